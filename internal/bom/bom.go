@@ -0,0 +1,263 @@
+// Package bom contains a format-agnostic representation of an SBOM document,
+// which is used to generate and process SBOMs locally without a round trip to
+// the Snyk API.
+package bom
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ComponentType string
+
+const (
+	ComponentTypeApplication ComponentType = "application"
+	ComponentTypeLibrary     ComponentType = "library"
+)
+
+// LifecyclePhases are the product lifecycle phases an SBOM can be created
+// in, as defined by CycloneDX.
+var LifecyclePhases = [...]string{"design", "pre-build", "build", "post-build", "operations", "discovery", "decommission"}
+
+type (
+	Document struct {
+		SerialNumber string
+		Timestamp    time.Time
+		Metadata     Metadata
+		Components   []*Component
+		Dependencies []*Dependency
+
+		// index maps bom-refs to the components and dependencies of the
+		// document, for lookups in documents of many components. See
+		// updateIndex.
+		index *index
+	}
+
+	index struct {
+		components   map[string]*Component
+		dependencies map[string]*Dependency
+		// dependsOn are the refs each dependency depends on, indexed once
+		// dependencies are added to it.
+		dependsOn map[*Dependency]*refSet
+		// nComponents and nDependencies are the number of components and
+		// dependencies indexed.
+		nComponents, nDependencies int
+	}
+
+	// refSet holds the first n refs of a dependency's DependsOn.
+	refSet struct {
+		refs map[string]struct{}
+		n    int
+	}
+
+	Metadata struct {
+		Tools        []*Tool
+		Authors      []string
+		Supplier     string
+		Manufacturer string
+		// Lifecycles are the phases of the product lifecycle the document was
+		// created in, one of LifecyclePhases.
+		Lifecycles []string
+		// VCS is the source revision the document was generated from, if
+		// known.
+		VCS        *VCS
+		Component  *Component
+		Properties []Property
+	}
+
+	// VCS identifies a revision in a version control system.
+	VCS struct {
+		URL    string
+		Commit string
+		Branch string
+		Dirty  bool
+	}
+
+	Tool struct {
+		Vendor  string
+		Name    string
+		Version string
+		// Service is set for tools that are services rather than software
+		// components, which CycloneDX 1.5+ lists separately.
+		Service bool
+	}
+
+	Component struct {
+		BOMRef string
+		Type   ComponentType
+		// Supplier is the organization that supplies the component.
+		Supplier string
+		Name     string
+		Version  string
+		PURL     string
+		// CPE is the Common Platform Enumeration name of the component, which
+		// identifies it in vulnerability databases.
+		CPE        string
+		Licenses   []License
+		Hashes     []Hash
+		Properties []Property
+	}
+
+	// License is either an SPDX license expression (a single SPDX license
+	// identifier being the simplest one) or, for licenses that have no SPDX
+	// identifier, the name of the license.
+	License struct {
+		Expression string
+		Name       string
+	}
+
+	// Hash is a checksum of a component. Algorithms are named as in
+	// CycloneDX (e.g. `SHA-256`), or as in SPDX for those CycloneDX does not
+	// support.
+	Hash struct {
+		Algorithm string
+		Value     string
+	}
+
+	Dependency struct {
+		Ref       string
+		DependsOn []string
+	}
+
+	Property struct {
+		Name  string
+		Value string
+	}
+
+	// Warning describes information that is lost when a document is encoded
+	// in a format that cannot represent it. BOMRef identifies the affected
+	// component, if any.
+	Warning struct {
+		BOMRef string
+		Msg    string
+	}
+)
+
+// Component returns the component (including the metadata component) with
+// the given bom-ref, or nil.
+func (d *Document) Component(ref string) *Component {
+	if d.Metadata.Component != nil && d.Metadata.Component.BOMRef == ref {
+		return d.Metadata.Component
+	}
+	return d.updateIndex().components[ref]
+}
+
+// AddComponent adds c to the document unless a component with the same
+// bom-ref is already present. It reports whether c was added.
+func (d *Document) AddComponent(c *Component) bool {
+	if d.Component(c.BOMRef) != nil {
+		return false
+	}
+	d.Components = append(d.Components, c)
+	d.index.components[c.BOMRef] = c
+	d.index.nComponents++
+	return true
+}
+
+// AddDependencies records that ref depends on each of dependsOn, merging
+// with any relationships already recorded for ref.
+func (d *Document) AddDependencies(ref string, dependsOn ...string) {
+	idx := d.updateIndex()
+	dep := idx.dependencies[ref]
+	if dep == nil {
+		dep = &Dependency{Ref: ref}
+		d.Dependencies = append(d.Dependencies, dep)
+		idx.dependencies[ref] = dep
+		idx.nDependencies++
+	}
+
+	set := idx.dependsOn[dep]
+	if set == nil || len(dep.DependsOn) < set.n {
+		set = &refSet{refs: make(map[string]struct{}, len(dep.DependsOn)+len(dependsOn))}
+		idx.dependsOn[dep] = set
+	}
+	for _, r := range dep.DependsOn[set.n:] {
+		set.refs[r] = struct{}{}
+	}
+	for _, r := range dependsOn {
+		if _, ok := set.refs[r]; !ok {
+			set.refs[r] = struct{}{}
+			dep.DependsOn = append(dep.DependsOn, r)
+		}
+	}
+	set.n = len(dep.DependsOn)
+}
+
+// updateIndex indexes the components and dependencies appended to the
+// document since it was last indexed, and returns the index. Components and
+// dependencies may be appended to directly; the index is rebuilt when any were
+// removed. The first of several components or dependencies with the same ref
+// is the one indexed.
+func (d *Document) updateIndex() *index {
+	idx := d.index
+	if idx == nil || len(d.Components) < idx.nComponents || len(d.Dependencies) < idx.nDependencies {
+		idx = &index{
+			components:   make(map[string]*Component, len(d.Components)),
+			dependencies: make(map[string]*Dependency, len(d.Dependencies)),
+			dependsOn:    make(map[*Dependency]*refSet),
+		}
+		d.index = idx
+	}
+
+	for _, c := range d.Components[idx.nComponents:] {
+		if _, ok := idx.components[c.BOMRef]; !ok {
+			idx.components[c.BOMRef] = c
+		}
+	}
+	idx.nComponents = len(d.Components)
+
+	for _, dep := range d.Dependencies[idx.nDependencies:] {
+		if _, ok := idx.dependencies[dep.Ref]; !ok {
+			idx.dependencies[dep.Ref] = dep
+		}
+	}
+	idx.nDependencies = len(d.Dependencies)
+
+	return idx
+}
+
+// Sort orders components, dependencies and properties canonically, so that
+// equal documents encode to the same bytes.
+func (d *Document) Sort() {
+	sortProperties(d.Metadata.Properties)
+	if d.Metadata.Component != nil {
+		sortProperties(d.Metadata.Component.Properties)
+	}
+	for _, c := range d.Components {
+		sortProperties(c.Properties)
+	}
+	slices.SortStableFunc(d.Components, func(a, b *Component) int {
+		return cmp.Or(cmp.Compare(a.BOMRef, b.BOMRef), cmp.Compare(a.Name, b.Name), cmp.Compare(a.Version, b.Version))
+	})
+	for _, dep := range d.Dependencies {
+		slices.Sort(dep.DependsOn)
+	}
+	slices.SortStableFunc(d.Dependencies, func(a, b *Dependency) int {
+		return cmp.Compare(a.Ref, b.Ref)
+	})
+}
+
+// serialNamespace is the namespace of content-derived serial numbers.
+var serialNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://snyk.io/sbom"))
+
+// ContentSerialNumber derives a serial number from the document's content
+// (excluding its current serial number), so that equal documents share it.
+func (d *Document) ContentSerialNumber() (string, error) {
+	content := *d
+	content.SerialNumber = ""
+	b, err := json.Marshal(&content)
+	if err != nil {
+		return "", err
+	}
+	return "urn:uuid:" + uuid.NewSHA1(serialNamespace, b).String(), nil
+}
+
+func sortProperties(props []Property) {
+	slices.SortStableFunc(props, func(a, b Property) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Value, b.Value))
+	})
+}
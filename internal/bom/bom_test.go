@@ -0,0 +1,101 @@
+package bom_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+func TestDocument_AddComponent(t *testing.T) {
+	doc := &bom.Document{
+		Metadata: bom.Metadata{Component: &bom.Component{BOMRef: "root@1.0.0"}},
+	}
+
+	assert.True(t, doc.AddComponent(&bom.Component{BOMRef: "a@1.0.0"}))
+	assert.False(t, doc.AddComponent(&bom.Component{BOMRef: "a@1.0.0"}), "duplicate components are not added")
+	assert.False(t, doc.AddComponent(&bom.Component{BOMRef: "root@1.0.0"}), "the metadata component is not duplicated")
+	assert.Len(t, doc.Components, 1)
+}
+
+func TestDocument_AddDependencies(t *testing.T) {
+	doc := &bom.Document{}
+
+	doc.AddDependencies("a", "b", "c")
+	doc.AddDependencies("a", "c", "d")
+	doc.AddDependencies("b")
+
+	assert.Equal(t, []*bom.Dependency{
+		{Ref: "a", DependsOn: []string{"b", "c", "d"}},
+		{Ref: "b"},
+	}, doc.Dependencies)
+}
+
+func TestDocument_LookupsFollowDirectChanges(t *testing.T) {
+	doc := &bom.Document{}
+	doc.AddComponent(&bom.Component{BOMRef: "a@1.0.0"})
+	doc.AddDependencies("a@1.0.0", "b@1.0.0")
+
+	doc.Components = append(doc.Components, &bom.Component{BOMRef: "b@1.0.0"})
+	doc.Dependencies[0].DependsOn = append(doc.Dependencies[0].DependsOn, "c@1.0.0")
+	doc.AddDependencies("a@1.0.0", "c@1.0.0", "d@1.0.0")
+
+	assert.NotNil(t, doc.Component("b@1.0.0"), "appended components are found")
+	assert.False(t, doc.AddComponent(&bom.Component{BOMRef: "b@1.0.0"}))
+	assert.Equal(t, []string{"b@1.0.0", "c@1.0.0", "d@1.0.0"}, doc.Dependencies[0].DependsOn)
+
+	doc.Components = doc.Components[1:]
+	assert.Nil(t, doc.Component("a@1.0.0"), "removed components are not found")
+	assert.True(t, doc.AddComponent(&bom.Component{BOMRef: "a@1.0.0"}))
+}
+
+func TestDocument_Sort(t *testing.T) {
+	doc := &bom.Document{
+		Metadata: bom.Metadata{Properties: []bom.Property{{Name: "b", Value: "2"}, {Name: "a", Value: "1"}}},
+		Components: []*bom.Component{
+			{BOMRef: "ws@1.0.0"},
+			{BOMRef: "express@4.4.0"},
+		},
+		Dependencies: []*bom.Dependency{
+			{Ref: "root", DependsOn: []string{"ws@1.0.0", "express@4.4.0"}},
+			{Ref: "express@4.4.0"},
+		},
+	}
+
+	doc.Sort()
+
+	assert.Equal(t, []bom.Property{{Name: "a", Value: "1"}, {Name: "b", Value: "2"}}, doc.Metadata.Properties)
+	assert.Equal(t, "express@4.4.0", doc.Components[0].BOMRef)
+	assert.Equal(t, "ws@1.0.0", doc.Components[1].BOMRef)
+	assert.Equal(t, []*bom.Dependency{
+		{Ref: "express@4.4.0"},
+		{Ref: "root", DependsOn: []string{"express@4.4.0", "ws@1.0.0"}},
+	}, doc.Dependencies)
+}
+
+func TestDocument_ContentSerialNumber(t *testing.T) {
+	newDoc := func(serial string) *bom.Document {
+		return &bom.Document{
+			SerialNumber: serial,
+			Timestamp:    time.Unix(1700000000, 0).UTC(),
+			Components:   []*bom.Component{{BOMRef: "express@4.4.0", Name: "express", Version: "4.4.0"}},
+		}
+	}
+
+	a, err := newDoc("urn:uuid:1").ContentSerialNumber()
+	require.NoError(t, err)
+	b, err := newDoc("urn:uuid:2").ContentSerialNumber()
+	require.NoError(t, err)
+
+	assert.Equal(t, a, b, "the serial number does not depend on the previous one")
+	assert.Regexp(t, `^urn:uuid:[0-9a-f-]{36}$`, a)
+
+	other := newDoc("")
+	other.Components[0].Version = "4.4.1"
+	c, err := other.ContentSerialNumber()
+	require.NoError(t, err)
+	assert.NotEqual(t, a, c)
+}
@@ -0,0 +1,166 @@
+// Package cyclonedx encodes bom documents as CycloneDX JSON and XML, and
+// decodes CycloneDX JSON documents.
+package cyclonedx
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+const (
+	MIMETypeJSON = "application/vnd.cyclonedx+json"
+	MIMETypeXML  = "application/vnd.cyclonedx+xml"
+
+	SpecVersion1_4 = "1.4"
+	SpecVersion1_5 = "1.5"
+	SpecVersion1_6 = "1.6"
+
+	// PropertyLifecycle records a lifecycle phase in CycloneDX 1.4, which
+	// has no metadata.lifecycles.
+	PropertyLifecycle = "snyk:lifecycle"
+
+	// Properties of the metadata component recording the source revision the
+	// document was generated from.
+	PropertyGitCommit = "snyk:git:commit"
+	PropertyGitBranch = "snyk:git:branch"
+	PropertyGitDirty  = "snyk:git:dirty"
+
+	externalReferenceTypeVCS = "vcs"
+
+	bomFormat   = "CycloneDX"
+	bomVersion  = 1
+	xmlnsPrefix = "http://cyclonedx.org/schema/bom/"
+)
+
+var specVersions = [...]string{SpecVersion1_4, SpecVersion1_5, SpecVersion1_6}
+
+// componentTypes are the component types CycloneDX 1.4 defines. Later
+// versions add componentTypes1_5.
+var (
+	componentTypes = [...]string{
+		"application", "framework", "library", "container", "operating-system", "device", "firmware", "file",
+	}
+	componentTypes1_5 = [...]string{"platform", "device-driver", "machine-learning-model", "data"}
+)
+
+// hashAlgorithms are the hash algorithms CycloneDX defines.
+var hashAlgorithms = [...]string{
+	"MD5", "SHA-1", "SHA-256", "SHA-384", "SHA-512", "SHA3-256", "SHA3-384", "SHA3-512",
+	"BLAKE2b-256", "BLAKE2b-384", "BLAKE2b-512", "BLAKE3",
+}
+
+// IsSupportedSpecVersion reports whether v is a CycloneDX spec version this
+// package can encode.
+func IsSupportedSpecVersion(v string) bool {
+	for _, sv := range specVersions {
+		if sv == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Encode renders doc as a CycloneDX document of the given spec version and
+// encoding (JSON or XML). It returns the document and its MIME type.
+func Encode(doc *bom.Document, specVersion, encoding string) (b []byte, mimeType string, err error) {
+	if !IsSupportedSpecVersion(specVersion) {
+		return nil, "", fmt.Errorf("unsupported CycloneDX spec version %q", specVersion)
+	}
+
+	switch encoding {
+	case bom.EncodingJSON:
+		b, err = encodeJSON(doc, specVersion)
+		return b, MIMETypeJSON, err
+	case bom.EncodingXML:
+		b, err = encodeXML(doc, specVersion)
+		return b, MIMETypeXML, err
+	default:
+		return nil, "", fmt.Errorf("unsupported CycloneDX encoding %q", encoding)
+	}
+}
+
+// hasToolsObject reports whether the spec version expresses metadata.tools
+// as an object of components rather than the (deprecated) list of tools.
+func hasToolsObject(specVersion string) bool {
+	return specVersion != SpecVersion1_4
+}
+
+// hasLifecycles reports whether the spec version supports metadata.lifecycles.
+// Older versions record lifecycles as properties.
+func hasLifecycles(specVersion string) bool {
+	return specVersion != SpecVersion1_4
+}
+
+// hasManufacturer reports whether the spec version names the manufacturer
+// metadata.manufacturer instead of the deprecated metadata.manufacture.
+func hasManufacturer(specVersion string) bool {
+	return specVersion == SpecVersion1_6
+}
+
+// metadataProperties returns the document properties, including those that
+// stand in for metadata the spec version has no field for.
+func metadataProperties(m *bom.Metadata, specVersion string) []bom.Property {
+	if hasLifecycles(specVersion) || len(m.Lifecycles) == 0 {
+		return m.Properties
+	}
+	props := make([]bom.Property, 0, len(m.Properties)+len(m.Lifecycles))
+	for _, l := range m.Lifecycles {
+		props = append(props, bom.Property{Name: PropertyLifecycle, Value: l})
+	}
+	return append(props, m.Properties...)
+}
+
+// vcsProperties returns the properties recording the source revision, which
+// are added to the metadata component, or to the document if it has none.
+func vcsProperties(v *bom.VCS) []bom.Property {
+	props := []bom.Property{{Name: PropertyGitCommit, Value: v.Commit}}
+	if v.Branch != "" {
+		props = append(props, bom.Property{Name: PropertyGitBranch, Value: v.Branch})
+	}
+	return append(props, bom.Property{Name: PropertyGitDirty, Value: strconv.FormatBool(v.Dirty)})
+}
+
+// componentType returns the type of a component, or library if the spec
+// version does not define it.
+func componentType(t bom.ComponentType, specVersion string) string {
+	if slices.Contains(componentTypes[:], string(t)) ||
+		(specVersion != SpecVersion1_4 && slices.Contains(componentTypes1_5[:], string(t))) {
+		return string(t)
+	}
+	return string(bom.ComponentTypeLibrary)
+}
+
+// supportedHashes returns the hashes whose algorithm CycloneDX defines.
+func supportedHashes(hashes []bom.Hash) []bom.Hash {
+	var out []bom.Hash
+	for _, h := range hashes {
+		if slices.Contains(hashAlgorithms[:], h.Algorithm) {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// splitLicenses returns how licenses are written: either as a list of
+// licenses identified by SPDX identifier or name, or, if any of them is a
+// compound expression, as a single expression. CycloneDX allows no mix of
+// both, so licenses that only have a name are dropped in the latter case.
+func splitLicenses(licenses []bom.License) (named []bom.License, expression string) {
+	for _, l := range licenses {
+		if l.Expression != "" && !l.IsSPDXID() {
+			return nil, bom.LicenseExpression(licenses)
+		}
+	}
+	return licenses, ""
+}
+
+func formatTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
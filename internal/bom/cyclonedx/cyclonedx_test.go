@@ -0,0 +1,150 @@
+package cyclonedx_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bradleyjkemp/cupaloy/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/bom/cyclonedx"
+)
+
+var snapshotter = cupaloy.New(cupaloy.SnapshotSubdirectory("testdata/snapshots"))
+
+func newTestDocument() *bom.Document {
+	return &bom.Document{
+		SerialNumber: "urn:uuid:1b4e28ba-2fa1-4d3b-a3f5-ef19b5a7633b",
+		Timestamp:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Metadata: bom.Metadata{
+			Tools: []*bom.Tool{{Vendor: "Snyk", Name: "snyk-cli", Version: "1.2.3"}},
+			Component: &bom.Component{
+				BOMRef:  "goof@1.0.0",
+				Type:    bom.ComponentTypeApplication,
+				Name:    "goof",
+				Version: "1.0.0",
+				PURL:    "pkg:npm/goof@1.0.0",
+			},
+			Properties: []bom.Property{{Name: "snyk:scan_error", Value: "project/pom.xml: missing lockfile"}},
+		},
+		Components: []*bom.Component{
+			{BOMRef: "express@4.4.0", Type: bom.ComponentTypeLibrary, Name: "express", Version: "4.4.0", PURL: "pkg:npm/express@4.4.0"},
+			{BOMRef: "ws@1.0.0", Type: bom.ComponentTypeLibrary, Name: "ws", Version: "1.0.0", PURL: "pkg:npm/ws@1.0.0"},
+		},
+		Dependencies: []*bom.Dependency{
+			{Ref: "goof@1.0.0", DependsOn: []string{"express@4.4.0"}},
+			{Ref: "express@4.4.0", DependsOn: []string{"ws@1.0.0"}},
+			{Ref: "ws@1.0.0"},
+		},
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tc := []struct {
+		specVersion      string
+		encoding         string
+		expectedMIMEType string
+	}{
+		{specVersion: "1.4", encoding: "json", expectedMIMEType: cyclonedx.MIMETypeJSON},
+		{specVersion: "1.4", encoding: "xml", expectedMIMEType: cyclonedx.MIMETypeXML},
+		{specVersion: "1.5", encoding: "json", expectedMIMEType: cyclonedx.MIMETypeJSON},
+		{specVersion: "1.5", encoding: "xml", expectedMIMEType: cyclonedx.MIMETypeXML},
+		{specVersion: "1.6", encoding: "json", expectedMIMEType: cyclonedx.MIMETypeJSON},
+		{specVersion: "1.6", encoding: "xml", expectedMIMEType: cyclonedx.MIMETypeXML},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.specVersion+"+"+tt.encoding, func(t *testing.T) {
+			b, mimeType, err := cyclonedx.Encode(newTestDocument(), tt.specVersion, tt.encoding)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedMIMEType, mimeType)
+			snapshotter.SnapshotT(t, string(b))
+		})
+	}
+}
+
+func TestEncode_Metadata(t *testing.T) {
+	doc := newTestDocument()
+	doc.Metadata.Supplier = "ACME"
+	doc.Metadata.Authors = []string{"Jane Doe", "John Doe"}
+	doc.Metadata.Manufacturer = "ACME Corp"
+	doc.Metadata.Lifecycles = []string{"build", "post-build"}
+
+	for _, specVersion := range []string{"1.4", "1.5", "1.6"} {
+		for _, encoding := range []string{"json", "xml"} {
+			t.Run(specVersion+"+"+encoding, func(t *testing.T) {
+				b, _, err := cyclonedx.Encode(doc, specVersion, encoding)
+
+				require.NoError(t, err)
+				snapshotter.SnapshotT(t, string(b))
+			})
+		}
+	}
+}
+
+func TestEncode_VCS(t *testing.T) {
+	doc := newTestDocument()
+	doc.Metadata.VCS = &bom.VCS{
+		URL:    "https://github.com/snyk/goof",
+		Commit: "4b825dc642cb6eb9a060e54bf8d69288fbee4904",
+		Branch: "main",
+		Dirty:  true,
+	}
+
+	for _, encoding := range []string{"json", "xml"} {
+		t.Run(encoding, func(t *testing.T) {
+			b, _, err := cyclonedx.Encode(doc, "1.6", encoding)
+
+			require.NoError(t, err)
+			snapshotter.SnapshotT(t, string(b))
+		})
+	}
+}
+
+func TestEncode_LicensesAndHashes(t *testing.T) {
+	doc := newTestDocument()
+	doc.Components[0].Licenses = []bom.License{{Expression: "MIT"}, {Name: "ACME Commercial License"}}
+	doc.Components[0].Hashes = []bom.Hash{
+		{Algorithm: "SHA-256", Value: "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"},
+		{Algorithm: "MD2", Value: "d9cce882ee690a5c1ce70beff3a78c77"},
+	}
+	doc.Components[1].Licenses = []bom.License{{Expression: "MIT OR Apache-2.0"}}
+
+	for _, encoding := range []string{"json", "xml"} {
+		t.Run(encoding, func(t *testing.T) {
+			b, _, err := cyclonedx.Encode(doc, "1.6", encoding)
+
+			require.NoError(t, err)
+			snapshotter.SnapshotT(t, string(b))
+		})
+	}
+}
+
+func TestEncode_UnsupportedComponentType(t *testing.T) {
+	doc := newTestDocument()
+	doc.Components[0].Type = "platform"
+
+	for specVersion, expected := range map[string]string{"1.4": `"type":"library"`, "1.5": `"type":"platform"`} {
+		t.Run(specVersion, func(t *testing.T) {
+			b, _, err := cyclonedx.Encode(doc, specVersion, "json")
+
+			require.NoError(t, err)
+			assert.Contains(t, string(b), expected)
+		})
+	}
+}
+
+func TestEncode_UnsupportedSpecVersion(t *testing.T) {
+	_, _, err := cyclonedx.Encode(newTestDocument(), "1.3", "json")
+
+	assert.ErrorContains(t, err, `unsupported CycloneDX spec version "1.3"`)
+}
+
+func TestEncode_UnsupportedEncoding(t *testing.T) {
+	_, _, err := cyclonedx.Encode(newTestDocument(), "1.4", "yaml")
+
+	assert.ErrorContains(t, err, `unsupported CycloneDX encoding "yaml"`)
+}
@@ -0,0 +1,196 @@
+package cyclonedx
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+// Decode reads a CycloneDX JSON or XML document. Nested components are
+// flattened into the document's components, and components without a bom-ref
+// are assigned one derived from their purl or name and version.
+func Decode(b []byte) (*bom.Document, error) {
+	if isXML(b) {
+		in, err := decodeXML(b)
+		if err != nil {
+			return nil, err
+		}
+		return decodeBOM(in), nil
+	}
+
+	var in jsonBOM
+	if err := json.Unmarshal(b, &in); err != nil {
+		return nil, fmt.Errorf("failed to decode CycloneDX document: %w", err)
+	}
+	if in.BOMFormat != bomFormat {
+		return nil, fmt.Errorf("not a CycloneDX document (bomFormat %q)", in.BOMFormat)
+	}
+	return decodeBOM(&in), nil
+}
+
+func decodeBOM(in *jsonBOM) *bom.Document {
+	doc := &bom.Document{SerialNumber: in.SerialNumber}
+
+	if m := in.Metadata; m != nil {
+		decodeMetadata(m, &doc.Metadata)
+		if doc.Metadata.Component == nil {
+			doc.Metadata.Properties, doc.Metadata.VCS = splitVCS(doc.Metadata.Properties, in.ExternalReferences)
+		}
+		// Timestamps that do not parse are dropped rather than failing the
+		// whole document.
+		doc.Timestamp, _ = time.Parse(time.RFC3339, m.Timestamp) //nolint:errcheck // See above
+	}
+
+	var addComponents func(cs []jsonComponent)
+	addComponents = func(cs []jsonComponent) {
+		for i := range cs {
+			doc.AddComponent(fromJSONComponent(&cs[i]))
+			addComponents(cs[i].Components)
+		}
+	}
+	addComponents(in.Components)
+
+	for _, d := range in.Dependencies {
+		doc.AddDependencies(d.Ref, d.DependsOn...)
+	}
+
+	return doc
+}
+
+func decodeMetadata(in *jsonMetadata, m *bom.Metadata) {
+	if t := in.Tools; t != nil {
+		for _, tool := range t.Legacy {
+			m.Tools = append(m.Tools, &bom.Tool{Vendor: tool.Vendor, Name: tool.Name, Version: tool.Version})
+		}
+		for _, c := range t.Components {
+			tool := &bom.Tool{Name: c.Name, Version: c.Version}
+			if c.Supplier != nil {
+				tool.Vendor = c.Supplier.Name
+			}
+			m.Tools = append(m.Tools, tool)
+		}
+		for _, s := range t.Services {
+			tool := &bom.Tool{Name: s.Name, Version: s.Version, Service: true}
+			if s.Provider != nil {
+				tool.Vendor = s.Provider.Name
+			}
+			m.Tools = append(m.Tools, tool)
+		}
+	}
+
+	for _, a := range in.Authors {
+		m.Authors = append(m.Authors, a.Name)
+	}
+	if in.Supplier != nil {
+		m.Supplier = in.Supplier.Name
+	}
+	if in.Manufacturer != nil {
+		m.Manufacturer = in.Manufacturer.Name
+	} else if in.Manufacture != nil {
+		m.Manufacturer = in.Manufacture.Name
+	}
+	for _, l := range in.Lifecycles {
+		m.Lifecycles = append(m.Lifecycles, l.Phase)
+	}
+
+	for _, p := range in.Properties {
+		if p.Name == PropertyLifecycle {
+			m.Lifecycles = append(m.Lifecycles, p.Value)
+			continue
+		}
+		m.Properties = append(m.Properties, bom.Property{Name: p.Name, Value: p.Value})
+	}
+
+	if in.Component != nil {
+		m.Component, m.VCS = decodeMetadataComponent(in.Component)
+	}
+}
+
+// decodeMetadataComponent reads the metadata component, separating the
+// source revision recorded by Encode from its other properties.
+func decodeMetadataComponent(in *jsonComponent) (*bom.Component, *bom.VCS) {
+	c := fromJSONComponent(in)
+	var vcs *bom.VCS
+	c.Properties, vcs = splitVCS(c.Properties, in.ExternalReferences)
+	return c, vcs
+}
+
+// splitVCS separates the properties recording the source revision from the
+// other properties, and returns the revision, whose URL is that of the VCS
+// external reference. The revision is nil if none is recorded.
+func splitVCS(in []bom.Property, refs []jsonExternalReference) ([]bom.Property, *bom.VCS) {
+	var vcs bom.VCS
+	props := in[:0]
+	for _, p := range in {
+		switch p.Name {
+		case PropertyGitCommit:
+			vcs.Commit = p.Value
+		case PropertyGitBranch:
+			vcs.Branch = p.Value
+		case PropertyGitDirty:
+			vcs.Dirty, _ = strconv.ParseBool(p.Value) //nolint:errcheck // Anything but "true" is clean
+		default:
+			props = append(props, p)
+		}
+	}
+	if len(props) == 0 {
+		props = nil
+	}
+
+	if vcs.Commit == "" {
+		return props, nil
+	}
+	for _, r := range refs {
+		if r.Type == externalReferenceTypeVCS {
+			vcs.URL = r.URL
+			break
+		}
+	}
+	return props, &vcs
+}
+
+func fromJSONComponent(in *jsonComponent) *bom.Component {
+	c := &bom.Component{
+		BOMRef:  in.BOMRef,
+		Type:    bom.ComponentType(in.Type),
+		Name:    in.Name,
+		Version: in.Version,
+		PURL:    in.PURL,
+		CPE:     in.CPE,
+	}
+	if in.Supplier != nil {
+		c.Supplier = in.Supplier.Name
+	}
+	for _, h := range in.Hashes {
+		c.Hashes = append(c.Hashes, bom.Hash{Algorithm: h.Alg, Value: h.Content})
+	}
+	for _, l := range in.Licenses {
+		switch {
+		case l.Expression != "":
+			c.Licenses = append(c.Licenses, bom.License{Expression: l.Expression})
+		case l.License != nil:
+			c.Licenses = append(c.Licenses, bom.License{Expression: l.License.ID, Name: l.License.Name})
+		}
+	}
+	for _, p := range in.Properties {
+		c.Properties = append(c.Properties, bom.Property{Name: p.Name, Value: p.Value})
+	}
+	if c.BOMRef == "" {
+		c.BOMRef = defaultBOMRef(c)
+	}
+	return c
+}
+
+// defaultBOMRef identifies a component that has no bom-ref.
+func defaultBOMRef(c *bom.Component) string {
+	if c.PURL != "" {
+		return c.PURL
+	}
+	if c.Version == "" {
+		return c.Name
+	}
+	return c.Name + "@" + c.Version
+}
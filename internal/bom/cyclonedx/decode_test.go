@@ -0,0 +1,174 @@
+package cyclonedx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/bom/cyclonedx"
+)
+
+func TestDecode_RoundTrip(t *testing.T) {
+	for _, tc := range []struct{ specVersion, encoding string }{
+		{"1.4", "json"}, {"1.5", "json"}, {"1.6", "json"},
+		{"1.4", "xml"}, {"1.5", "xml"}, {"1.6", "xml"},
+	} {
+		specVersion := tc.specVersion
+		t.Run(specVersion+"+"+tc.encoding, func(t *testing.T) {
+			doc := newTestDocument()
+			doc.Metadata.Authors = []string{"Jane Doe"}
+			doc.Metadata.Supplier = "ACME"
+			doc.Metadata.Manufacturer = "ACME Manufacturing"
+			doc.Metadata.Lifecycles = []string{"build"}
+			doc.Metadata.VCS = &bom.VCS{URL: "https://github.com/snyk/goof", Commit: "4b825dc6", Branch: "main", Dirty: true}
+			doc.Components[0].Supplier = "Express Foundation"
+			doc.Components[0].CPE = "cpe:2.3:a:expressjs:express:4.4.0:*:*:*:*:node.js:*:*"
+			doc.Components[0].Licenses = []bom.License{{Expression: "MIT"}, {Name: "ACME Commercial License"}}
+			doc.Components[0].Hashes = []bom.Hash{{Algorithm: "SHA-256", Value: "2c26b46b68ffc68f"}}
+			doc.Components[1].Licenses = []bom.License{{Expression: "MIT OR Apache-2.0"}}
+
+			b, _, err := cyclonedx.Encode(doc, specVersion, tc.encoding)
+			require.NoError(t, err)
+
+			decoded, err := cyclonedx.Decode(b)
+
+			require.NoError(t, err)
+			assertEqualDocuments(t, doc, decoded)
+		})
+	}
+}
+
+func TestDecode_VCSWithoutMetadataComponent(t *testing.T) {
+	for _, encoding := range []string{"json", "xml"} {
+		t.Run(encoding, func(t *testing.T) {
+			doc := newTestDocument()
+			doc.Metadata.Component = nil
+			doc.Metadata.VCS = &bom.VCS{URL: "https://github.com/snyk/goof", Commit: "4b825dc6", Branch: "main"}
+
+			b, _, err := cyclonedx.Encode(doc, "1.6", encoding)
+			require.NoError(t, err)
+			assert.Contains(t, string(b), "https://github.com/snyk/goof")
+
+			decoded, err := cyclonedx.Decode(b)
+
+			require.NoError(t, err)
+			assert.Equal(t, doc.Metadata.VCS, decoded.Metadata.VCS)
+			assert.Equal(t, doc.Metadata.Properties, decoded.Metadata.Properties)
+			assert.Empty(t, cyclonedx.DroppedFields(b))
+		})
+	}
+}
+
+func TestDecode_ToolServices(t *testing.T) {
+	for _, encoding := range []string{"json", "xml"} {
+		t.Run(encoding, func(t *testing.T) {
+			doc := newTestDocument()
+			doc.Metadata.Tools = append(doc.Metadata.Tools, &bom.Tool{Vendor: "ACME", Name: "scanner", Version: "2.0.0", Service: true})
+
+			b, _, err := cyclonedx.Encode(doc, "1.6", encoding)
+			require.NoError(t, err)
+
+			decoded, err := cyclonedx.Decode(b)
+
+			require.NoError(t, err)
+			assert.Equal(t, doc.Metadata.Tools, decoded.Metadata.Tools)
+		})
+	}
+}
+
+func TestDecode_LegacyXMLTools(t *testing.T) {
+	doc, err := cyclonedx.Decode([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.4" version="1">
+  <metadata>
+    <tools>
+      <tool><vendor>Snyk</vendor><name>snyk-cli</name><version>1.2.3</version></tool>
+    </tools>
+  </metadata>
+  <components>
+    <component type="library">
+      <name>express</name>
+      <version>4.4.0</version>
+      <components>
+        <component type="library"><name>router</name><version>1.0.0</version></component>
+      </components>
+    </component>
+  </components>
+</bom>`))
+
+	require.NoError(t, err)
+	assert.Equal(t, []*bom.Tool{{Vendor: "Snyk", Name: "snyk-cli", Version: "1.2.3"}}, doc.Metadata.Tools)
+	assert.Equal(t, []*bom.Component{
+		{BOMRef: "express@4.4.0", Type: bom.ComponentTypeLibrary, Name: "express", Version: "4.4.0"},
+		{BOMRef: "router@1.0.0", Type: bom.ComponentTypeLibrary, Name: "router", Version: "1.0.0"},
+	}, doc.Components)
+}
+
+func TestXMLSpecVersion(t *testing.T) {
+	specVersion, ok := cyclonedx.XMLSpecVersion([]byte(`<?xml version="1.0"?><bom xmlns="http://cyclonedx.org/schema/bom/1.5"></bom>`))
+	assert.True(t, ok)
+	assert.Equal(t, "1.5", specVersion)
+
+	_, ok = cyclonedx.XMLSpecVersion([]byte(`<?xml version="1.0"?><project xmlns="http://maven.apache.org/POM/4.0.0"></project>`))
+	assert.False(t, ok)
+
+	_, ok = cyclonedx.XMLSpecVersion([]byte(`{"bomFormat":"CycloneDX"}`))
+	assert.False(t, ok)
+}
+
+func TestDecode_NestedComponents(t *testing.T) {
+	doc, err := cyclonedx.Decode([]byte(`{
+		"bomFormat": "CycloneDX",
+		"specVersion": "1.5",
+		"components": [
+			{"type": "library", "name": "express", "version": "4.4.0", "purl": "pkg:npm/express@4.4.0", "components": [
+				{"type": "library", "name": "router", "version": "1.0.0"}
+			]}
+		]
+	}`))
+
+	require.NoError(t, err)
+	assert.Equal(t, []*bom.Component{
+		{BOMRef: "pkg:npm/express@4.4.0", Type: bom.ComponentTypeLibrary, Name: "express", Version: "4.4.0", PURL: "pkg:npm/express@4.4.0"},
+		{BOMRef: "router@1.0.0", Type: bom.ComponentTypeLibrary, Name: "router", Version: "1.0.0"},
+	}, doc.Components)
+}
+
+func TestDecode_NotCycloneDX(t *testing.T) {
+	_, err := cyclonedx.Decode([]byte(`{"spdxVersion":"SPDX-2.3"}`))
+
+	assert.ErrorContains(t, err, "not a CycloneDX document")
+}
+
+func TestXMLToJSON(t *testing.T) {
+	doc := newTestDocument()
+	b, _, err := cyclonedx.Encode(doc, "1.5", "xml")
+	require.NoError(t, err)
+
+	converted, err := cyclonedx.XMLToJSON(b)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(converted), `"bomFormat":"CycloneDX"`)
+	assert.Contains(t, string(converted), `"specVersion":"1.5"`)
+	decoded, err := cyclonedx.Decode(converted)
+	require.NoError(t, err)
+	assertEqualDocuments(t, doc, decoded)
+}
+
+// assertEqualDocuments compares the content of documents, leaving out the
+// indexes built by looking components and dependencies up.
+func assertEqualDocuments(t *testing.T, want, got *bom.Document) {
+	t.Helper()
+	assert.Equal(t, want.SerialNumber, got.SerialNumber)
+	assert.Equal(t, want.Timestamp, got.Timestamp)
+	assert.Equal(t, want.Metadata, got.Metadata)
+	assert.Equal(t, want.Components, got.Components)
+	assert.Equal(t, want.Dependencies, got.Dependencies)
+}
+
+func TestXMLToJSON_NotCycloneDX(t *testing.T) {
+	_, err := cyclonedx.XMLToJSON([]byte(`<?xml version="1.0"?><project xmlns="http://maven.apache.org/POM/4.0.0"></project>`))
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,201 @@
+package cyclonedx
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+// decodedFields lists, per object, the fields Decode reads. The objects are
+// named by their path in the JSON document.
+var decodedFields = map[string][]string{
+	"": {"$schema", "bomFormat", "specVersion", "serialNumber", "version", "metadata", "components", "externalReferences", "dependencies"},
+	"metadata": {
+		"timestamp", "lifecycles", "tools", "authors", "component", "manufacture", "manufacturer", "supplier", "properties",
+	},
+	"metadata.component": {
+		"bom-ref", "type", "supplier", "name", "version", "cpe", "purl", "hashes", "licenses", "externalReferences", "properties",
+	},
+	"components[]": {"bom-ref", "type", "supplier", "name", "version", "cpe", "purl", "hashes", "licenses", "properties", "components"},
+}
+
+// convertedXMLFields lists, per object, the fields XMLToJSON keeps, which are
+// those Decode reads and the external references of components.
+var convertedXMLFields = func() map[string][]string {
+	fields := maps.Clone(decodedFields)
+	fields["components[]"] = append(slices.Clone(fields["components[]"]), "externalReferences")
+	return fields
+}()
+
+// DroppedFields reports the fields of a CycloneDX JSON or XML document that
+// Decode does not read, and which are therefore lost when the document is
+// converted. Each field is reported once, with the number of occurrences.
+func DroppedFields(b []byte) []bom.Warning {
+	if isXML(b) {
+		return droppedFieldWarnings(droppedXMLFields(b, decodedFields))
+	}
+	return droppedFieldWarnings(droppedJSONFields(b))
+}
+
+// DroppedXMLFields reports the fields of a CycloneDX XML document that
+// XMLToJSON does not keep, like DroppedFields.
+func DroppedXMLFields(b []byte) []bom.Warning {
+	return droppedFieldWarnings(droppedXMLFields(b, convertedXMLFields))
+}
+
+func droppedFieldWarnings(counts map[string]int) []bom.Warning {
+	paths := make([]string, 0, len(counts))
+	for p := range counts {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+
+	warnings := make([]bom.Warning, 0, len(paths))
+	for _, p := range paths {
+		msg := fmt.Sprintf("field %s is not supported and was dropped", p)
+		if n := counts[p]; n > 1 {
+			msg += fmt.Sprintf(" (%d occurrences)", n)
+		}
+		warnings = append(warnings, bom.Warning{Msg: msg})
+	}
+	return warnings
+}
+
+// fieldCounter counts the fields of objects that are not kept.
+type fieldCounter struct {
+	kept   map[string][]string
+	counts map[string]int
+}
+
+func newFieldCounter(kept map[string][]string) *fieldCounter {
+	return &fieldCounter{kept: kept, counts: map[string]int{}}
+}
+
+func (c *fieldCounter) check(object string, fields []string) {
+	for _, f := range fields {
+		if !slices.Contains(c.kept[object], f) {
+			c.counts[joinPath(object, f)]++
+		}
+	}
+}
+
+func joinPath(object, field string) string {
+	if object == "" {
+		return field
+	}
+	return object + "." + field
+}
+
+func droppedJSONFields(b []byte) map[string]int {
+	type object = map[string]json.RawMessage
+	counts := newFieldCounter(decodedFields)
+
+	var root object
+	if err := json.Unmarshal(b, &root); err != nil {
+		return nil
+	}
+	counts.check("", keys(root))
+
+	var metadata object
+	if json.Unmarshal(root["metadata"], &metadata) == nil {
+		counts.check("metadata", keys(metadata))
+
+		var component object
+		if json.Unmarshal(metadata["component"], &component) == nil {
+			counts.check("metadata.component", keys(component))
+		}
+	}
+
+	var checkComponents func(raw json.RawMessage)
+	checkComponents = func(raw json.RawMessage) {
+		var components []object
+		if json.Unmarshal(raw, &components) != nil {
+			return
+		}
+		for _, c := range components {
+			counts.check("components[]", keys(c))
+			checkComponents(c["components"])
+		}
+	}
+	checkComponents(root["components"])
+
+	return counts.counts
+}
+
+// xmlNode is an XML element, of which only the names of the child elements
+// are of interest.
+type xmlNode struct {
+	Nodes []xmlNode `xml:",any"`
+	name  string
+}
+
+func (n *xmlNode) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type node xmlNode
+	if err := d.DecodeElement((*node)(n), &start); err != nil {
+		return err
+	}
+	n.name = start.Name.Local
+	return nil
+}
+
+func (n *xmlNode) child(name string) *xmlNode {
+	for i := range n.Nodes {
+		if n.Nodes[i].name == name {
+			return &n.Nodes[i]
+		}
+	}
+	return nil
+}
+
+func (n *xmlNode) childNames() []string {
+	names := make([]string, 0, len(n.Nodes))
+	for _, c := range n.Nodes {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+func droppedXMLFields(b []byte, kept map[string][]string) map[string]int {
+	counts := newFieldCounter(kept)
+
+	var root xmlNode
+	if err := xml.NewDecoder(bytes.NewReader(b)).Decode(&root); err != nil {
+		return nil
+	}
+	counts.check("", root.childNames())
+
+	if metadata := root.child("metadata"); metadata != nil {
+		counts.check("metadata", metadata.childNames())
+		if component := metadata.child("component"); component != nil {
+			counts.check("metadata.component", component.childNames())
+		}
+	}
+
+	var checkComponents func(components *xmlNode)
+	checkComponents = func(components *xmlNode) {
+		if components == nil {
+			return
+		}
+		for i := range components.Nodes {
+			c := &components.Nodes[i]
+			counts.check("components[]", c.childNames())
+			checkComponents(c.child("components"))
+		}
+	}
+	checkComponents(root.child("components"))
+
+	return counts.counts
+}
+
+func keys(o map[string]json.RawMessage) []string {
+	out := make([]string, 0, len(o))
+	for k := range o {
+		out = append(out, k)
+	}
+	return out
+}
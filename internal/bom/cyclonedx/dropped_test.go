@@ -0,0 +1,75 @@
+package cyclonedx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/bom/cyclonedx"
+)
+
+func TestDroppedFields_JSON(t *testing.T) {
+	warnings := cyclonedx.DroppedFields([]byte(`{
+		"bomFormat": "CycloneDX",
+		"specVersion": "1.6",
+		"metadata": {"component": {"type": "application", "name": "goof", "description": "demo"}},
+		"components": [
+			{"type": "library", "name": "express", "scope": "required", "components": [
+				{"type": "library", "name": "router", "scope": "optional"}
+			]}
+		],
+		"vulnerabilities": []
+	}`))
+
+	assert.Equal(t, []bom.Warning{
+		{Msg: "field components[].scope is not supported and was dropped (2 occurrences)"},
+		{Msg: "field metadata.component.description is not supported and was dropped"},
+		{Msg: "field vulnerabilities is not supported and was dropped"},
+	}, warnings)
+}
+
+func TestDroppedFields_XML(t *testing.T) {
+	warnings := cyclonedx.DroppedFields([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.5" version="1">
+  <metadata>
+    <licenses><license><id>MIT</id></license></licenses>
+  </metadata>
+  <components>
+    <component type="library"><name>express</name><scope>required</scope></component>
+  </components>
+  <services><service><name>api</name></service></services>
+</bom>`))
+
+	assert.Equal(t, []bom.Warning{
+		{Msg: "field components[].scope is not supported and was dropped"},
+		{Msg: "field metadata.licenses is not supported and was dropped"},
+		{Msg: "field services is not supported and was dropped"},
+	}, warnings)
+}
+
+func TestDroppedXMLFields(t *testing.T) {
+	warnings := cyclonedx.DroppedXMLFields([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.5" version="1">
+  <components>
+    <component type="library">
+      <group>org.example</group>
+      <name>express</name>
+      <externalReferences><reference type="vcs"><url>https://example.com/express</url></reference></externalReferences>
+    </component>
+  </components>
+  <vulnerabilities><vulnerability><id>CVE-2024-0001</id></vulnerability></vulnerabilities>
+</bom>`))
+
+	assert.Equal(t, []bom.Warning{
+		{Msg: "field components[].group is not supported and was dropped"},
+		{Msg: "field vulnerabilities is not supported and was dropped"},
+	}, warnings)
+}
+
+func TestDroppedFields_NoneDropped(t *testing.T) {
+	b, _, err := cyclonedx.Encode(newTestDocument(), "1.6", "xml")
+	assert.NoError(t, err)
+
+	assert.Empty(t, cyclonedx.DroppedFields(b))
+}
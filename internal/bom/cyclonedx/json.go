@@ -0,0 +1,286 @@
+package cyclonedx
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+type (
+	jsonBOM struct {
+		BOMFormat    string          `json:"bomFormat"`
+		SpecVersion  string          `json:"specVersion"`
+		SerialNumber string          `json:"serialNumber,omitempty"`
+		Version      int             `json:"version"`
+		Metadata     *jsonMetadata   `json:"metadata,omitempty"`
+		Components   []jsonComponent `json:"components"`
+		// ExternalReferences record the source revision of documents
+		// without a metadata component.
+		ExternalReferences []jsonExternalReference `json:"externalReferences,omitempty"`
+		Dependencies       []jsonDependency        `json:"dependencies"`
+	}
+
+	jsonMetadata struct {
+		Timestamp    string            `json:"timestamp,omitempty"`
+		Lifecycles   []jsonLifecycle   `json:"lifecycles,omitempty"`
+		Tools        *jsonTools        `json:"tools,omitempty"`
+		Authors      []jsonContact     `json:"authors,omitempty"`
+		Component    *jsonComponent    `json:"component,omitempty"`
+		Manufacture  *jsonOrganization `json:"manufacture,omitempty"`
+		Manufacturer *jsonOrganization `json:"manufacturer,omitempty"`
+		Supplier     *jsonOrganization `json:"supplier,omitempty"`
+		Properties   []jsonProperty    `json:"properties,omitempty"`
+	}
+
+	jsonLifecycle struct {
+		Phase string `json:"phase"`
+	}
+
+	jsonContact struct {
+		Name string `json:"name"`
+	}
+
+	// jsonTools is either the legacy list of tools (CycloneDX 1.4) or
+	// the object of tool components and services (CycloneDX 1.5+).
+	jsonTools struct {
+		Legacy     []jsonTool
+		Components []jsonComponent
+		Services   []jsonService
+	}
+
+	jsonTool struct {
+		Vendor  string `json:"vendor,omitempty"`
+		Name    string `json:"name,omitempty"`
+		Version string `json:"version,omitempty"`
+	}
+
+	jsonComponent struct {
+		BOMRef             string                  `json:"bom-ref,omitempty"`
+		Type               string                  `json:"type"`
+		Supplier           *jsonOrganization       `json:"supplier,omitempty"`
+		Name               string                  `json:"name"`
+		Version            string                  `json:"version,omitempty"`
+		Hashes             []jsonHash              `json:"hashes,omitempty"`
+		Licenses           []jsonLicenseChoice     `json:"licenses,omitempty"`
+		CPE                string                  `json:"cpe,omitempty"`
+		PURL               string                  `json:"purl,omitempty"`
+		ExternalReferences []jsonExternalReference `json:"externalReferences,omitempty"`
+		Properties         []jsonProperty          `json:"properties,omitempty"`
+		// Components are nested (sub-)components, which are only read from
+		// decoded documents.
+		Components []jsonComponent `json:"components,omitempty"`
+	}
+
+	jsonHash struct {
+		Alg     string `json:"alg"`
+		Content string `json:"content"`
+	}
+
+	// jsonLicenseChoice is either a license or an SPDX license expression.
+	jsonLicenseChoice struct {
+		License    *jsonLicense `json:"license,omitempty"`
+		Expression string       `json:"expression,omitempty"`
+	}
+
+	jsonLicense struct {
+		ID   string `json:"id,omitempty"`
+		Name string `json:"name,omitempty"`
+	}
+
+	jsonService struct {
+		Provider *jsonOrganization `json:"provider,omitempty"`
+		Name     string            `json:"name"`
+		Version  string            `json:"version,omitempty"`
+	}
+
+	jsonExternalReference struct {
+		URL  string `json:"url"`
+		Type string `json:"type"`
+	}
+
+	jsonOrganization struct {
+		Name string `json:"name,omitempty"`
+	}
+
+	jsonProperty struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+
+	jsonDependency struct {
+		Ref       string   `json:"ref"`
+		DependsOn []string `json:"dependsOn,omitempty"`
+	}
+)
+
+func (t *jsonTools) MarshalJSON() ([]byte, error) {
+	if t.Legacy != nil {
+		return json.Marshal(t.Legacy)
+	}
+	return json.Marshal(struct {
+		Components []jsonComponent `json:"components,omitempty"`
+		Services   []jsonService   `json:"services,omitempty"`
+	}{Components: t.Components, Services: t.Services})
+}
+
+func (t *jsonTools) UnmarshalJSON(b []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte("[")) {
+		return json.Unmarshal(b, &t.Legacy)
+	}
+	var obj struct {
+		Components []jsonComponent `json:"components"`
+		Services   []jsonService   `json:"services"`
+	}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return err
+	}
+	t.Components, t.Services = obj.Components, obj.Services
+	return nil
+}
+
+func encodeJSON(doc *bom.Document, specVersion string) ([]byte, error) {
+	out := jsonBOM{
+		BOMFormat:    bomFormat,
+		SpecVersion:  specVersion,
+		SerialNumber: doc.SerialNumber,
+		Version:      bomVersion,
+		Metadata: &jsonMetadata{
+			Timestamp:  formatTimestamp(doc.Timestamp),
+			Tools:      toJSONTools(doc.Metadata.Tools, specVersion),
+			Supplier:   toJSONOrganization(doc.Metadata.Supplier),
+			Properties: toJSONProperties(metadataProperties(&doc.Metadata, specVersion)),
+		},
+		Components:   make([]jsonComponent, 0, len(doc.Components)),
+		Dependencies: make([]jsonDependency, 0, len(doc.Dependencies)),
+	}
+
+	if doc.Metadata.Component != nil {
+		c := toJSONComponent(doc.Metadata.Component, specVersion)
+		if v := doc.Metadata.VCS; v != nil {
+			if v.URL != "" {
+				c.ExternalReferences = []jsonExternalReference{{URL: v.URL, Type: externalReferenceTypeVCS}}
+			}
+			c.Properties = append(c.Properties, toJSONProperties(vcsProperties(v))...)
+		}
+		out.Metadata.Component = &c
+	} else if v := doc.Metadata.VCS; v != nil {
+		// Without a metadata component, the source revision is recorded on
+		// the document.
+		if v.URL != "" {
+			out.ExternalReferences = []jsonExternalReference{{URL: v.URL, Type: externalReferenceTypeVCS}}
+		}
+		out.Metadata.Properties = append(out.Metadata.Properties, toJSONProperties(vcsProperties(v))...)
+	}
+
+	if hasLifecycles(specVersion) {
+		for _, l := range doc.Metadata.Lifecycles {
+			out.Metadata.Lifecycles = append(out.Metadata.Lifecycles, jsonLifecycle{Phase: l})
+		}
+	}
+
+	for _, a := range doc.Metadata.Authors {
+		out.Metadata.Authors = append(out.Metadata.Authors, jsonContact{Name: a})
+	}
+
+	if hasManufacturer(specVersion) {
+		out.Metadata.Manufacturer = toJSONOrganization(doc.Metadata.Manufacturer)
+	} else {
+		out.Metadata.Manufacture = toJSONOrganization(doc.Metadata.Manufacturer)
+	}
+
+	for _, c := range doc.Components {
+		out.Components = append(out.Components, toJSONComponent(c, specVersion))
+	}
+
+	for _, d := range doc.Dependencies {
+		out.Dependencies = append(out.Dependencies, jsonDependency{Ref: d.Ref, DependsOn: d.DependsOn})
+	}
+
+	return json.Marshal(out)
+}
+
+func toJSONTools(tools []*bom.Tool, specVersion string) *jsonTools {
+	if len(tools) == 0 {
+		return nil
+	}
+
+	if !hasToolsObject(specVersion) {
+		legacy := make([]jsonTool, 0, len(tools))
+		for _, t := range tools {
+			legacy = append(legacy, jsonTool{Vendor: t.Vendor, Name: t.Name, Version: t.Version})
+		}
+		return &jsonTools{Legacy: legacy}
+	}
+
+	var out jsonTools
+	for _, t := range tools {
+		if t.Service {
+			out.Services = append(out.Services, jsonService{Provider: toJSONOrganization(t.Vendor), Name: t.Name, Version: t.Version})
+			continue
+		}
+		c := jsonComponent{
+			Type:    string(bom.ComponentTypeApplication),
+			Name:    t.Name,
+			Version: t.Version,
+		}
+		if t.Vendor != "" {
+			c.Supplier = &jsonOrganization{Name: t.Vendor}
+		}
+		out.Components = append(out.Components, c)
+	}
+	return &out
+}
+
+func toJSONComponent(c *bom.Component, specVersion string) jsonComponent {
+	out := jsonComponent{
+		BOMRef:     c.BOMRef,
+		Type:       componentType(c.Type, specVersion),
+		Supplier:   toJSONOrganization(c.Supplier),
+		Name:       c.Name,
+		Version:    c.Version,
+		CPE:        c.CPE,
+		PURL:       c.PURL,
+		Licenses:   toJSONLicenses(c.Licenses),
+		Properties: toJSONProperties(c.Properties),
+	}
+	for _, h := range supportedHashes(c.Hashes) {
+		out.Hashes = append(out.Hashes, jsonHash{Alg: h.Algorithm, Content: h.Value})
+	}
+	return out
+}
+
+func toJSONLicenses(licenses []bom.License) []jsonLicenseChoice {
+	named, expression := splitLicenses(licenses)
+	if expression != "" {
+		return []jsonLicenseChoice{{Expression: expression}}
+	}
+
+	var out []jsonLicenseChoice
+	for _, l := range named {
+		if l.Expression != "" {
+			out = append(out, jsonLicenseChoice{License: &jsonLicense{ID: l.Expression}})
+		} else {
+			out = append(out, jsonLicenseChoice{License: &jsonLicense{Name: l.Name}})
+		}
+	}
+	return out
+}
+
+func toJSONOrganization(name string) *jsonOrganization {
+	if name == "" {
+		return nil
+	}
+	return &jsonOrganization{Name: name}
+}
+
+func toJSONProperties(props []bom.Property) []jsonProperty {
+	if len(props) == 0 {
+		return nil
+	}
+	out := make([]jsonProperty, 0, len(props))
+	for _, p := range props {
+		out = append(out, jsonProperty{Name: p.Name, Value: p.Value})
+	}
+	return out
+}
@@ -0,0 +1,48 @@
+package cyclonedx
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+// Unrepresentable reports the information in doc that is lost when it is
+// encoded as a CycloneDX document of the given spec version.
+func Unrepresentable(doc *bom.Document, specVersion string) []bom.Warning {
+	var warnings []bom.Warning
+	check := func(c *bom.Component) {
+		if t := componentType(c.Type, specVersion); t != string(c.Type) {
+			warnings = append(warnings, bom.Warning{
+				BOMRef: c.BOMRef,
+				Msg:    fmt.Sprintf("component type %q is not supported by CycloneDX %s and was replaced by %q", c.Type, specVersion, t),
+			})
+		}
+		for _, h := range c.Hashes {
+			if !slices.Contains(hashAlgorithms[:], h.Algorithm) {
+				warnings = append(warnings, bom.Warning{
+					BOMRef: c.BOMRef,
+					Msg:    fmt.Sprintf("hash algorithm %s is not supported by CycloneDX and the hash was dropped", h.Algorithm),
+				})
+			}
+		}
+		if _, expression := splitLicenses(c.Licenses); expression != "" {
+			for _, l := range c.Licenses {
+				if l.Expression == "" {
+					warnings = append(warnings, bom.Warning{
+						BOMRef: c.BOMRef,
+						Msg:    fmt.Sprintf("license %q cannot be combined with a license expression and was dropped", l.Name),
+					})
+				}
+			}
+		}
+	}
+
+	if doc.Metadata.Component != nil {
+		check(doc.Metadata.Component)
+	}
+	for _, c := range doc.Components {
+		check(c)
+	}
+	return warnings
+}
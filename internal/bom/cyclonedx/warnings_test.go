@@ -0,0 +1,33 @@
+package cyclonedx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/bom/cyclonedx"
+)
+
+func TestUnrepresentable(t *testing.T) {
+	doc := newTestDocument()
+	doc.Metadata.Component.Type = "source"
+	doc.Components[0].Type = "platform"
+	doc.Components[0].Hashes = []bom.Hash{{Algorithm: "SHA-256", Value: "2c26b46b"}, {Algorithm: "ADLER32", Value: "062c0215"}}
+	doc.Components[1].Licenses = []bom.License{{Expression: "MIT OR Apache-2.0"}, {Name: "ACME Commercial License"}}
+
+	assert.Equal(t, []bom.Warning{
+		{BOMRef: "goof@1.0.0", Msg: `component type "source" is not supported by CycloneDX 1.4 and was replaced by "library"`},
+		{BOMRef: "express@4.4.0", Msg: `component type "platform" is not supported by CycloneDX 1.4 and was replaced by "library"`},
+		{BOMRef: "express@4.4.0", Msg: "hash algorithm ADLER32 is not supported by CycloneDX and the hash was dropped"},
+		{BOMRef: "ws@1.0.0", Msg: `license "ACME Commercial License" cannot be combined with a license expression and was dropped`},
+	}, cyclonedx.Unrepresentable(doc, "1.4"))
+}
+
+func TestUnrepresentable_NoWarnings(t *testing.T) {
+	doc := newTestDocument()
+	doc.Components[0].Type = "platform"
+	doc.Components[0].Licenses = []bom.License{{Expression: "MIT"}, {Name: "ACME Commercial License"}}
+
+	assert.Empty(t, cyclonedx.Unrepresentable(doc, "1.6"))
+}
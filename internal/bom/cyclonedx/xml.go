@@ -0,0 +1,513 @@
+package cyclonedx
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+type (
+	xmlBOM struct {
+		XMLName      xml.Name       `xml:"bom"`
+		XMLNS        string         `xml:"xmlns,attr"`
+		SerialNumber string         `xml:"serialNumber,attr,omitempty"`
+		Version      int            `xml:"version,attr"`
+		Metadata     *xmlMetadata   `xml:"metadata,omitempty"`
+		Components   *xmlComponents `xml:"components,omitempty"`
+		// ExternalReferences record the source revision of documents
+		// without a metadata component.
+		ExternalReferences *xmlExternalReferences `xml:"externalReferences,omitempty"`
+		Dependencies       *xmlDependencies       `xml:"dependencies,omitempty"`
+	}
+
+	xmlMetadata struct {
+		Timestamp    string           `xml:"timestamp,omitempty"`
+		Lifecycles   *xmlLifecycles   `xml:"lifecycles,omitempty"`
+		Tools        *xmlTools        `xml:"tools,omitempty"`
+		Authors      *xmlAuthors      `xml:"authors,omitempty"`
+		Component    *xmlComponent    `xml:"component,omitempty"`
+		Manufacture  *xmlOrganization `xml:"manufacture,omitempty"`
+		Manufacturer *xmlOrganization `xml:"manufacturer,omitempty"`
+		Supplier     *xmlOrganization `xml:"supplier,omitempty"`
+		Properties   *xmlProperties   `xml:"properties,omitempty"`
+	}
+
+	xmlLifecycles struct {
+		Lifecycle []xmlLifecycle `xml:"lifecycle"`
+	}
+
+	xmlLifecycle struct {
+		Phase string `xml:"phase"`
+	}
+
+	xmlAuthors struct {
+		Author []xmlContact `xml:"author"`
+	}
+
+	xmlContact struct {
+		Name string `xml:"name"`
+	}
+
+	// xmlTools holds either the legacy list of tools (CycloneDX 1.4) or
+	// the tool components and services (CycloneDX 1.5+).
+	xmlTools struct {
+		Legacy     []xmlTool      `xml:"tool,omitempty"`
+		Components *xmlComponents `xml:"components,omitempty"`
+		Services   *xmlServices   `xml:"services,omitempty"`
+	}
+
+	xmlServices struct {
+		Service []xmlService `xml:"service"`
+	}
+
+	xmlService struct {
+		Provider *xmlOrganization `xml:"provider,omitempty"`
+		Name     string           `xml:"name"`
+		Version  string           `xml:"version,omitempty"`
+	}
+
+	xmlComponents struct {
+		Component []xmlComponent `xml:"component"`
+	}
+
+	xmlDependencies struct {
+		Dependency []xmlDependency `xml:"dependency"`
+	}
+
+	xmlTool struct {
+		Vendor  string `xml:"vendor,omitempty"`
+		Name    string `xml:"name,omitempty"`
+		Version string `xml:"version,omitempty"`
+	}
+
+	xmlComponent struct {
+		Type               string                 `xml:"type,attr"`
+		BOMRef             string                 `xml:"bom-ref,attr,omitempty"`
+		Supplier           *xmlOrganization       `xml:"supplier,omitempty"`
+		Name               string                 `xml:"name"`
+		Version            string                 `xml:"version,omitempty"`
+		Hashes             *xmlHashes             `xml:"hashes,omitempty"`
+		Licenses           *xmlLicenses           `xml:"licenses,omitempty"`
+		CPE                string                 `xml:"cpe,omitempty"`
+		PURL               string                 `xml:"purl,omitempty"`
+		ExternalReferences *xmlExternalReferences `xml:"externalReferences,omitempty"`
+		Properties         *xmlProperties         `xml:"properties,omitempty"`
+		// Components are nested (sub-)components, which are only read from
+		// decoded documents.
+		Components *xmlComponents `xml:"components,omitempty"`
+	}
+
+	xmlHashes struct {
+		Hash []xmlHash `xml:"hash"`
+	}
+
+	xmlHash struct {
+		Alg   string `xml:"alg,attr"`
+		Value string `xml:",chardata"`
+	}
+
+	// xmlLicenses holds either licenses or a single SPDX license expression.
+	xmlLicenses struct {
+		License    []xmlLicense `xml:"license,omitempty"`
+		Expression string       `xml:"expression,omitempty"`
+	}
+
+	xmlLicense struct {
+		ID   string `xml:"id,omitempty"`
+		Name string `xml:"name,omitempty"`
+	}
+
+	xmlExternalReferences struct {
+		Reference []xmlExternalReference `xml:"reference"`
+	}
+
+	xmlExternalReference struct {
+		Type string `xml:"type,attr"`
+		URL  string `xml:"url"`
+	}
+
+	xmlOrganization struct {
+		Name string `xml:"name,omitempty"`
+	}
+
+	xmlProperties struct {
+		Property []xmlProperty `xml:"property"`
+	}
+
+	xmlProperty struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:",chardata"`
+	}
+
+	xmlDependency struct {
+		Ref       string          `xml:"ref,attr"`
+		DependsOn []xmlDependency `xml:"dependency,omitempty"`
+	}
+)
+
+func encodeXML(doc *bom.Document, specVersion string) ([]byte, error) {
+	out := xmlBOM{
+		XMLNS:        xmlnsPrefix + specVersion,
+		SerialNumber: doc.SerialNumber,
+		Version:      bomVersion,
+		Metadata:     toXMLMetadata(doc, specVersion),
+	}
+
+	if v := doc.Metadata.VCS; v != nil && v.URL != "" && doc.Metadata.Component == nil {
+		out.ExternalReferences = &xmlExternalReferences{
+			Reference: []xmlExternalReference{{Type: externalReferenceTypeVCS, URL: v.URL}},
+		}
+	}
+
+	if len(doc.Components) > 0 {
+		out.Components = &xmlComponents{}
+		for _, c := range doc.Components {
+			out.Components.Component = append(out.Components.Component, toXMLComponent(c, specVersion))
+		}
+	}
+
+	if len(doc.Dependencies) > 0 {
+		out.Dependencies = &xmlDependencies{}
+		for _, d := range doc.Dependencies {
+			dep := xmlDependency{Ref: d.Ref}
+			for _, r := range d.DependsOn {
+				dep.DependsOn = append(dep.DependsOn, xmlDependency{Ref: r})
+			}
+			out.Dependencies.Dependency = append(out.Dependencies.Dependency, dep)
+		}
+	}
+
+	b, err := xml.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), b...), nil
+}
+
+func toXMLMetadata(doc *bom.Document, specVersion string) *xmlMetadata {
+	m := &xmlMetadata{
+		Timestamp:  formatTimestamp(doc.Timestamp),
+		Tools:      toXMLTools(doc.Metadata.Tools, specVersion),
+		Supplier:   toXMLOrganization(doc.Metadata.Supplier),
+		Properties: toXMLProperties(metadataProperties(&doc.Metadata, specVersion)),
+	}
+
+	if doc.Metadata.Component != nil {
+		c := toXMLComponent(doc.Metadata.Component, specVersion)
+		if v := doc.Metadata.VCS; v != nil {
+			if v.URL != "" {
+				c.ExternalReferences = &xmlExternalReferences{
+					Reference: []xmlExternalReference{{Type: externalReferenceTypeVCS, URL: v.URL}},
+				}
+			}
+			c.Properties = toXMLProperties(append(slices.Clone(doc.Metadata.Component.Properties), vcsProperties(v)...))
+		}
+		m.Component = &c
+	} else if v := doc.Metadata.VCS; v != nil {
+		// Without a metadata component, the source revision is recorded on
+		// the document, see encodeXML.
+		m.Properties = toXMLProperties(append(slices.Clone(metadataProperties(&doc.Metadata, specVersion)), vcsProperties(v)...))
+	}
+
+	if hasLifecycles(specVersion) && len(doc.Metadata.Lifecycles) > 0 {
+		m.Lifecycles = &xmlLifecycles{}
+		for _, l := range doc.Metadata.Lifecycles {
+			m.Lifecycles.Lifecycle = append(m.Lifecycles.Lifecycle, xmlLifecycle{Phase: l})
+		}
+	}
+
+	if len(doc.Metadata.Authors) > 0 {
+		m.Authors = &xmlAuthors{}
+		for _, a := range doc.Metadata.Authors {
+			m.Authors.Author = append(m.Authors.Author, xmlContact{Name: a})
+		}
+	}
+
+	if hasManufacturer(specVersion) {
+		m.Manufacturer = toXMLOrganization(doc.Metadata.Manufacturer)
+	} else {
+		m.Manufacture = toXMLOrganization(doc.Metadata.Manufacturer)
+	}
+
+	return m
+}
+
+func toXMLTools(tools []*bom.Tool, specVersion string) *xmlTools {
+	if len(tools) == 0 {
+		return nil
+	}
+
+	var out xmlTools
+	for _, t := range tools {
+		if !hasToolsObject(specVersion) {
+			out.Legacy = append(out.Legacy, xmlTool{Vendor: t.Vendor, Name: t.Name, Version: t.Version})
+			continue
+		}
+		if t.Service {
+			if out.Services == nil {
+				out.Services = &xmlServices{}
+			}
+			out.Services.Service = append(out.Services.Service, xmlService{Provider: toXMLOrganization(t.Vendor), Name: t.Name, Version: t.Version})
+			continue
+		}
+
+		c := xmlComponent{
+			Type:    string(bom.ComponentTypeApplication),
+			Name:    t.Name,
+			Version: t.Version,
+		}
+		if t.Vendor != "" {
+			c.Supplier = &xmlOrganization{Name: t.Vendor}
+		}
+		if out.Components == nil {
+			out.Components = &xmlComponents{}
+		}
+		out.Components.Component = append(out.Components.Component, c)
+	}
+	return &out
+}
+
+func toXMLComponent(c *bom.Component, specVersion string) xmlComponent {
+	out := xmlComponent{
+		Type:       componentType(c.Type, specVersion),
+		BOMRef:     c.BOMRef,
+		Supplier:   toXMLOrganization(c.Supplier),
+		Name:       c.Name,
+		Version:    c.Version,
+		Licenses:   toXMLLicenses(c.Licenses),
+		CPE:        c.CPE,
+		PURL:       c.PURL,
+		Properties: toXMLProperties(c.Properties),
+	}
+	if hashes := supportedHashes(c.Hashes); len(hashes) > 0 {
+		out.Hashes = &xmlHashes{}
+		for _, h := range hashes {
+			out.Hashes.Hash = append(out.Hashes.Hash, xmlHash{Alg: h.Algorithm, Value: h.Value})
+		}
+	}
+	return out
+}
+
+func toXMLLicenses(licenses []bom.License) *xmlLicenses {
+	if len(licenses) == 0 {
+		return nil
+	}
+	named, expression := splitLicenses(licenses)
+	if expression != "" {
+		return &xmlLicenses{Expression: expression}
+	}
+
+	out := &xmlLicenses{}
+	for _, l := range named {
+		if l.Expression != "" {
+			out.License = append(out.License, xmlLicense{ID: l.Expression})
+		} else {
+			out.License = append(out.License, xmlLicense{Name: l.Name})
+		}
+	}
+	return out
+}
+
+func toXMLOrganization(name string) *xmlOrganization {
+	if name == "" {
+		return nil
+	}
+	return &xmlOrganization{Name: name}
+}
+
+func toXMLProperties(props []bom.Property) *xmlProperties {
+	if len(props) == 0 {
+		return nil
+	}
+	out := make([]xmlProperty, 0, len(props))
+	for _, p := range props {
+		out = append(out, xmlProperty{Name: p.Name, Value: p.Value})
+	}
+	return &xmlProperties{Property: out}
+}
+
+// XMLSpecVersion returns the spec version of a CycloneDX XML document, which
+// is identified by the namespace of its root element.
+func XMLSpecVersion(b []byte) (string, bool) {
+	d := xml.NewDecoder(bytes.NewReader(b))
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return "", false
+		}
+		if el, ok := tok.(xml.StartElement); ok {
+			version, found := strings.CutPrefix(el.Name.Space, xmlnsPrefix)
+			return version, found && el.Name.Local == "bom"
+		}
+	}
+}
+
+// isXML reports whether b looks like an XML rather than a JSON document.
+func isXML(b []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(b), []byte("<"))
+}
+
+// decodeXML reads a CycloneDX XML document into its JSON representation,
+// which Decode reads the document from.
+func decodeXML(b []byte) (*jsonBOM, error) {
+	specVersion, ok := XMLSpecVersion(b)
+	if !ok {
+		return nil, fmt.Errorf("not a CycloneDX XML document")
+	}
+
+	var in xmlBOM
+	if err := xml.Unmarshal(b, &in); err != nil {
+		return nil, fmt.Errorf("failed to decode CycloneDX document: %w", err)
+	}
+
+	out := &jsonBOM{
+		BOMFormat:    bomFormat,
+		SpecVersion:  specVersion,
+		SerialNumber: in.SerialNumber,
+		Version:      in.Version,
+		Components:   fromXMLComponents(in.Components),
+	}
+	if in.Metadata != nil {
+		out.Metadata = fromXMLMetadata(in.Metadata)
+	}
+	if in.ExternalReferences != nil {
+		for _, r := range in.ExternalReferences.Reference {
+			out.ExternalReferences = append(out.ExternalReferences, jsonExternalReference{URL: r.URL, Type: r.Type})
+		}
+	}
+	if in.Dependencies != nil {
+		for _, d := range in.Dependencies.Dependency {
+			dep := jsonDependency{Ref: d.Ref}
+			for _, r := range d.DependsOn {
+				dep.DependsOn = append(dep.DependsOn, r.Ref)
+			}
+			out.Dependencies = append(out.Dependencies, dep)
+		}
+	}
+	return out, nil
+}
+
+// XMLToJSON converts a CycloneDX XML document to a CycloneDX JSON document of
+// the same spec version. The fields Decode does not read, except for the
+// external references of components, are dropped, as reported by
+// DroppedXMLFields.
+func XMLToJSON(b []byte) ([]byte, error) {
+	out, err := decodeXML(b)
+	if err != nil {
+		return nil, err
+	}
+	// The version attribute is optional in XML, defaulting to 1.
+	out.Version = cmp.Or(out.Version, 1)
+	if out.Components == nil {
+		out.Components = []jsonComponent{}
+	}
+	if out.Dependencies == nil {
+		out.Dependencies = []jsonDependency{}
+	}
+	return json.Marshal(out)
+}
+
+func fromXMLMetadata(in *xmlMetadata) *jsonMetadata {
+	out := &jsonMetadata{
+		Timestamp:    in.Timestamp,
+		Manufacture:  fromXMLOrganization(in.Manufacture),
+		Manufacturer: fromXMLOrganization(in.Manufacturer),
+		Supplier:     fromXMLOrganization(in.Supplier),
+		Properties:   fromXMLProperties(in.Properties),
+	}
+	if in.Lifecycles != nil {
+		for _, l := range in.Lifecycles.Lifecycle {
+			out.Lifecycles = append(out.Lifecycles, jsonLifecycle(l))
+		}
+	}
+	if in.Tools != nil {
+		out.Tools = &jsonTools{Components: fromXMLComponents(in.Tools.Components)}
+		for _, t := range in.Tools.Legacy {
+			out.Tools.Legacy = append(out.Tools.Legacy, jsonTool(t))
+		}
+		if in.Tools.Services != nil {
+			for _, s := range in.Tools.Services.Service {
+				out.Tools.Services = append(out.Tools.Services, jsonService{Provider: fromXMLOrganization(s.Provider), Name: s.Name, Version: s.Version})
+			}
+		}
+	}
+	if in.Authors != nil {
+		for _, a := range in.Authors.Author {
+			out.Authors = append(out.Authors, jsonContact(a))
+		}
+	}
+	if in.Component != nil {
+		c := fromXMLComponent(in.Component)
+		out.Component = &c
+	}
+	return out
+}
+
+func fromXMLComponents(in *xmlComponents) []jsonComponent {
+	if in == nil {
+		return nil
+	}
+	out := make([]jsonComponent, 0, len(in.Component))
+	for i := range in.Component {
+		out = append(out, fromXMLComponent(&in.Component[i]))
+	}
+	return out
+}
+
+func fromXMLComponent(in *xmlComponent) jsonComponent {
+	out := jsonComponent{
+		BOMRef:     in.BOMRef,
+		Type:       in.Type,
+		Supplier:   fromXMLOrganization(in.Supplier),
+		Name:       in.Name,
+		Version:    in.Version,
+		CPE:        in.CPE,
+		PURL:       in.PURL,
+		Properties: fromXMLProperties(in.Properties),
+		Components: fromXMLComponents(in.Components),
+	}
+	if in.Hashes != nil {
+		for _, h := range in.Hashes.Hash {
+			out.Hashes = append(out.Hashes, jsonHash{Alg: h.Alg, Content: strings.TrimSpace(h.Value)})
+		}
+	}
+	if in.Licenses != nil {
+		for _, l := range in.Licenses.License {
+			out.Licenses = append(out.Licenses, jsonLicenseChoice{License: &jsonLicense{ID: l.ID, Name: l.Name}})
+		}
+		if in.Licenses.Expression != "" {
+			out.Licenses = append(out.Licenses, jsonLicenseChoice{Expression: in.Licenses.Expression})
+		}
+	}
+	if in.ExternalReferences != nil {
+		for _, r := range in.ExternalReferences.Reference {
+			out.ExternalReferences = append(out.ExternalReferences, jsonExternalReference{URL: r.URL, Type: r.Type})
+		}
+	}
+	return out
+}
+
+func fromXMLOrganization(in *xmlOrganization) *jsonOrganization {
+	if in == nil {
+		return nil
+	}
+	return &jsonOrganization{Name: in.Name}
+}
+
+func fromXMLProperties(in *xmlProperties) []jsonProperty {
+	if in == nil {
+		return nil
+	}
+	out := make([]jsonProperty, 0, len(in.Property))
+	for _, p := range in.Property {
+		out = append(out, jsonProperty(p))
+	}
+	return out
+}
@@ -0,0 +1,113 @@
+package bom
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+type (
+	// Diff lists the components added, removed or changed between two
+	// documents.
+	Diff struct {
+		Added   []*Component
+		Removed []*Component
+		Changed []*ComponentChange
+	}
+
+	// ComponentChange is a component whose version changed.
+	ComponentChange struct {
+		From *Component
+		To   *Component
+	}
+)
+
+// Empty reports whether the documents have the same components.
+func (d *Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffDocuments compares the components of two documents. Components are
+// matched by their purl without the version, or by name if they have no
+// purl. A package present in a single version on both sides is reported as
+// changed if the versions differ; otherwise each version is reported as
+// added or removed. The metadata components are not compared.
+func DiffDocuments(from, to *Document) *Diff {
+	fromByKey, toByKey := componentsByKey(from), componentsByKey(to)
+
+	keys := make([]string, 0, len(fromByKey)+len(toByKey))
+	for k := range fromByKey {
+		keys = append(keys, k)
+	}
+	for k := range toByKey {
+		if _, ok := fromByKey[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	d := &Diff{}
+	for _, k := range keys {
+		removed := missingVersions(fromByKey[k], toByKey[k])
+		added := missingVersions(toByKey[k], fromByKey[k])
+		if len(removed) == 1 && len(added) == 1 {
+			d.Changed = append(d.Changed, &ComponentChange{From: removed[0], To: added[0]})
+			continue
+		}
+		d.Removed = append(d.Removed, removed...)
+		d.Added = append(d.Added, added...)
+	}
+
+	return d
+}
+
+// componentsByKey groups the components of doc by their match key, ordered
+// by version.
+func componentsByKey(doc *Document) map[string][]*Component {
+	out := map[string][]*Component{}
+	for _, c := range doc.Components {
+		k := matchKey(c)
+		if !slices.ContainsFunc(out[k], func(x *Component) bool { return x.Version == c.Version }) {
+			out[k] = append(out[k], c)
+		}
+	}
+	for _, cs := range out {
+		slices.SortFunc(cs, func(a, b *Component) int { return cmp.Compare(a.Version, b.Version) })
+	}
+	return out
+}
+
+// missingVersions returns the components of cs whose version is not in
+// other.
+func missingVersions(cs, other []*Component) []*Component {
+	var out []*Component
+	for _, c := range cs {
+		if !slices.ContainsFunc(other, func(o *Component) bool { return o.Version == c.Version }) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func matchKey(c *Component) string {
+	if c.PURL == "" {
+		return "name:" + c.Name
+	}
+	return unversionedPURL(c.PURL)
+}
+
+// unversionedPURL strips the version from a package URL, keeping its
+// qualifiers and subpath.
+func unversionedPURL(purl string) string {
+	end := len(purl)
+	if i := strings.IndexAny(purl, "?#"); i >= 0 {
+		end = i
+	}
+	base, rest := purl[:end], purl[end:]
+
+	nameStart := strings.LastIndex(base, "/") + 1
+	if i := strings.Index(base[nameStart:], "@"); i >= 0 {
+		base = base[:nameStart+i]
+	}
+	return base + rest
+}
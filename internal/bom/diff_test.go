@@ -0,0 +1,70 @@
+package bom_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+func TestDiffDocuments(t *testing.T) {
+	from := &bom.Document{
+		Metadata: bom.Metadata{Component: &bom.Component{Name: "app", Version: "1.0.0"}},
+		Components: []*bom.Component{
+			{BOMRef: "1", Name: "express", Version: "4.4.0", PURL: "pkg:npm/express@4.4.0"},
+			{BOMRef: "2", Name: "ws", Version: "1.0.0", PURL: "pkg:npm/ws@1.0.0"},
+			{BOMRef: "3", Name: "lodash", Version: "3.0.0", PURL: "pkg:npm/lodash@3.0.0"},
+			{BOMRef: "4", Name: "util", Version: "1.0.0"},
+		},
+	}
+	to := &bom.Document{
+		Metadata: bom.Metadata{Component: &bom.Component{Name: "app", Version: "2.0.0"}},
+		Components: []*bom.Component{
+			{BOMRef: "a", Name: "express", Version: "4.5.0", PURL: "pkg:npm/express@4.5.0"},
+			{BOMRef: "b", Name: "lodash", Version: "3.0.0", PURL: "pkg:npm/lodash@3.0.0"},
+			{BOMRef: "c", Name: "lodash", Version: "4.0.0", PURL: "pkg:npm/lodash@4.0.0"},
+			{BOMRef: "d", Name: "util", Version: "1.0.0"},
+			{BOMRef: "e", Name: "@snyk/router", Version: "1.0.0", PURL: "pkg:npm/%40snyk/router@1.0.0"},
+		},
+	}
+
+	d := bom.DiffDocuments(from, to)
+
+	assert.False(t, d.Empty())
+	assert.Equal(t, []*bom.Component{
+		{BOMRef: "e", Name: "@snyk/router", Version: "1.0.0", PURL: "pkg:npm/%40snyk/router@1.0.0"},
+		{BOMRef: "c", Name: "lodash", Version: "4.0.0", PURL: "pkg:npm/lodash@4.0.0"},
+	}, d.Added)
+	assert.Equal(t, []*bom.Component{
+		{BOMRef: "2", Name: "ws", Version: "1.0.0", PURL: "pkg:npm/ws@1.0.0"},
+	}, d.Removed)
+	assert.Equal(t, []*bom.ComponentChange{{
+		From: &bom.Component{BOMRef: "1", Name: "express", Version: "4.4.0", PURL: "pkg:npm/express@4.4.0"},
+		To:   &bom.Component{BOMRef: "a", Name: "express", Version: "4.5.0", PURL: "pkg:npm/express@4.5.0"},
+	}}, d.Changed)
+}
+
+func TestDiffDocuments_MatchesQualifiedPURLs(t *testing.T) {
+	from := &bom.Document{Components: []*bom.Component{
+		{Name: "guava", Version: "31.0", PURL: "pkg:maven/com.google/guava@31.0?type=jar"},
+		{Name: "guava", Version: "31.0", PURL: "pkg:maven/com.google/guava@31.0?type=pom"},
+	}}
+	to := &bom.Document{Components: []*bom.Component{
+		{Name: "guava", Version: "32.0", PURL: "pkg:maven/com.google/guava@32.0?type=jar"},
+		{Name: "guava", Version: "31.0", PURL: "pkg:maven/com.google/guava@31.0?type=pom"},
+	}}
+
+	d := bom.DiffDocuments(from, to)
+
+	assert.Empty(t, d.Added)
+	assert.Empty(t, d.Removed)
+	assert.Len(t, d.Changed, 1)
+	assert.Equal(t, "32.0", d.Changed[0].To.Version)
+}
+
+func TestDiffDocuments_Equal(t *testing.T) {
+	doc := &bom.Document{Components: []*bom.Component{{Name: "ws", Version: "1.0.0", PURL: "pkg:npm/ws@1.0.0"}}}
+
+	assert.True(t, bom.DiffDocuments(doc, doc).Empty())
+}
@@ -0,0 +1,41 @@
+package bom
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	StandardCycloneDX = "cyclonedx"
+	StandardSPDX      = "spdx"
+
+	EncodingJSON = "json"
+	EncodingXML  = "xml"
+)
+
+// Format describes an SBOM output format, such as `cyclonedx1.5+xml`.
+type Format struct {
+	Standard    string
+	SpecVersion string
+	Encoding    string
+}
+
+// ParseFormat splits a format string like `cyclonedx1.4+json` into its parts.
+func ParseFormat(f string) (Format, error) {
+	spec, encoding, ok := strings.Cut(f, "+")
+	if !ok || encoding == "" {
+		return Format{}, fmt.Errorf("invalid format %q", f)
+	}
+
+	for _, standard := range []string{StandardCycloneDX, StandardSPDX} {
+		if version, found := strings.CutPrefix(spec, standard); found && version != "" {
+			return Format{Standard: standard, SpecVersion: version, Encoding: encoding}, nil
+		}
+	}
+
+	return Format{}, fmt.Errorf("invalid format %q", f)
+}
+
+func (f Format) String() string {
+	return f.Standard + f.SpecVersion + "+" + f.Encoding
+}
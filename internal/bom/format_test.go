@@ -0,0 +1,38 @@
+package bom_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+func TestParseFormat(t *testing.T) {
+	tc := map[string]bom.Format{
+		"cyclonedx1.4+json": {Standard: "cyclonedx", SpecVersion: "1.4", Encoding: "json"},
+		"cyclonedx1.6+xml":  {Standard: "cyclonedx", SpecVersion: "1.6", Encoding: "xml"},
+		"spdx2.3+json":      {Standard: "spdx", SpecVersion: "2.3", Encoding: "json"},
+	}
+
+	for input, expected := range tc {
+		t.Run(input, func(t *testing.T) {
+			f, err := bom.ParseFormat(input)
+
+			require.NoError(t, err)
+			assert.Equal(t, expected, f)
+			assert.Equal(t, input, f.String())
+		})
+	}
+}
+
+func TestParseFormat_Invalid(t *testing.T) {
+	for _, input := range []string{"", "cyclonedx+json", "cyclonedx1.4", "foo1.0+json"} {
+		t.Run(input, func(t *testing.T) {
+			_, err := bom.ParseFormat(input)
+
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package bom
+
+import "strings"
+
+// IsSPDXID reports whether the license is a single license identifier from
+// the SPDX license list, rather than a compound expression or a reference to
+// a custom license.
+func (l License) IsSPDXID() bool {
+	if l.Expression == "" || strings.ContainsAny(l.Expression, " ()") {
+		return false
+	}
+	return !strings.HasPrefix(l.Expression, "LicenseRef-") && !strings.HasPrefix(l.Expression, "DocumentRef-")
+}
+
+// LicenseExpression combines the expressions of licenses into a single SPDX
+// license expression that requires all of them. Licenses that only have a
+// name are skipped.
+func LicenseExpression(licenses []License) string {
+	exprs := make([]string, 0, len(licenses))
+	for _, l := range licenses {
+		if l.Expression != "" {
+			exprs = append(exprs, l.Expression)
+		}
+	}
+	if len(exprs) > 1 {
+		for i, e := range exprs {
+			if strings.Contains(e, " ") {
+				exprs[i] = "(" + e + ")"
+			}
+		}
+	}
+	return strings.Join(exprs, " AND ")
+}
@@ -0,0 +1,34 @@
+package bom_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+func TestLicense_IsSPDXID(t *testing.T) {
+	assert.True(t, bom.License{Expression: "MIT"}.IsSPDXID())
+	assert.True(t, bom.License{Expression: "GPL-2.0-or-later"}.IsSPDXID())
+	assert.False(t, bom.License{Expression: "MIT OR Apache-2.0"}.IsSPDXID())
+	assert.False(t, bom.License{Expression: "LicenseRef-ACME"}.IsSPDXID())
+	assert.False(t, bom.License{Name: "ACME Commercial License"}.IsSPDXID())
+}
+
+func TestLicenseExpression(t *testing.T) {
+	tc := []struct {
+		licenses []bom.License
+		expected string
+	}{
+		{licenses: nil, expected: ""},
+		{licenses: []bom.License{{Expression: "MIT OR Apache-2.0"}}, expected: "MIT OR Apache-2.0"},
+		{licenses: []bom.License{{Expression: "MIT"}, {Expression: "ISC"}}, expected: "MIT AND ISC"},
+		{licenses: []bom.License{{Expression: "MIT"}, {Expression: "ISC OR Apache-2.0"}}, expected: "MIT AND (ISC OR Apache-2.0)"},
+		{licenses: []bom.License{{Expression: "ISC OR Apache-2.0"}, {Name: "ACME Commercial License"}}, expected: "ISC OR Apache-2.0"},
+	}
+
+	for _, tt := range tc {
+		assert.Equal(t, tt.expected, bom.LicenseExpression(tt.licenses))
+	}
+}
@@ -0,0 +1,113 @@
+package bom
+
+import "fmt"
+
+// Merge combines docs into a single document describing subject. Components
+// are deduplicated by purl, or by bom-ref for components without one, and
+// bom-refs that identify different components in different documents are
+// rewritten to keep them unique. The metadata component of each document (or
+// its top-level components, if it has none) becomes a dependency of subject.
+func Merge(subject *Component, docs ...*Document) *Document {
+	m := &merger{
+		out:    &Document{Metadata: Metadata{Component: subject}},
+		byPURL: map[string]string{},
+	}
+	m.out.AddDependencies(subject.BOMRef)
+
+	for _, doc := range docs {
+		m.add(doc)
+	}
+
+	return m.out
+}
+
+type merger struct {
+	out *Document
+	// byPURL maps purls to the bom-ref of the merged component.
+	byPURL map[string]string
+}
+
+func (m *merger) add(doc *Document) {
+	// refs maps the bom-refs of doc to those of the merged document.
+	refs := make(map[string]string, len(doc.Components)+1)
+	if root := doc.Metadata.Component; root != nil {
+		refs[root.BOMRef] = m.addComponent(root)
+	}
+	for _, c := range doc.Components {
+		refs[c.BOMRef] = m.addComponent(c)
+	}
+	rewrite := func(ref string) string {
+		if r, ok := refs[ref]; ok {
+			return r
+		}
+		return ref
+	}
+
+	for _, dep := range doc.Dependencies {
+		dependsOn := make([]string, 0, len(dep.DependsOn))
+		for _, ref := range dep.DependsOn {
+			dependsOn = append(dependsOn, rewrite(ref))
+		}
+		m.out.AddDependencies(rewrite(dep.Ref), dependsOn...)
+	}
+
+	subject := m.out.Metadata.Component.BOMRef
+	for _, ref := range topLevelRefs(doc) {
+		if r := rewrite(ref); r != subject {
+			m.out.AddDependencies(subject, r)
+		}
+	}
+}
+
+// addComponent adds c to the merged document unless it is already present,
+// and returns its bom-ref in the merged document.
+func (m *merger) addComponent(c *Component) string {
+	if c.PURL != "" {
+		if ref, ok := m.byPURL[c.PURL]; ok {
+			return ref
+		}
+	}
+	if existing := m.out.Component(c.BOMRef); existing != nil &&
+		existing.PURL == c.PURL && existing.Name == c.Name && existing.Version == c.Version {
+		return existing.BOMRef
+	}
+
+	merged := *c
+	merged.BOMRef = m.uniqueRef(c.BOMRef)
+	m.out.AddComponent(&merged)
+	if c.PURL != "" {
+		m.byPURL[c.PURL] = merged.BOMRef
+	}
+	return merged.BOMRef
+}
+
+// uniqueRef returns ref, suffixed with a counter if it is already taken.
+func (m *merger) uniqueRef(ref string) string {
+	candidate := ref
+	for i := 2; m.out.Component(candidate) != nil; i++ {
+		candidate = fmt.Sprintf("%s-%d", ref, i)
+	}
+	return candidate
+}
+
+// topLevelRefs returns the bom-refs that describe what doc is about: its
+// metadata component, or else the components nothing depends on.
+func topLevelRefs(doc *Document) []string {
+	if root := doc.Metadata.Component; root != nil {
+		return []string{root.BOMRef}
+	}
+
+	dependedOn := map[string]bool{}
+	for _, dep := range doc.Dependencies {
+		for _, ref := range dep.DependsOn {
+			dependedOn[ref] = true
+		}
+	}
+	var refs []string
+	for _, c := range doc.Components {
+		if !dependedOn[c.BOMRef] {
+			refs = append(refs, c.BOMRef)
+		}
+	}
+	return refs
+}
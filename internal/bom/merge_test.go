@@ -0,0 +1,72 @@
+package bom_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+func TestMerge(t *testing.T) {
+	subject := &bom.Component{BOMRef: "product@2.0.0", Type: bom.ComponentTypeApplication, Name: "product", Version: "2.0.0"}
+	frontend := &bom.Document{
+		Metadata: bom.Metadata{Component: &bom.Component{BOMRef: "frontend", Type: bom.ComponentTypeApplication, Name: "frontend"}},
+		Components: []*bom.Component{
+			{BOMRef: "express@4.4.0", Type: bom.ComponentTypeLibrary, Name: "express", Version: "4.4.0", PURL: "pkg:npm/express@4.4.0"},
+			{BOMRef: "util", Type: bom.ComponentTypeLibrary, Name: "util", Version: "1.0.0"},
+		},
+		Dependencies: []*bom.Dependency{
+			{Ref: "frontend", DependsOn: []string{"express@4.4.0", "util"}},
+		},
+	}
+	backend := &bom.Document{
+		Metadata: bom.Metadata{Component: &bom.Component{BOMRef: "SPDXRef-1", Type: bom.ComponentTypeApplication, Name: "backend"}},
+		Components: []*bom.Component{
+			{BOMRef: "SPDXRef-2", Type: bom.ComponentTypeLibrary, Name: "express", Version: "4.4.0", PURL: "pkg:npm/express@4.4.0"},
+			{BOMRef: "util", Type: bom.ComponentTypeLibrary, Name: "util", Version: "2.0.0"},
+		},
+		Dependencies: []*bom.Dependency{
+			{Ref: "SPDXRef-1", DependsOn: []string{"SPDXRef-2", "util"}},
+		},
+	}
+
+	doc := bom.Merge(subject, frontend, backend)
+
+	assert.Equal(t, subject, doc.Metadata.Component)
+	assert.Equal(t, []*bom.Component{
+		{BOMRef: "frontend", Type: bom.ComponentTypeApplication, Name: "frontend"},
+		{BOMRef: "express@4.4.0", Type: bom.ComponentTypeLibrary, Name: "express", Version: "4.4.0", PURL: "pkg:npm/express@4.4.0"},
+		{BOMRef: "util", Type: bom.ComponentTypeLibrary, Name: "util", Version: "1.0.0"},
+		{BOMRef: "SPDXRef-1", Type: bom.ComponentTypeApplication, Name: "backend"},
+		{BOMRef: "util-2", Type: bom.ComponentTypeLibrary, Name: "util", Version: "2.0.0"},
+	}, doc.Components)
+	assert.Equal(t, []*bom.Dependency{
+		{Ref: "product@2.0.0", DependsOn: []string{"frontend", "SPDXRef-1"}},
+		{Ref: "frontend", DependsOn: []string{"express@4.4.0", "util"}},
+		{Ref: "SPDXRef-1", DependsOn: []string{"express@4.4.0", "util-2"}},
+	}, doc.Dependencies)
+}
+
+func TestMerge_DeduplicatesByBOMRef(t *testing.T) {
+	subject := &bom.Component{BOMRef: "product", Name: "product"}
+	a := &bom.Document{Components: []*bom.Component{{BOMRef: "util", Name: "util", Version: "1.0.0"}}}
+	b := &bom.Document{Components: []*bom.Component{{BOMRef: "util", Name: "util", Version: "1.0.0"}}}
+
+	doc := bom.Merge(subject, a, b)
+
+	assert.Equal(t, []*bom.Component{{BOMRef: "util", Name: "util", Version: "1.0.0"}}, doc.Components)
+	assert.Equal(t, []*bom.Dependency{
+		{Ref: "product", DependsOn: []string{"util"}},
+	}, doc.Dependencies, "documents without a metadata component are attached by their top-level components")
+}
+
+func TestMerge_DoesNotModifyInputs(t *testing.T) {
+	subject := &bom.Component{BOMRef: "product", Name: "product"}
+	a := &bom.Document{Components: []*bom.Component{{BOMRef: "util", Name: "util", Version: "1.0.0"}}}
+	b := &bom.Document{Components: []*bom.Component{{BOMRef: "util", Name: "util", Version: "2.0.0"}}}
+
+	bom.Merge(subject, a, b)
+
+	assert.Equal(t, "util", b.Components[0].BOMRef)
+}
@@ -0,0 +1,146 @@
+package bom
+
+// NTIA minimum elements, as checked by CheckNTIA. See
+// https://www.ntia.gov/report/2021/minimum-elements-software-bill-materials-sbom.
+const (
+	NTIASupplier     = "supplier"
+	NTIAName         = "component name"
+	NTIAVersion      = "version"
+	NTIAIdentifier   = "unique identifier"
+	NTIADependencies = "dependency relationships"
+	NTIAAuthor       = "author"
+	NTIATimestamp    = "timestamp"
+)
+
+var (
+	ntiaComponentElements = [...]string{NTIASupplier, NTIAName, NTIAVersion, NTIAIdentifier, NTIADependencies}
+	ntiaDocumentElements  = [...]string{NTIAAuthor, NTIATimestamp}
+
+	// NTIAElements are the NTIA minimum elements, the component elements
+	// first.
+	NTIAElements = append(ntiaComponentElements[:], ntiaDocumentElements[:]...)
+)
+
+type (
+	// NTIAResult is the conformance of a document to the NTIA minimum
+	// elements.
+	NTIAResult struct {
+		// Elements is the coverage of each minimum element, in the order of
+		// NTIAElements.
+		Elements []*ElementCoverage
+		// Missing are the document elements (author and timestamp) that are
+		// missing.
+		Missing []string
+		// Components are the components that miss at least one element.
+		Components []*NonConformingComponent
+	}
+
+	// ElementCoverage counts the components, or for document elements the
+	// document, an element is present in.
+	ElementCoverage struct {
+		Element string
+		Present int
+		Total   int
+	}
+
+	NonConformingComponent struct {
+		Component *Component
+		Missing   []string
+	}
+)
+
+// Score is the percentage of element checks that passed, rounded down.
+func (r *NTIAResult) Score() int {
+	var present, total int
+	for _, e := range r.Elements {
+		present += e.Present
+		total += e.Total
+	}
+	if total == 0 {
+		return 0
+	}
+	return present * 100 / total
+}
+
+// Conforms reports whether all elements are present.
+func (r *NTIAResult) Conforms() bool {
+	return len(r.Missing) == 0 && len(r.Components) == 0
+}
+
+// CheckNTIA checks doc for the NTIA minimum elements. Each component,
+// including the metadata component, needs a supplier, name, version, a purl
+// as its unique identifier and a recorded dependency relationship (which may
+// state that it has no dependencies). The document needs an author, either a
+// person or a tool, and a timestamp. The metadata supplier is accepted as
+// the supplier of the metadata component.
+func CheckNTIA(doc *Document) *NTIAResult {
+	coverage := make(map[string]*ElementCoverage, len(NTIAElements))
+	r := &NTIAResult{}
+	for _, e := range NTIAElements {
+		c := &ElementCoverage{Element: e}
+		coverage[e] = c
+		r.Elements = append(r.Elements, c)
+	}
+
+	related := relatedComponents(doc)
+	check := func(c *Component, supplier string) {
+		present := map[string]bool{
+			NTIASupplier:     supplier != "",
+			NTIAName:         c.Name != "",
+			NTIAVersion:      c.Version != "",
+			NTIAIdentifier:   c.PURL != "",
+			NTIADependencies: related[c.BOMRef],
+		}
+		var missing []string
+		for _, e := range ntiaComponentElements {
+			coverage[e].Total++
+			if present[e] {
+				coverage[e].Present++
+			} else {
+				missing = append(missing, e)
+			}
+		}
+		if len(missing) > 0 {
+			r.Components = append(r.Components, &NonConformingComponent{Component: c, Missing: missing})
+		}
+	}
+
+	if c := doc.Metadata.Component; c != nil {
+		supplier := c.Supplier
+		if supplier == "" {
+			supplier = doc.Metadata.Supplier
+		}
+		check(c, supplier)
+	}
+	for _, c := range doc.Components {
+		check(c, c.Supplier)
+	}
+
+	present := map[string]bool{
+		NTIAAuthor:    len(doc.Metadata.Authors) > 0 || len(doc.Metadata.Tools) > 0,
+		NTIATimestamp: !doc.Timestamp.IsZero(),
+	}
+	for _, e := range ntiaDocumentElements {
+		coverage[e].Total++
+		if present[e] {
+			coverage[e].Present++
+		} else {
+			r.Missing = append(r.Missing, e)
+		}
+	}
+
+	return r
+}
+
+// relatedComponents returns the bom-refs of the components that have a
+// recorded dependency relationship, either as dependent or as dependency.
+func relatedComponents(doc *Document) map[string]bool {
+	related := map[string]bool{}
+	for _, d := range doc.Dependencies {
+		related[d.Ref] = true
+		for _, r := range d.DependsOn {
+			related[r] = true
+		}
+	}
+	return related
+}
@@ -0,0 +1,69 @@
+package bom_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+func TestCheckNTIA(t *testing.T) {
+	doc := &bom.Document{
+		Metadata: bom.Metadata{
+			Supplier:  "ACME",
+			Component: &bom.Component{BOMRef: "app", Name: "app", Version: "1.0.0", PURL: "pkg:npm/app@1.0.0"},
+		},
+		Components: []*bom.Component{
+			{BOMRef: "express", Supplier: "Express Foundation", Name: "express", Version: "4.4.0", PURL: "pkg:npm/express@4.4.0"},
+			{BOMRef: "util", Name: "util"},
+		},
+		Dependencies: []*bom.Dependency{
+			{Ref: "app", DependsOn: []string{"express"}},
+		},
+	}
+
+	r := bom.CheckNTIA(doc)
+
+	assert.False(t, r.Conforms())
+	assert.Equal(t, []*bom.ElementCoverage{
+		{Element: bom.NTIASupplier, Present: 2, Total: 3},
+		{Element: bom.NTIAName, Present: 3, Total: 3},
+		{Element: bom.NTIAVersion, Present: 2, Total: 3},
+		{Element: bom.NTIAIdentifier, Present: 2, Total: 3},
+		{Element: bom.NTIADependencies, Present: 2, Total: 3},
+		{Element: bom.NTIAAuthor, Present: 0, Total: 1},
+		{Element: bom.NTIATimestamp, Present: 0, Total: 1},
+	}, r.Elements)
+	assert.Equal(t, []string{bom.NTIAAuthor, bom.NTIATimestamp}, r.Missing)
+	assert.Equal(t, []*bom.NonConformingComponent{
+		{
+			Component: doc.Components[1],
+			Missing:   []string{bom.NTIASupplier, bom.NTIAVersion, bom.NTIAIdentifier, bom.NTIADependencies},
+		},
+	}, r.Components)
+	assert.Equal(t, 64, r.Score())
+}
+
+func TestCheckNTIA_Conforms(t *testing.T) {
+	doc := &bom.Document{
+		Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Metadata: bom.Metadata{
+			Tools: []*bom.Tool{{Vendor: "Snyk", Name: "snyk-cli", Version: "1.2.3"}},
+		},
+		Components: []*bom.Component{
+			{BOMRef: "express", Supplier: "Express Foundation", Name: "express", Version: "4.4.0", PURL: "pkg:npm/express@4.4.0"},
+		},
+		// An empty dependency entry records that the component has no
+		// dependencies.
+		Dependencies: []*bom.Dependency{{Ref: "express"}},
+	}
+
+	r := bom.CheckNTIA(doc)
+
+	assert.True(t, r.Conforms())
+	assert.Empty(t, r.Missing)
+	assert.Empty(t, r.Components)
+	assert.Equal(t, 100, r.Score())
+}
@@ -0,0 +1,61 @@
+package bom
+
+// Component quality categories, as scored by ScoreComponents.
+const (
+	QualityIdentifiers  = "identifier coverage"
+	QualityLicenses     = "license coverage"
+	QualityHashes       = "hash coverage"
+	QualityDependencies = "dependency completeness"
+	QualityVersions     = "versions present"
+)
+
+// ComponentQualityCategories are the component quality categories, in the
+// order ScoreComponents reports them.
+var ComponentQualityCategories = [...]string{
+	QualityIdentifiers, QualityLicenses, QualityHashes, QualityDependencies, QualityVersions,
+}
+
+// CategoryScore counts the components that satisfy a quality category.
+type CategoryScore struct {
+	Category string
+	Present  int
+	Total    int
+}
+
+// Score is the percentage of components that satisfy the category, rounded
+// down. A document without components scores 0.
+func (s *CategoryScore) Score() int {
+	if s.Total == 0 {
+		return 0
+	}
+	return s.Present * 100 / s.Total
+}
+
+// ScoreComponents scores the components of doc, excluding the metadata
+// component, in each of ComponentQualityCategories. A component is covered
+// by an identifier if it has a purl or a CPE name, and its dependencies are
+// complete if it has a recorded dependency relationship.
+func ScoreComponents(doc *Document) []*CategoryScore {
+	scores := make([]*CategoryScore, 0, len(ComponentQualityCategories))
+	for _, cat := range ComponentQualityCategories {
+		scores = append(scores, &CategoryScore{Category: cat, Total: len(doc.Components)})
+	}
+
+	related := relatedComponents(doc)
+	for _, c := range doc.Components {
+		present := [...]bool{
+			c.PURL != "" || c.CPE != "",
+			len(c.Licenses) > 0,
+			len(c.Hashes) > 0,
+			related[c.BOMRef],
+			c.Version != "",
+		}
+		for i, ok := range present {
+			if ok {
+				scores[i].Present++
+			}
+		}
+	}
+
+	return scores
+}
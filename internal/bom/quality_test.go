@@ -0,0 +1,53 @@
+package bom_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+func TestScoreComponents(t *testing.T) {
+	doc := &bom.Document{
+		Metadata: bom.Metadata{
+			Component: &bom.Component{BOMRef: "app", Name: "app", Version: "1.0.0"},
+		},
+		Components: []*bom.Component{
+			{
+				BOMRef:   "express",
+				Name:     "express",
+				Version:  "4.4.0",
+				PURL:     "pkg:npm/express@4.4.0",
+				Licenses: []bom.License{{Expression: "MIT"}},
+				Hashes:   []bom.Hash{{Algorithm: "SHA-256", Value: "2c26b46b68ffc68f"}},
+			},
+			{BOMRef: "openssl", Name: "openssl", Version: "3.0.0", CPE: "cpe:2.3:a:openssl:openssl:3.0.0:*:*:*:*:*:*:*"},
+			{BOMRef: "util", Name: "util"},
+			{BOMRef: "left-pad", Name: "left-pad", Version: "1.3.0", Licenses: []bom.License{{Name: "WTFPL"}}},
+		},
+		Dependencies: []*bom.Dependency{
+			{Ref: "app", DependsOn: []string{"express", "openssl"}},
+		},
+	}
+
+	scores := bom.ScoreComponents(doc)
+
+	assert.Equal(t, []*bom.CategoryScore{
+		{Category: bom.QualityIdentifiers, Present: 2, Total: 4},
+		{Category: bom.QualityLicenses, Present: 2, Total: 4},
+		{Category: bom.QualityHashes, Present: 1, Total: 4},
+		{Category: bom.QualityDependencies, Present: 2, Total: 4},
+		{Category: bom.QualityVersions, Present: 3, Total: 4},
+	}, scores)
+	assert.Equal(t, 75, scores[4].Score())
+}
+
+func TestScoreComponents_NoComponents(t *testing.T) {
+	scores := bom.ScoreComponents(&bom.Document{})
+
+	assert.Len(t, scores, len(bom.ComponentQualityCategories))
+	for _, s := range scores {
+		assert.Equal(t, 0, s.Score())
+	}
+}
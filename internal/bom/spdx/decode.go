@@ -0,0 +1,202 @@
+package spdx
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+// Relationship types that are read as dependencies, in addition to
+// DEPENDS_ON. They point from the dependency to the dependent.
+var inverseDependencyRelationships = [...]string{
+	"DEPENDENCY_OF",
+	"DEV_DEPENDENCY_OF",
+	"OPTIONAL_DEPENDENCY_OF",
+	"PROVIDED_DEPENDENCY_OF",
+	"RUNTIME_DEPENDENCY_OF",
+	"BUILD_DEPENDENCY_OF",
+	"TEST_DEPENDENCY_OF",
+}
+
+// Decode reads an SPDX 2.x JSON document. Packages become components
+// identified by their SPDX identifier, and the first package the document
+// describes becomes the metadata component.
+func Decode(b []byte) (*bom.Document, error) {
+	var in document
+	if err := json.Unmarshal(b, &in); err != nil {
+		return nil, fmt.Errorf("failed to decode SPDX document: %w", err)
+	}
+	if !strings.HasPrefix(in.SPDXVersion, "SPDX-2.") {
+		return nil, fmt.Errorf("unsupported SPDX version %q", in.SPDXVersion)
+	}
+
+	doc := &bom.Document{}
+	// Timestamps that do not parse are dropped rather than failing the whole
+	// document.
+	doc.Timestamp, _ = time.Parse(time.RFC3339, in.CreationInfo.Created) //nolint:errcheck // See above
+	decodeCreators(in.CreationInfo.Creators, &doc.Metadata)
+
+	components := make(map[string]*bom.Component, len(in.Packages))
+	for _, p := range in.Packages {
+		c := toComponent(p, in.ExtractedLicenses)
+		components[p.SPDXID] = c
+		doc.AddComponent(c)
+	}
+
+	for _, r := range in.Relationships {
+		switch {
+		case r.RelationshipType == relationshipDescribes && r.SPDXElementID == documentID:
+			if c, ok := components[r.RelatedSPDXElement]; ok && doc.Metadata.Component == nil {
+				doc.Metadata.Component = c
+				// The supplier of the described package is that of the
+				// document.
+				doc.Metadata.Supplier, c.Supplier = c.Supplier, ""
+				if p := findPackage(in.Packages, r.RelatedSPDXElement); p != nil {
+					doc.Metadata.Manufacturer = agentName(p.Originator)
+				}
+			}
+		case r.RelationshipType == relationshipDependsOn:
+			doc.AddDependencies(r.SPDXElementID, r.RelatedSPDXElement)
+		case isInverseDependency(r.RelationshipType):
+			doc.AddDependencies(r.RelatedSPDXElement, r.SPDXElementID)
+		}
+	}
+
+	if root := doc.Metadata.Component; root != nil {
+		doc.Components = removeComponent(doc.Components, root)
+	}
+
+	return doc, nil
+}
+
+// decodeCreators reads the tools and persons that created the document.
+func decodeCreators(creators []string, m *bom.Metadata) {
+	var vendor string
+	for _, c := range creators {
+		kind, name, ok := strings.Cut(c, ": ")
+		if !ok {
+			continue
+		}
+		switch kind {
+		case "Tool":
+			m.Tools = append(m.Tools, parseTool(name))
+		case "Person":
+			m.Authors = append(m.Authors, name)
+		case "Organization":
+			if vendor == "" {
+				vendor = name
+			}
+		}
+	}
+	for _, t := range m.Tools {
+		t.Vendor = vendor
+	}
+}
+
+// parseTool splits a creator tool `name-version` as written by toolName.
+func parseTool(s string) *bom.Tool {
+	if i := strings.LastIndex(s, "-"); i > 0 {
+		return &bom.Tool{Name: s[:i], Version: s[i+1:]}
+	}
+	return &bom.Tool{Name: s}
+}
+
+func toComponent(p *pkg, extracted []*extractedLicense) *bom.Component {
+	c := &bom.Component{
+		BOMRef:   p.SPDXID,
+		Type:     bom.ComponentTypeLibrary,
+		Supplier: agentName(p.Supplier),
+		Name:     p.Name,
+		Version:  p.VersionInfo,
+	}
+	if p.PrimaryPurpose != "" {
+		c.Type = bom.ComponentType(strings.ToLower(p.PrimaryPurpose))
+	}
+	for _, r := range p.ExternalRefs {
+		switch r.ReferenceType {
+		case refTypePURL:
+			c.PURL = cmp.Or(c.PURL, r.ReferenceLocator)
+		case refTypeCPE23, refTypeCPE22:
+			c.CPE = cmp.Or(c.CPE, r.ReferenceLocator)
+		}
+	}
+	for _, cs := range p.Checksums {
+		c.Hashes = append(c.Hashes, bom.Hash{Algorithm: hashAlgorithm(cs.Algorithm), Value: cs.ChecksumValue})
+	}
+	if l, ok := packageLicense(p, extracted); ok {
+		c.Licenses = []bom.License{l}
+	}
+	return c
+}
+
+// packageLicense returns the declared license of a package, falling back to
+// the concluded one. A LicenseRef to a license without an SPDX identifier is
+// read as that license's name.
+func packageLicense(p *pkg, extracted []*extractedLicense) (bom.License, bool) {
+	expr := p.LicenseDeclared
+	if !isLicenseExpression(expr) {
+		expr = p.LicenseConcluded
+	}
+	if !isLicenseExpression(expr) {
+		return bom.License{}, false
+	}
+	if l := findExtractedLicense(extracted, expr); l != nil && l.Name != "" {
+		return bom.License{Name: l.Name}, true
+	}
+	return bom.License{Expression: expr}, true
+}
+
+func isLicenseExpression(s string) bool {
+	return s != "" && s != noAssertion && s != licenseNone
+}
+
+// hashAlgorithm returns the name of an SPDX 2.x checksum algorithm.
+func hashAlgorithm(spdxAlgorithm string) string {
+	for alg, a := range v2ChecksumAlgorithms {
+		if a == spdxAlgorithm {
+			return alg
+		}
+	}
+	return spdxAlgorithm
+}
+
+// agentName returns the name of a package supplier or originator, which is
+// either NOASSERTION or prefixed by its kind, e.g. `Organization: Snyk`.
+func agentName(s string) string {
+	if _, name, ok := strings.Cut(s, ": "); ok {
+		return name
+	}
+	return ""
+}
+
+func findPackage(pkgs []*pkg, id string) *pkg {
+	for _, p := range pkgs {
+		if p.SPDXID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func isInverseDependency(relationshipType string) bool {
+	for _, t := range inverseDependencyRelationships {
+		if t == relationshipType {
+			return true
+		}
+	}
+	return false
+}
+
+func removeComponent(cs []*bom.Component, c *bom.Component) []*bom.Component {
+	out := cs[:0]
+	for _, x := range cs {
+		if x != c {
+			out = append(out, x)
+		}
+	}
+	return out
+}
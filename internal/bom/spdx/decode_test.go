@@ -0,0 +1,123 @@
+package spdx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/bom/spdx"
+)
+
+func TestDecode(t *testing.T) {
+	doc := newTestDocument()
+	doc.Metadata.Supplier = "ACME"
+	b, _, err := spdx.Encode(doc, "2.3", "json")
+	require.NoError(t, err)
+
+	decoded, err := spdx.Decode(b)
+
+	require.NoError(t, err)
+	assert.Equal(t, doc.Timestamp, decoded.Timestamp)
+	assert.Equal(t, []*bom.Tool{{Vendor: "Snyk", Name: "snyk-cli", Version: "1.2.3"}}, decoded.Metadata.Tools)
+	assert.Equal(t, "ACME", decoded.Metadata.Supplier)
+	assert.Equal(t, &bom.Component{
+		BOMRef:  "SPDXRef-1-goof-1.0.0",
+		Type:    bom.ComponentTypeApplication,
+		Name:    "goof",
+		Version: "1.0.0",
+		PURL:    "pkg:npm/goof@1.0.0",
+	}, decoded.Metadata.Component)
+	assert.Equal(t, []*bom.Component{
+		{BOMRef: "SPDXRef-2-snyk-express-4.4.0", Type: bom.ComponentTypeLibrary, Name: "@snyk/express", Version: "4.4.0", PURL: "pkg:npm/%40snyk/express@4.4.0"},
+		{BOMRef: "SPDXRef-3-ws-1.0.0", Type: bom.ComponentTypeLibrary, Name: "ws", Version: "1.0.0", PURL: "pkg:npm/ws@1.0.0"},
+	}, decoded.Components)
+	assert.Equal(t, []*bom.Dependency{
+		{Ref: "SPDXRef-1-goof-1.0.0", DependsOn: []string{"SPDXRef-2-snyk-express-4.4.0"}},
+		{Ref: "SPDXRef-2-snyk-express-4.4.0", DependsOn: []string{"SPDXRef-3-ws-1.0.0"}},
+	}, decoded.Dependencies)
+}
+
+func TestDecode_LicensesAndHashes(t *testing.T) {
+	doc := newTestDocument()
+	doc.Components[0].Licenses = []bom.License{{Name: "ACME Commercial License"}}
+	doc.Components[0].Hashes = []bom.Hash{{Algorithm: "SHA-256", Value: "2c26b46b"}, {Algorithm: "MD2", Value: "d9cce882"}}
+	doc.Components[1].Licenses = []bom.License{{Expression: "MIT"}, {Expression: "ISC OR Apache-2.0"}}
+	b, _, err := spdx.Encode(doc, "2.3", "json")
+	require.NoError(t, err)
+
+	decoded, err := spdx.Decode(b)
+
+	require.NoError(t, err)
+	require.Len(t, decoded.Components, 2)
+	assert.Equal(t, []bom.License{{Name: "ACME Commercial License"}}, decoded.Components[0].Licenses)
+	assert.Equal(t, doc.Components[0].Hashes, decoded.Components[0].Hashes)
+	assert.Equal(t, []bom.License{{Expression: "MIT AND (ISC OR Apache-2.0)"}}, decoded.Components[1].Licenses)
+}
+
+func TestDecode_CPE(t *testing.T) {
+	doc := newTestDocument()
+	doc.Components[0].CPE = "cpe:2.3:a:expressjs:express:4.4.0:*:*:*:*:node.js:*:*"
+	doc.Components[1].CPE = "cpe:/a:websockets:ws:1.0.0"
+	b, _, err := spdx.Encode(doc, "2.3", "json")
+	require.NoError(t, err)
+
+	decoded, err := spdx.Decode(b)
+
+	require.NoError(t, err)
+	assert.Empty(t, decoded.Metadata.Component.CPE)
+	assert.Equal(t, doc.Components[0].CPE, decoded.Components[0].CPE)
+	assert.Equal(t, doc.Components[1].CPE, decoded.Components[1].CPE)
+	assert.Equal(t, "pkg:npm/%40snyk/express@4.4.0", decoded.Components[0].PURL)
+}
+
+func TestDecode_Suppliers(t *testing.T) {
+	doc, err := spdx.Decode([]byte(`{
+		"spdxVersion": "SPDX-2.3",
+		"packages": [
+			{"SPDXID": "SPDXRef-app", "name": "app", "supplier": "Organization: ACME"},
+			{"SPDXID": "SPDXRef-a", "name": "a", "supplier": "Person: Jane Doe"},
+			{"SPDXID": "SPDXRef-b", "name": "b", "supplier": "NOASSERTION"}
+		],
+		"relationships": [{"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES", "relatedSpdxElement": "SPDXRef-app"}]
+	}`))
+
+	require.NoError(t, err)
+	assert.Equal(t, "ACME", doc.Metadata.Supplier)
+	assert.Empty(t, doc.Metadata.Component.Supplier)
+	assert.Equal(t, "Jane Doe", doc.Components[0].Supplier)
+	assert.Empty(t, doc.Components[1].Supplier)
+}
+
+func TestDecode_ConcludedLicense(t *testing.T) {
+	doc, err := spdx.Decode([]byte(`{
+		"spdxVersion": "SPDX-2.2",
+		"packages": [
+			{"SPDXID": "SPDXRef-a", "name": "a", "licenseDeclared": "NOASSERTION", "licenseConcluded": "MIT"},
+			{"SPDXID": "SPDXRef-b", "name": "b", "licenseDeclared": "NONE"}
+		]
+	}`))
+
+	require.NoError(t, err)
+	assert.Equal(t, []bom.License{{Expression: "MIT"}}, doc.Components[0].Licenses)
+	assert.Nil(t, doc.Components[1].Licenses)
+}
+
+func TestDecode_InverseDependencies(t *testing.T) {
+	doc, err := spdx.Decode([]byte(`{
+		"spdxVersion": "SPDX-2.2",
+		"packages": [{"SPDXID": "SPDXRef-app", "name": "app"}, {"SPDXID": "SPDXRef-lib", "name": "lib"}],
+		"relationships": [{"spdxElementId": "SPDXRef-lib", "relationshipType": "DEV_DEPENDENCY_OF", "relatedSpdxElement": "SPDXRef-app"}]
+	}`))
+
+	require.NoError(t, err)
+	assert.Nil(t, doc.Metadata.Component)
+	assert.Equal(t, []*bom.Dependency{{Ref: "SPDXRef-app", DependsOn: []string{"SPDXRef-lib"}}}, doc.Dependencies)
+}
+
+func TestDecode_UnsupportedVersion(t *testing.T) {
+	_, err := spdx.Decode([]byte(`{"spdxVersion":"SPDX-3.0"}`))
+
+	assert.ErrorContains(t, err, `unsupported SPDX version "SPDX-3.0"`)
+}
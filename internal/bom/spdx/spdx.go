@@ -0,0 +1,109 @@
+// Package spdx encodes bom documents as SPDX 2.3 JSON and SPDX 3.0 JSON-LD,
+// and decodes SPDX 2.x JSON documents.
+package spdx
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+const (
+	MIMETypeJSON = "application/spdx+json"
+
+	SpecVersion2_3 = "2.3"
+	SpecVersion3_0 = "3.0"
+
+	namespaceBase = "https://snyk.io/spdx/"
+)
+
+var invalidIDChars = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+
+// Encode renders doc as an SPDX document of the given spec version and
+// encoding. It returns the document and its MIME type. The metadata component
+// is the package the document describes.
+func Encode(doc *bom.Document, specVersion, encoding string) (b []byte, mimeType string, err error) {
+	if encoding != bom.EncodingJSON {
+		return nil, "", fmt.Errorf("unsupported SPDX encoding %q", encoding)
+	}
+
+	switch specVersion {
+	case SpecVersion2_3:
+		b, err = encodeV2(doc)
+	case SpecVersion3_0:
+		b, err = encodeV3(doc)
+	default:
+		return nil, "", fmt.Errorf("unsupported SPDX spec version %q", specVersion)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return b, MIMETypeJSON, nil
+}
+
+// packageID derives an SPDX identifier from a component's bom-ref. The index
+// keeps identifiers unique when bom-refs only differ in characters SPDX does
+// not allow.
+func packageID(i int, c *bom.Component) string {
+	return fmt.Sprintf("SPDXRef-%d-%s", i, strings.Trim(invalidIDChars.ReplaceAllString(c.BOMRef, "-"), "-"))
+}
+
+func documentName(doc *bom.Document) string {
+	root := doc.Metadata.Component
+	if root == nil {
+		return "sbom"
+	}
+	if root.Version == "" {
+		return root.Name
+	}
+	return root.Name + "@" + root.Version
+}
+
+func documentNamespace(doc *bom.Document) string {
+	name := invalidIDChars.ReplaceAllString(documentName(doc), "-")
+	return namespaceBase + name + "-" + strings.TrimPrefix(doc.SerialNumber, "urn:uuid:")
+}
+
+// propertiesComment renders document properties, which have no equivalent in
+// SPDX, as one `name: value` line each.
+func propertiesComment(props []bom.Property) string {
+	lines := make([]string, 0, len(props))
+	for _, p := range props {
+		lines = append(lines, p.Name+": "+p.Value)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// vcsComment renders the source revision the document was generated from,
+// which is recorded as an annotation of the described package, or of the
+// document if it describes none.
+func vcsComment(v *bom.VCS) string {
+	lines := make([]string, 0, 4)
+	if v.URL != "" {
+		lines = append(lines, "Git remote: "+v.URL)
+	}
+	lines = append(lines, "Git commit: "+v.Commit)
+	if v.Branch != "" {
+		lines = append(lines, "Git branch: "+v.Branch)
+	}
+	lines = append(lines, "Git dirty: "+strconv.FormatBool(v.Dirty))
+	return strings.Join(lines, "\n")
+}
+
+func toolName(t *bom.Tool) string {
+	if t.Version == "" {
+		return t.Name
+	}
+	return t.Name + "-" + t.Version
+}
+
+func formatTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,407 @@
+package spdx_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bradleyjkemp/cupaloy/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/bom/spdx"
+)
+
+var snapshotter = cupaloy.New(cupaloy.SnapshotSubdirectory("testdata/snapshots"))
+
+func newTestDocument() *bom.Document {
+	return &bom.Document{
+		SerialNumber: "urn:uuid:1b4e28ba-2fa1-4d3b-a3f5-ef19b5a7633b",
+		Timestamp:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Metadata: bom.Metadata{
+			Tools: []*bom.Tool{{Vendor: "Snyk", Name: "snyk-cli", Version: "1.2.3"}},
+			Component: &bom.Component{
+				BOMRef:  "goof@1.0.0",
+				Type:    bom.ComponentTypeApplication,
+				Name:    "goof",
+				Version: "1.0.0",
+				PURL:    "pkg:npm/goof@1.0.0",
+			},
+			Properties: []bom.Property{{Name: "snyk:scan_error", Value: "project/pom.xml: missing lockfile"}},
+		},
+		Components: []*bom.Component{
+			{BOMRef: "@snyk/express@4.4.0", Type: bom.ComponentTypeLibrary, Name: "@snyk/express", Version: "4.4.0", PURL: "pkg:npm/%40snyk/express@4.4.0"},
+			{BOMRef: "ws@1.0.0", Type: bom.ComponentTypeLibrary, Name: "ws", Version: "1.0.0", PURL: "pkg:npm/ws@1.0.0"},
+		},
+		Dependencies: []*bom.Dependency{
+			{Ref: "goof@1.0.0", DependsOn: []string{"@snyk/express@4.4.0"}},
+			{Ref: "@snyk/express@4.4.0", DependsOn: []string{"ws@1.0.0"}},
+			{Ref: "ws@1.0.0"},
+		},
+	}
+}
+
+func TestEncode(t *testing.T) {
+	for _, specVersion := range []string{"2.3", "3.0"} {
+		t.Run(specVersion, func(t *testing.T) {
+			b, mimeType, err := spdx.Encode(newTestDocument(), specVersion, "json")
+
+			require.NoError(t, err)
+			assert.Equal(t, spdx.MIMETypeJSON, mimeType)
+			snapshotter.SnapshotT(t, string(b))
+		})
+	}
+}
+
+func TestEncode_Relationships(t *testing.T) {
+	b, _, err := spdx.Encode(newTestDocument(), "2.3", "json")
+	require.NoError(t, err)
+
+	var doc struct {
+		Relationships []struct {
+			SPDXElementID      string `json:"spdxElementId"`
+			RelationshipType   string `json:"relationshipType"`
+			RelatedSPDXElement string `json:"relatedSpdxElement"`
+		} `json:"relationships"`
+	}
+	require.NoError(t, json.Unmarshal(b, &doc))
+
+	type rel struct{ from, typ, to string }
+	rels := make([]rel, 0, len(doc.Relationships))
+	for _, r := range doc.Relationships {
+		rels = append(rels, rel{r.SPDXElementID, r.RelationshipType, r.RelatedSPDXElement})
+	}
+	assert.Equal(t, []rel{
+		{"SPDXRef-DOCUMENT", "DESCRIBES", "SPDXRef-1-goof-1.0.0"},
+		{"SPDXRef-1-goof-1.0.0", "DEPENDS_ON", "SPDXRef-2-snyk-express-4.4.0"},
+		{"SPDXRef-2-snyk-express-4.4.0", "DEPENDS_ON", "SPDXRef-3-ws-1.0.0"},
+	}, rels)
+}
+
+func TestEncode_V3Relationships(t *testing.T) {
+	b, _, err := spdx.Encode(newTestDocument(), "3.0", "json")
+	require.NoError(t, err)
+
+	var doc struct {
+		Graph []struct {
+			Type               string   `json:"type"`
+			SPDXID             string   `json:"spdxId"`
+			ProfileConformance []string `json:"profileConformance"`
+			RootElement        []string `json:"rootElement"`
+			From               string   `json:"from"`
+			RelationshipType   string   `json:"relationshipType"`
+			To                 []string `json:"to"`
+		} `json:"@graph"`
+	}
+	require.NoError(t, json.Unmarshal(b, &doc))
+
+	const ns = "https://snyk.io/spdx/goof-1.0.0-1b4e28ba-2fa1-4d3b-a3f5-ef19b5a7633b#"
+	type rel struct{ from, typ, to string }
+	var rels []rel
+	for _, e := range doc.Graph {
+		switch e.Type {
+		case "Relationship":
+			for _, to := range e.To {
+				rels = append(rels, rel{e.From, e.RelationshipType, to})
+			}
+		case "SpdxDocument":
+			assert.Equal(t, []string{"core", "software"}, e.ProfileConformance)
+			assert.Equal(t, []string{ns + "SPDXRef-SBOM"}, e.RootElement)
+		case "software_Sbom":
+			assert.Equal(t, []string{ns + "SPDXRef-1-goof-1.0.0"}, e.RootElement)
+		}
+	}
+	assert.Equal(t, []rel{
+		{ns + "SPDXRef-1-goof-1.0.0", "dependsOn", ns + "SPDXRef-2-snyk-express-4.4.0"},
+		{ns + "SPDXRef-2-snyk-express-4.4.0", "dependsOn", ns + "SPDXRef-3-ws-1.0.0"},
+	}, rels)
+}
+
+func TestEncode_Metadata(t *testing.T) {
+	doc := newTestDocument()
+	doc.Metadata.Supplier = "ACME"
+	doc.Metadata.Authors = []string{"Jane Doe"}
+	doc.Metadata.Manufacturer = "ACME Corp"
+	doc.Metadata.Lifecycles = []string{"pre-build", "build"}
+
+	b, _, err := spdx.Encode(doc, "2.3", "json")
+	require.NoError(t, err)
+
+	var v2 struct {
+		CreationInfo struct {
+			Creators []string `json:"creators"`
+			Comment  string   `json:"comment"`
+		} `json:"creationInfo"`
+		Packages []struct {
+			Supplier   string `json:"supplier"`
+			Originator string `json:"originator"`
+		} `json:"packages"`
+	}
+	require.NoError(t, json.Unmarshal(b, &v2))
+	assert.Equal(t, []string{"Person: Jane Doe", "Tool: snyk-cli-1.2.3", "Organization: Snyk"}, v2.CreationInfo.Creators)
+	assert.Equal(t, "Lifecycle phases: pre-build, build", v2.CreationInfo.Comment)
+	assert.Equal(t, "Organization: ACME", v2.Packages[0].Supplier)
+	assert.Equal(t, "Organization: ACME Corp", v2.Packages[0].Originator)
+
+	b, _, err = spdx.Encode(doc, "3.0", "json")
+	require.NoError(t, err)
+
+	var v3 struct {
+		Graph []struct {
+			Type         string   `json:"type"`
+			SPDXID       string   `json:"spdxId"`
+			Name         string   `json:"name"`
+			CreatedBy    []string `json:"createdBy"`
+			SuppliedBy   string   `json:"suppliedBy"`
+			OriginatedBy []string `json:"originatedBy"`
+			SBOMType     []string `json:"software_sbomType"`
+		} `json:"@graph"`
+	}
+	require.NoError(t, json.Unmarshal(b, &v3))
+
+	const ns = "https://snyk.io/spdx/goof-1.0.0-1b4e28ba-2fa1-4d3b-a3f5-ef19b5a7633b#"
+	for _, e := range v3.Graph {
+		switch {
+		case e.Type == "CreationInfo":
+			assert.Equal(t, []string{ns + "SPDXRef-Person-1", ns + "SPDXRef-Organization"}, e.CreatedBy)
+		case e.SPDXID == ns+"SPDXRef-Supplier":
+			assert.Equal(t, "ACME", e.Name)
+		case e.SPDXID == ns+"SPDXRef-1-goof-1.0.0":
+			assert.Equal(t, ns+"SPDXRef-Supplier", e.SuppliedBy)
+			assert.Equal(t, []string{ns + "SPDXRef-Manufacturer"}, e.OriginatedBy)
+		case e.Type == "software_Sbom":
+			assert.Equal(t, []string{"source", "build"}, e.SBOMType)
+		}
+	}
+}
+
+func TestEncode_ComponentSupplier(t *testing.T) {
+	doc := newTestDocument()
+	doc.Metadata.Supplier = "ACME"
+	doc.Components[0].Supplier = "Express Foundation"
+	doc.Components[1].Supplier = "ACME"
+
+	b, _, err := spdx.Encode(doc, "2.3", "json")
+	require.NoError(t, err)
+
+	var v2 struct {
+		Packages []struct {
+			Supplier string `json:"supplier"`
+		} `json:"packages"`
+	}
+	require.NoError(t, json.Unmarshal(b, &v2))
+	require.Len(t, v2.Packages, 3)
+	assert.Equal(t, "Organization: ACME", v2.Packages[0].Supplier)
+	assert.Equal(t, "Organization: Express Foundation", v2.Packages[1].Supplier)
+	assert.Equal(t, "Organization: ACME", v2.Packages[2].Supplier)
+
+	b, _, err = spdx.Encode(doc, "3.0", "json")
+	require.NoError(t, err)
+
+	var v3 struct {
+		Graph []struct {
+			Type       string `json:"type"`
+			SPDXID     string `json:"spdxId"`
+			Name       string `json:"name"`
+			SuppliedBy string `json:"suppliedBy"`
+		} `json:"@graph"`
+	}
+	require.NoError(t, json.Unmarshal(b, &v3))
+
+	const ns = "https://snyk.io/spdx/goof-1.0.0-1b4e28ba-2fa1-4d3b-a3f5-ef19b5a7633b#"
+	suppliedBy := map[string]string{}
+	names := map[string]string{}
+	for _, e := range v3.Graph {
+		suppliedBy[e.SPDXID] = e.SuppliedBy
+		names[e.SPDXID] = e.Name
+	}
+	assert.Equal(t, ns+"SPDXRef-Supplier", suppliedBy[ns+"SPDXRef-1-goof-1.0.0"])
+	assert.Equal(t, ns+"SPDXRef-Supplier-2", suppliedBy[ns+"SPDXRef-2-snyk-express-4.4.0"])
+	assert.Equal(t, ns+"SPDXRef-Supplier", suppliedBy[ns+"SPDXRef-3-ws-1.0.0"])
+	assert.Equal(t, "Express Foundation", names[ns+"SPDXRef-Supplier-2"])
+}
+
+func TestEncode_CPE(t *testing.T) {
+	doc := newTestDocument()
+	doc.Components[0].CPE = "cpe:2.3:a:expressjs:express:4.4.0:*:*:*:*:node.js:*:*"
+	doc.Components[1].CPE = "cpe:/a:websockets:ws:1.0.0"
+
+	b, _, err := spdx.Encode(doc, "2.3", "json")
+	require.NoError(t, err)
+
+	type externalRef struct {
+		ReferenceCategory string `json:"referenceCategory"`
+		ReferenceType     string `json:"referenceType"`
+		ReferenceLocator  string `json:"referenceLocator"`
+	}
+	var v2 struct {
+		Packages []struct {
+			ExternalRefs []externalRef `json:"externalRefs"`
+		} `json:"packages"`
+	}
+	require.NoError(t, json.Unmarshal(b, &v2))
+	require.Len(t, v2.Packages, 3)
+	assert.Equal(t, []externalRef{
+		{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:npm/%40snyk/express@4.4.0"},
+		{ReferenceCategory: "SECURITY", ReferenceType: "cpe23Type", ReferenceLocator: doc.Components[0].CPE},
+	}, v2.Packages[1].ExternalRefs)
+	assert.Equal(t, externalRef{ReferenceCategory: "SECURITY", ReferenceType: "cpe22Type", ReferenceLocator: doc.Components[1].CPE},
+		v2.Packages[2].ExternalRefs[1])
+
+	b, _, err = spdx.Encode(doc, "3.0", "json")
+	require.NoError(t, err)
+
+	type externalIdentifier struct {
+		Type                   string `json:"type"`
+		ExternalIdentifierType string `json:"externalIdentifierType"`
+		Identifier             string `json:"identifier"`
+	}
+	var v3 struct {
+		Graph []struct {
+			SPDXID             string               `json:"spdxId"`
+			ExternalIdentifier []externalIdentifier `json:"externalIdentifier"`
+		} `json:"@graph"`
+	}
+	require.NoError(t, json.Unmarshal(b, &v3))
+
+	const ns = "https://snyk.io/spdx/goof-1.0.0-1b4e28ba-2fa1-4d3b-a3f5-ef19b5a7633b#"
+	identifiers := map[string][]externalIdentifier{}
+	for _, e := range v3.Graph {
+		identifiers[e.SPDXID] = e.ExternalIdentifier
+	}
+	assert.Empty(t, identifiers[ns+"SPDXRef-1-goof-1.0.0"])
+	assert.Equal(t, []externalIdentifier{{Type: "ExternalIdentifier", ExternalIdentifierType: "cpe23", Identifier: doc.Components[0].CPE}},
+		identifiers[ns+"SPDXRef-2-snyk-express-4.4.0"])
+	assert.Equal(t, []externalIdentifier{{Type: "ExternalIdentifier", ExternalIdentifierType: "cpe22", Identifier: doc.Components[1].CPE}},
+		identifiers[ns+"SPDXRef-3-ws-1.0.0"])
+}
+
+func TestEncode_VCS(t *testing.T) {
+	doc := newTestDocument()
+	doc.Metadata.VCS = &bom.VCS{
+		URL:    "https://github.com/snyk/goof",
+		Commit: "4b825dc642cb6eb9a060e54bf8d69288fbee4904",
+		Dirty:  false,
+	}
+	const comment = "Git remote: https://github.com/snyk/goof\n" +
+		"Git commit: 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n" +
+		"Git dirty: false"
+
+	b, _, err := spdx.Encode(doc, "2.3", "json")
+	require.NoError(t, err)
+
+	var v2 struct {
+		Packages []struct {
+			Annotations []struct {
+				AnnotationDate string `json:"annotationDate"`
+				AnnotationType string `json:"annotationType"`
+				Annotator      string `json:"annotator"`
+				Comment        string `json:"comment"`
+			} `json:"annotations"`
+		} `json:"packages"`
+	}
+	require.NoError(t, json.Unmarshal(b, &v2))
+	require.Len(t, v2.Packages[0].Annotations, 1)
+	assert.Equal(t, "2024-01-02T03:04:05Z", v2.Packages[0].Annotations[0].AnnotationDate)
+	assert.Equal(t, "OTHER", v2.Packages[0].Annotations[0].AnnotationType)
+	assert.Equal(t, "Tool: snyk-cli-1.2.3", v2.Packages[0].Annotations[0].Annotator)
+	assert.Equal(t, comment, v2.Packages[0].Annotations[0].Comment)
+	assert.Empty(t, v2.Packages[1].Annotations)
+
+	b, _, err = spdx.Encode(doc, "3.0", "json")
+	require.NoError(t, err)
+
+	var v3 struct {
+		Graph []struct {
+			Type           string `json:"type"`
+			AnnotationType string `json:"annotationType"`
+			Subject        string `json:"subject"`
+			Statement      string `json:"statement"`
+		} `json:"@graph"`
+	}
+	require.NoError(t, json.Unmarshal(b, &v3))
+
+	const ns = "https://snyk.io/spdx/goof-1.0.0-1b4e28ba-2fa1-4d3b-a3f5-ef19b5a7633b#"
+	var annotations int
+	for _, e := range v3.Graph {
+		if e.Type != "Annotation" {
+			continue
+		}
+		annotations++
+		assert.Equal(t, "other", e.AnnotationType)
+		assert.Equal(t, ns+"SPDXRef-1-goof-1.0.0", e.Subject)
+		assert.Equal(t, comment, e.Statement)
+	}
+	assert.Equal(t, 1, annotations)
+}
+
+func TestEncode_VCSWithoutMetadataComponent(t *testing.T) {
+	doc := newTestDocument()
+	doc.Metadata.Component = nil
+	doc.Metadata.VCS = &bom.VCS{URL: "https://github.com/snyk/goof", Commit: "4b825dc642cb6eb9a060e54bf8d69288fbee4904"}
+	const comment = "Git remote: https://github.com/snyk/goof\n" +
+		"Git commit: 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n" +
+		"Git dirty: false"
+
+	b, _, err := spdx.Encode(doc, "2.3", "json")
+	require.NoError(t, err)
+
+	var v2 struct {
+		Annotations []struct {
+			Comment string `json:"comment"`
+		} `json:"annotations"`
+	}
+	require.NoError(t, json.Unmarshal(b, &v2))
+	require.Len(t, v2.Annotations, 1)
+	assert.Equal(t, comment, v2.Annotations[0].Comment)
+
+	b, _, err = spdx.Encode(doc, "3.0", "json")
+	require.NoError(t, err)
+
+	var v3 struct {
+		Graph []struct {
+			Type    string `json:"type"`
+			Subject string `json:"subject"`
+		} `json:"@graph"`
+	}
+	require.NoError(t, json.Unmarshal(b, &v3))
+	var subjects []string
+	for _, e := range v3.Graph {
+		if e.Type == "Annotation" {
+			subjects = append(subjects, e.Subject)
+		}
+	}
+	assert.Equal(t, []string{"https://snyk.io/spdx/sbom-1b4e28ba-2fa1-4d3b-a3f5-ef19b5a7633b#SPDXRef-SBOM"}, subjects)
+}
+
+func TestEncode_LicensesAndHashes(t *testing.T) {
+	doc := newTestDocument()
+	doc.Components[0].Licenses = []bom.License{{Expression: "MIT"}, {Name: "ACME Commercial License"}}
+	doc.Components[0].Hashes = []bom.Hash{
+		{Algorithm: "SHA-256", Value: "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"},
+		{Algorithm: "MD2", Value: "d9cce882ee690a5c1ce70beff3a78c77"},
+	}
+	doc.Components[1].Licenses = []bom.License{{Expression: "MIT OR Apache-2.0"}, {Name: "ACME Commercial License"}}
+
+	for _, specVersion := range []string{"2.3", "3.0"} {
+		t.Run(specVersion, func(t *testing.T) {
+			b, _, err := spdx.Encode(doc, specVersion, "json")
+
+			require.NoError(t, err)
+			snapshotter.SnapshotT(t, string(b))
+		})
+	}
+}
+
+func TestEncode_UnsupportedSpecVersion(t *testing.T) {
+	_, _, err := spdx.Encode(newTestDocument(), "2.2", "json")
+
+	assert.ErrorContains(t, err, `unsupported SPDX spec version "2.2"`)
+}
+
+func TestEncode_UnsupportedEncoding(t *testing.T) {
+	_, _, err := spdx.Encode(newTestDocument(), "2.3", "xml")
+
+	assert.ErrorContains(t, err, `unsupported SPDX encoding "xml"`)
+}
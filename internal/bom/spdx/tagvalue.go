@@ -0,0 +1,202 @@
+package spdx
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// tagValue is a tag of a tag-value document, along with the line it starts
+// on.
+type tagValue struct {
+	tag   string
+	value string
+	line  int
+}
+
+// Sections of a tag-value document, which the tags following a section's
+// first tag describe.
+const (
+	sectionDocument = iota
+	sectionPackage
+	sectionLicense
+	// sectionSkipped are files and snippets, which are not converted.
+	sectionSkipped
+)
+
+// IsTagValue reports whether b looks like an SPDX tag-value document, which
+// starts with its SPDXVersion tag.
+func IsTagValue(b []byte) bool {
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return strings.HasPrefix(line, "SPDXVersion:")
+	}
+	return false
+}
+
+// TagValueToJSON converts an SPDX 2.x tag-value document to an SPDX JSON
+// document of the same spec version. The creation info, packages, extracted
+// licenses and relationships are converted. Files and snippets are dropped,
+// so packages are marked as not file-analyzed.
+func TagValueToJSON(b []byte) ([]byte, error) {
+	tvs, err := readTagValues(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SPDX tag-value document: %w", err)
+	}
+
+	doc := &document{Packages: []*pkg{}, Relationships: []*relationship{}}
+	var p *pkg
+	var l *extractedLicense
+	section := sectionDocument
+	for _, tv := range tvs {
+		switch tv.tag {
+		case "PackageName":
+			p = &pkg{Name: tv.value, DownloadLocation: noAssertion}
+			doc.Packages = append(doc.Packages, p)
+			section = sectionPackage
+		case "LicenseID":
+			l = &extractedLicense{LicenseID: tv.value}
+			doc.ExtractedLicenses = append(doc.ExtractedLicenses, l)
+			section = sectionLicense
+		case "FileName", "SnippetSPDXID":
+			section = sectionSkipped
+		case "Relationship":
+			fields := strings.Fields(tv.value)
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("line %d: invalid relationship %q", tv.line, tv.value)
+			}
+			doc.Relationships = append(doc.Relationships, &relationship{
+				SPDXElementID: fields[0], RelationshipType: fields[1], RelatedSPDXElement: fields[2],
+			})
+		default:
+			err = decodeTag(doc, p, l, section, tv)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(doc)
+}
+
+// decodeTag reads a tag of the given section into the document, the current
+// package or the current extracted license. Unknown tags are ignored.
+func decodeTag(doc *document, p *pkg, l *extractedLicense, section int, tv tagValue) error {
+	switch section {
+	case sectionDocument:
+		decodeDocumentTag(doc, tv)
+	case sectionPackage:
+		return decodePackageTag(p, tv)
+	case sectionLicense:
+		switch tv.tag {
+		case "ExtractedText":
+			l.ExtractedText = tv.value
+		case "LicenseName":
+			l.Name = tv.value
+		}
+	}
+	return nil
+}
+
+func decodeDocumentTag(doc *document, tv tagValue) {
+	switch tv.tag {
+	case "SPDXVersion":
+		doc.SPDXVersion = tv.value
+	case "DataLicense":
+		doc.DataLicense = tv.value
+	case "SPDXID":
+		doc.SPDXID = tv.value
+	case "DocumentName":
+		doc.Name = tv.value
+	case "DocumentNamespace":
+		doc.DocumentNamespace = tv.value
+	case "DocumentComment":
+		doc.Comment = tv.value
+	case "Creator":
+		doc.CreationInfo.Creators = append(doc.CreationInfo.Creators, tv.value)
+	case "Created":
+		doc.CreationInfo.Created = tv.value
+	case "CreatorComment":
+		doc.CreationInfo.Comment = tv.value
+	}
+}
+
+func decodePackageTag(p *pkg, tv tagValue) error {
+	switch tv.tag {
+	case "SPDXID":
+		p.SPDXID = tv.value
+	case "PackageVersion":
+		p.VersionInfo = tv.value
+	case "PackageSupplier":
+		p.Supplier = tv.value
+	case "PackageOriginator":
+		p.Originator = tv.value
+	case "PackageDownloadLocation":
+		p.DownloadLocation = tv.value
+	case "PackageLicenseConcluded":
+		p.LicenseConcluded = tv.value
+	case "PackageLicenseDeclared":
+		p.LicenseDeclared = tv.value
+	case "PrimaryPackagePurpose":
+		p.PrimaryPurpose = tv.value
+	case "PackageChecksum":
+		alg, value, ok := strings.Cut(tv.value, ":")
+		if !ok {
+			return fmt.Errorf("line %d: invalid checksum %q", tv.line, tv.value)
+		}
+		p.Checksums = append(p.Checksums, checksum{Algorithm: strings.TrimSpace(alg), ChecksumValue: strings.TrimSpace(value)})
+	case "ExternalRef":
+		fields := strings.Fields(tv.value)
+		if len(fields) != 3 {
+			return fmt.Errorf("line %d: invalid external reference %q", tv.line, tv.value)
+		}
+		p.ExternalRefs = append(p.ExternalRefs, externalRef{ReferenceCategory: fields[0], ReferenceType: fields[1], ReferenceLocator: fields[2]})
+	}
+	return nil
+}
+
+// readTagValues splits a tag-value document into its tags. The lines of
+// multi-line values, which are enclosed in <text></text>, are joined.
+func readTagValues(b []byte) ([]tagValue, error) {
+	var out []tagValue
+	// text is the multi-line value being read, if any.
+	var text *tagValue
+	for i, l := range strings.Split(string(b), "\n") {
+		line := i + 1
+		if text != nil {
+			before, found := strings.CutSuffix(strings.TrimRight(l, " \t\r"), "</text>")
+			text.value += "\n" + before
+			if found {
+				out = append(out, *text)
+				text = nil
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(l)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		tag, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected a tag and a value separated by a colon", line)
+		}
+		tv := tagValue{tag: strings.TrimSpace(tag), value: strings.TrimSpace(value), line: line}
+		if rest, ok := strings.CutPrefix(tv.value, "<text>"); ok {
+			var closed bool
+			tv.value, closed = strings.CutSuffix(rest, "</text>")
+			if !closed {
+				text = &tv
+				continue
+			}
+		}
+		out = append(out, tv)
+	}
+	if text != nil {
+		return nil, fmt.Errorf("line %d: unterminated <text> value", text.line)
+	}
+	return out, nil
+}
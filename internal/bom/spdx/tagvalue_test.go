@@ -0,0 +1,117 @@
+package spdx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/bom/spdx"
+)
+
+const testTagValueDocument = `## Document Information
+SPDXVersion: SPDX-2.3
+DataLicense: CC0-1.0
+SPDXID: SPDXRef-DOCUMENT
+DocumentName: goof
+DocumentNamespace: https://snyk.io/spdx/goof-1.0.0
+Creator: Organization: Snyk
+Creator: Tool: snyk-cli-1.2.3
+Created: 2024-01-01T00:00:00Z
+
+## Package Information
+PackageName: goof
+SPDXID: SPDXRef-goof
+PackageVersion: 1.0.0
+PackageDownloadLocation: NOASSERTION
+PrimaryPackagePurpose: APPLICATION
+
+PackageName: express
+SPDXID: SPDXRef-express
+PackageVersion: 4.4.0
+PackageSupplier: Organization: Express Foundation
+PackageLicenseDeclared: LicenseRef-ACME
+PackageChecksum: SHA256: 2c26b46b68ffc68f
+ExternalRef: PACKAGE-MANAGER purl pkg:npm/express@4.4.0
+ExternalRef: SECURITY cpe23Type cpe:2.3:a:expressjs:express:4.4.0:*:*:*:*:node.js:*:*
+
+## File Information
+FileName: ./index.js
+SPDXID: SPDXRef-index
+FileChecksum: SHA1: d6a770ba38583ed4bb4525bd96e50461655d2758
+
+## Other Licensing Information
+LicenseID: LicenseRef-ACME
+ExtractedText: <text>ACME Commercial License
+
+All rights reserved.</text>
+LicenseName: ACME Commercial License
+
+## Relationships
+Relationship: SPDXRef-DOCUMENT DESCRIBES SPDXRef-goof
+Relationship: SPDXRef-goof DEPENDS_ON SPDXRef-express
+`
+
+func TestIsTagValue(t *testing.T) {
+	assert.True(t, spdx.IsTagValue([]byte(testTagValueDocument)))
+	assert.True(t, spdx.IsTagValue([]byte("\n# comment\nSPDXVersion: SPDX-2.2\n")))
+	assert.False(t, spdx.IsTagValue([]byte(`{"spdxVersion":"SPDX-2.3"}`)))
+	assert.False(t, spdx.IsTagValue([]byte("DocumentName: goof\nSPDXVersion: SPDX-2.3\n")))
+	assert.False(t, spdx.IsTagValue(nil))
+}
+
+func TestTagValueToJSON(t *testing.T) {
+	b, err := spdx.TagValueToJSON([]byte(testTagValueDocument))
+	require.NoError(t, err)
+
+	decoded, err := spdx.Decode(b)
+
+	require.NoError(t, err)
+	assert.Equal(t, []*bom.Tool{{Vendor: "Snyk", Name: "snyk-cli", Version: "1.2.3"}}, decoded.Metadata.Tools)
+	assert.Equal(t, &bom.Component{
+		BOMRef:  "SPDXRef-goof",
+		Type:    bom.ComponentTypeApplication,
+		Name:    "goof",
+		Version: "1.0.0",
+	}, decoded.Metadata.Component)
+	assert.Equal(t, []*bom.Component{{
+		BOMRef:   "SPDXRef-express",
+		Type:     bom.ComponentTypeLibrary,
+		Name:     "express",
+		Version:  "4.4.0",
+		Supplier: "Express Foundation",
+		PURL:     "pkg:npm/express@4.4.0",
+		CPE:      "cpe:2.3:a:expressjs:express:4.4.0:*:*:*:*:node.js:*:*",
+		Licenses: []bom.License{{Name: "ACME Commercial License"}},
+		Hashes:   []bom.Hash{{Algorithm: "SHA-256", Value: "2c26b46b68ffc68f"}},
+	}}, decoded.Components)
+	assert.Equal(t, []*bom.Dependency{{Ref: "SPDXRef-goof", DependsOn: []string{"SPDXRef-express"}}}, decoded.Dependencies)
+}
+
+func TestTagValueToJSON_Invalid(t *testing.T) {
+	for name, tc := range map[string]struct{ doc, expectedErr string }{
+		"missing colon": {
+			doc:         "SPDXVersion: SPDX-2.3\nnot a tag\n",
+			expectedErr: "line 2: expected a tag and a value separated by a colon",
+		},
+		"unterminated text": {
+			doc:         "SPDXVersion: SPDX-2.3\nDocumentComment: <text>never\nclosed\n",
+			expectedErr: "line 2: unterminated <text> value",
+		},
+		"invalid relationship": {
+			doc:         "SPDXVersion: SPDX-2.3\nRelationship: SPDXRef-DOCUMENT DESCRIBES\n",
+			expectedErr: `line 2: invalid relationship "SPDXRef-DOCUMENT DESCRIBES"`,
+		},
+		"invalid checksum": {
+			doc:         "SPDXVersion: SPDX-2.3\nPackageName: goof\nPackageChecksum: 2c26b46b68ffc68f\n",
+			expectedErr: `line 3: invalid checksum "2c26b46b68ffc68f"`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := spdx.TagValueToJSON([]byte(tc.doc))
+
+			assert.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,357 @@
+package spdx
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+const (
+	dataLicense    = "CC0-1.0"
+	documentID     = "SPDXRef-DOCUMENT"
+	noAssertion    = "NOASSERTION"
+	licenseNone    = "NONE"
+	refCategoryPkg = "PACKAGE-MANAGER"
+	refTypePURL    = "purl"
+	refCategorySec = "SECURITY"
+	refTypeCPE23   = "cpe23Type"
+	refTypeCPE22   = "cpe22Type"
+
+	annotationTypeOther = "OTHER"
+
+	relationshipDescribes = "DESCRIBES"
+	relationshipDependsOn = "DEPENDS_ON"
+
+	purposeOther = "OTHER"
+)
+
+// v2Purposes are the primary package purposes SPDX 2.3 defines.
+var v2Purposes = [...]string{
+	"APPLICATION", "FRAMEWORK", "LIBRARY", "CONTAINER", "OPERATING-SYSTEM", "DEVICE", "FIRMWARE",
+	"SOURCE", "ARCHIVE", "FILE", "INSTALL", purposeOther,
+}
+
+// v2ChecksumAlgorithms maps hash algorithms to SPDX 2.3 checksum algorithms.
+var v2ChecksumAlgorithms = map[string]string{
+	"MD5":         "MD5",
+	"SHA-1":       "SHA1",
+	"SHA-256":     "SHA256",
+	"SHA-384":     "SHA384",
+	"SHA-512":     "SHA512",
+	"SHA3-256":    "SHA3-256",
+	"SHA3-384":    "SHA3-384",
+	"SHA3-512":    "SHA3-512",
+	"BLAKE2b-256": "BLAKE2b-256",
+	"BLAKE2b-384": "BLAKE2b-384",
+	"BLAKE2b-512": "BLAKE2b-512",
+	"BLAKE3":      "BLAKE3",
+	// Algorithms CycloneDX does not define keep their SPDX name.
+	"SHA224":  "SHA224",
+	"MD2":     "MD2",
+	"MD4":     "MD4",
+	"MD6":     "MD6",
+	"ADLER32": "ADLER32",
+}
+
+type (
+	document struct {
+		SPDXVersion       string          `json:"spdxVersion"`
+		DataLicense       string          `json:"dataLicense"`
+		SPDXID            string          `json:"SPDXID"`
+		Name              string          `json:"name"`
+		DocumentNamespace string          `json:"documentNamespace"`
+		CreationInfo      creationInfo    `json:"creationInfo"`
+		Comment           string          `json:"comment,omitempty"`
+		Packages          []*pkg          `json:"packages"`
+		Relationships     []*relationship `json:"relationships"`
+		// Annotations record the source revision of documents that
+		// describe no package.
+		Annotations []annotation `json:"annotations,omitempty"`
+		// ExtractedLicenses define the licenses without an SPDX identifier
+		// that packages refer to.
+		ExtractedLicenses []*extractedLicense `json:"hasExtractedLicensingInfos,omitempty"`
+	}
+
+	creationInfo struct {
+		Created  string   `json:"created"`
+		Creators []string `json:"creators"`
+		Comment  string   `json:"comment,omitempty"`
+	}
+
+	pkg struct {
+		Name             string        `json:"name"`
+		SPDXID           string        `json:"SPDXID"`
+		VersionInfo      string        `json:"versionInfo,omitempty"`
+		Supplier         string        `json:"supplier,omitempty"`
+		Originator       string        `json:"originator,omitempty"`
+		DownloadLocation string        `json:"downloadLocation"`
+		FilesAnalyzed    bool          `json:"filesAnalyzed"`
+		Checksums        []checksum    `json:"checksums,omitempty"`
+		LicenseConcluded string        `json:"licenseConcluded,omitempty"`
+		LicenseDeclared  string        `json:"licenseDeclared,omitempty"`
+		PrimaryPurpose   string        `json:"primaryPackagePurpose,omitempty"`
+		ExternalRefs     []externalRef `json:"externalRefs,omitempty"`
+		Annotations      []annotation  `json:"annotations,omitempty"`
+	}
+
+	annotation struct {
+		AnnotationDate string `json:"annotationDate"`
+		AnnotationType string `json:"annotationType"`
+		Annotator      string `json:"annotator"`
+		Comment        string `json:"comment"`
+	}
+
+	checksum struct {
+		Algorithm     string `json:"algorithm"`
+		ChecksumValue string `json:"checksumValue"`
+	}
+
+	extractedLicense struct {
+		LicenseID     string `json:"licenseId"`
+		ExtractedText string `json:"extractedText"`
+		Name          string `json:"name,omitempty"`
+	}
+
+	externalRef struct {
+		ReferenceCategory string `json:"referenceCategory"`
+		ReferenceType     string `json:"referenceType"`
+		ReferenceLocator  string `json:"referenceLocator"`
+	}
+
+	relationship struct {
+		SPDXElementID      string `json:"spdxElementId"`
+		RelationshipType   string `json:"relationshipType"`
+		RelatedSPDXElement string `json:"relatedSpdxElement"`
+	}
+)
+
+// encodeV2 renders doc as an SPDX 2.3 JSON document.
+func encodeV2(doc *bom.Document) ([]byte, error) {
+	return json.Marshal(fromDocument(doc))
+}
+
+func fromDocument(doc *bom.Document) *document {
+	out := &document{
+		SPDXVersion:       "SPDX-" + SpecVersion2_3,
+		DataLicense:       dataLicense,
+		SPDXID:            documentID,
+		Name:              documentName(doc),
+		DocumentNamespace: documentNamespace(doc),
+		CreationInfo: creationInfo{
+			Created:  formatTimestamp(doc.Timestamp),
+			Creators: creators(&doc.Metadata),
+			Comment:  creationComment(&doc.Metadata),
+		},
+		Comment:       propertiesComment(doc.Metadata.Properties),
+		Packages:      []*pkg{},
+		Relationships: []*relationship{},
+	}
+
+	ids := make(map[string]string, len(doc.Components)+1)
+	addPackage := func(c *bom.Component) {
+		if _, ok := ids[c.BOMRef]; ok {
+			return
+		}
+		id := packageID(len(ids)+1, c)
+		ids[c.BOMRef] = id
+		p := newPackage(id, c)
+		p.LicenseDeclared = out.declaredLicense(c.Licenses)
+		out.Packages = append(out.Packages, p)
+	}
+
+	if root := doc.Metadata.Component; root != nil {
+		addPackage(root)
+		// The supplier and manufacturer of the document are those of the
+		// product it describes.
+		rootPkg := out.Packages[len(out.Packages)-1]
+		rootPkg.Supplier = organization(cmp.Or(doc.Metadata.Supplier, root.Supplier))
+		rootPkg.Originator = organization(doc.Metadata.Manufacturer)
+		if v := doc.Metadata.VCS; v != nil {
+			rootPkg.Annotations = vcsAnnotations(doc, v)
+		}
+		out.Relationships = append(out.Relationships, &relationship{
+			SPDXElementID:      documentID,
+			RelationshipType:   relationshipDescribes,
+			RelatedSPDXElement: ids[root.BOMRef],
+		})
+	} else if v := doc.Metadata.VCS; v != nil {
+		out.Annotations = vcsAnnotations(doc, v)
+	}
+	for _, c := range doc.Components {
+		addPackage(c)
+	}
+
+	for _, dep := range doc.Dependencies {
+		from, ok := ids[dep.Ref]
+		if !ok {
+			continue
+		}
+		for _, ref := range dep.DependsOn {
+			to, ok := ids[ref]
+			if !ok {
+				continue
+			}
+			out.Relationships = append(out.Relationships, &relationship{
+				SPDXElementID:      from,
+				RelationshipType:   relationshipDependsOn,
+				RelatedSPDXElement: to,
+			})
+		}
+	}
+
+	return out
+}
+
+// vcsAnnotations record the source revision of doc, on the described package
+// or on the document itself.
+func vcsAnnotations(doc *bom.Document, v *bom.VCS) []annotation {
+	return []annotation{{
+		AnnotationDate: formatTimestamp(doc.Timestamp),
+		AnnotationType: annotationTypeOther,
+		Annotator:      annotator(&doc.Metadata),
+		Comment:        vcsComment(v),
+	}}
+}
+
+func newPackage(id string, c *bom.Component) *pkg {
+	p := &pkg{
+		Name:             c.Name,
+		SPDXID:           id,
+		VersionInfo:      c.Version,
+		DownloadLocation: noAssertion,
+		PrimaryPurpose:   v2Purpose(c.Type),
+		Supplier:         organization(c.Supplier),
+	}
+	for _, h := range c.Hashes {
+		if alg, ok := v2ChecksumAlgorithms[h.Algorithm]; ok {
+			p.Checksums = append(p.Checksums, checksum{Algorithm: alg, ChecksumValue: h.Value})
+		}
+	}
+	if c.PURL != "" {
+		p.ExternalRefs = append(p.ExternalRefs, externalRef{
+			ReferenceCategory: refCategoryPkg,
+			ReferenceType:     refTypePURL,
+			ReferenceLocator:  c.PURL,
+		})
+	}
+	if c.CPE != "" {
+		p.ExternalRefs = append(p.ExternalRefs, externalRef{
+			ReferenceCategory: refCategorySec,
+			ReferenceType:     cpeRefType(c.CPE),
+			ReferenceLocator:  c.CPE,
+		})
+	}
+	return p
+}
+
+// cpeRefType returns the external reference type of a CPE name, which is
+// either a CPE 2.3 formatted string or a CPE 2.2 URI.
+func cpeRefType(cpe string) string {
+	if strings.HasPrefix(cpe, "cpe:2.3:") {
+		return refTypeCPE23
+	}
+	return refTypeCPE22
+}
+
+// declaredLicense returns the license expression of licenses. Licenses that
+// only have a name are referred to by a LicenseRef defined in the document.
+func (d *document) declaredLicense(licenses []bom.License) string {
+	exprs := make([]bom.License, 0, len(licenses))
+	for _, l := range licenses {
+		if l.Expression == "" {
+			l = bom.License{Expression: d.licenseRef(l.Name)}
+		}
+		exprs = append(exprs, l)
+	}
+	return bom.LicenseExpression(exprs)
+}
+
+// licenseRef returns the LicenseRef of the license with the given name,
+// defining it if the document does not yet.
+func (d *document) licenseRef(name string) string {
+	base := "LicenseRef-" + strings.Trim(invalidIDChars.ReplaceAllString(name, "-"), "-")
+	id := base
+	for i := 2; ; i++ {
+		existing := findExtractedLicense(d.ExtractedLicenses, id)
+		if existing == nil {
+			break
+		}
+		if existing.Name == name {
+			return id
+		}
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+	d.ExtractedLicenses = append(d.ExtractedLicenses, &extractedLicense{LicenseID: id, ExtractedText: name, Name: name})
+	return id
+}
+
+func findExtractedLicense(ls []*extractedLicense, id string) *extractedLicense {
+	for _, l := range ls {
+		if l.LicenseID == id {
+			return l
+		}
+	}
+	return nil
+}
+
+// v2Purpose returns the primary package purpose of a component type, or
+// OTHER if SPDX 2.3 does not define it.
+func v2Purpose(t bom.ComponentType) string {
+	if t == "" {
+		return ""
+	}
+	if p := strings.ToUpper(string(t)); slices.Contains(v2Purposes[:], p) {
+		return p
+	}
+	return purposeOther
+}
+
+func creators(m *bom.Metadata) []string {
+	out := make([]string, 0, len(m.Authors)+len(m.Tools)+1)
+	for _, a := range m.Authors {
+		out = append(out, "Person: "+a)
+	}
+	var vendor string
+	for _, t := range m.Tools {
+		if t.Vendor != "" && vendor == "" {
+			vendor = t.Vendor
+		}
+		out = append(out, "Tool: "+toolName(t))
+	}
+	if vendor != "" {
+		out = append(out, "Organization: "+vendor)
+	}
+	return out
+}
+
+// annotator names the tool that generated the document, falling back to its
+// first creator.
+func annotator(m *bom.Metadata) string {
+	if len(m.Tools) > 0 {
+		return "Tool: " + toolName(m.Tools[0])
+	}
+	if c := creators(m); len(c) > 0 {
+		return c[0]
+	}
+	return "Organization: Snyk"
+}
+
+// creationComment records the lifecycle phases, for which SPDX 2.3 has no
+// field.
+func creationComment(m *bom.Metadata) string {
+	if len(m.Lifecycles) == 0 {
+		return ""
+	}
+	return "Lifecycle phases: " + strings.Join(m.Lifecycles, ", ")
+}
+
+func organization(name string) string {
+	if name == "" {
+		return ""
+	}
+	return "Organization: " + name
+}
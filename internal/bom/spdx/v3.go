@@ -0,0 +1,429 @@
+package spdx
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+const (
+	v3Context      = "https://spdx.org/rdf/3.0.1/spdx-context.jsonld"
+	v3SpecVersion  = "3.0.1"
+	creationInfoID = "_:creationinfo"
+
+	profileCore     = "core"
+	profileSoftware = "software"
+
+	v3AnnotationTypeOther              = "other"
+	relationshipTypeDependsOn          = "dependsOn"
+	relationshipTypeHasDeclaredLicense = "hasDeclaredLicense"
+	sbomTypeBuild                      = "build"
+	v3PurposeOther                     = "other"
+)
+
+// v3Purposes maps component types to SPDX 3.0 software purposes.
+var v3Purposes = map[bom.ComponentType]string{
+	"application":            "application",
+	"framework":              "framework",
+	"library":                "library",
+	"container":              "container",
+	"operating-system":       "operatingSystem",
+	"device":                 "device",
+	"firmware":               "firmware",
+	"file":                   "file",
+	"platform":               "platform",
+	"machine-learning-model": "model",
+	"data":                   "data",
+	"source":                 "source",
+	"archive":                "archive",
+	"install":                "install",
+	"other":                  v3PurposeOther,
+}
+
+// v3HashAlgorithms maps hash algorithms to SPDX 3.0 hash algorithms.
+var v3HashAlgorithms = map[string]string{
+	"MD5":         "md5",
+	"SHA-1":       "sha1",
+	"SHA-256":     "sha256",
+	"SHA-384":     "sha384",
+	"SHA-512":     "sha512",
+	"SHA3-256":    "sha3_256",
+	"SHA3-384":    "sha3_384",
+	"SHA3-512":    "sha3_512",
+	"BLAKE2b-256": "blake2b256",
+	"BLAKE2b-384": "blake2b384",
+	"BLAKE2b-512": "blake2b512",
+	"BLAKE3":      "blake3",
+	"SHA224":      "sha224",
+	"MD2":         "md2",
+	"MD4":         "md4",
+	"MD6":         "md6",
+	"ADLER32":     "adler32",
+}
+
+// v3SBOMTypes maps CycloneDX lifecycle phases to SPDX 3.0 SBOM types.
+var v3SBOMTypes = map[string]string{
+	"design":     "design",
+	"pre-build":  "source",
+	"build":      "build",
+	"post-build": "analyzed",
+	"operations": "runtime",
+	"discovery":  "analyzed",
+}
+
+type (
+	v3Document struct {
+		Context string `json:"@context"`
+		Graph   []any  `json:"@graph"`
+	}
+
+	v3CreationInfo struct {
+		Type         string   `json:"type"`
+		ID           string   `json:"@id"`
+		SpecVersion  string   `json:"specVersion"`
+		Created      string   `json:"created"`
+		CreatedBy    []string `json:"createdBy"`
+		CreatedUsing []string `json:"createdUsing,omitempty"`
+	}
+
+	v3Element struct {
+		Type         string `json:"type"`
+		SPDXID       string `json:"spdxId"`
+		CreationInfo string `json:"creationInfo"`
+		Name         string `json:"name,omitempty"`
+		Comment      string `json:"comment,omitempty"`
+	}
+
+	v3SPDXDocument struct {
+		v3Element
+		ProfileConformance []string `json:"profileConformance"`
+		RootElement        []string `json:"rootElement"`
+		Element            []string `json:"element"`
+	}
+
+	v3SBOM struct {
+		v3Element
+		SBOMType    []string `json:"software_sbomType"`
+		RootElement []string `json:"rootElement"`
+		Element     []string `json:"element"`
+	}
+
+	v3Package struct {
+		v3Element
+		SuppliedBy         string                 `json:"suppliedBy,omitempty"`
+		OriginatedBy       []string               `json:"originatedBy,omitempty"`
+		PackageVersion     string                 `json:"software_packageVersion,omitempty"`
+		PackageURL         string                 `json:"software_packageUrl,omitempty"`
+		PrimaryPurpose     string                 `json:"software_primaryPurpose,omitempty"`
+		VerifiedUsing      []v3Hash               `json:"verifiedUsing,omitempty"`
+		ExternalIdentifier []v3ExternalIdentifier `json:"externalIdentifier,omitempty"`
+	}
+
+	v3ExternalIdentifier struct {
+		Type                   string `json:"type"`
+		ExternalIdentifierType string `json:"externalIdentifierType"`
+		Identifier             string `json:"identifier"`
+	}
+
+	v3Hash struct {
+		Type      string `json:"type"`
+		Algorithm string `json:"algorithm"`
+		HashValue string `json:"hashValue"`
+	}
+
+	v3LicenseExpression struct {
+		v3Element
+		LicenseExpression string `json:"simplelicensing_licenseExpression"`
+	}
+
+	// v3CustomLicense is a license without an SPDX identifier, of which
+	// only the name is known.
+	v3CustomLicense struct {
+		v3Element
+		LicenseText string `json:"expandedlicensing_licenseText"`
+	}
+
+	v3Annotation struct {
+		v3Element
+		AnnotationType string `json:"annotationType"`
+		Subject        string `json:"subject"`
+		Statement      string `json:"statement"`
+	}
+
+	v3Relationship struct {
+		v3Element
+		From             string   `json:"from"`
+		RelationshipType string   `json:"relationshipType"`
+		To               []string `json:"to"`
+	}
+)
+
+// encodeV3 renders doc as an SPDX 3.0 JSON-LD document conforming to the
+// Core and Software profiles.
+func encodeV3(doc *bom.Document) ([]byte, error) {
+	ns := documentNamespace(doc)
+	id := func(local string) string { return ns + "#" + local }
+	element := func(typ, localID, name string) v3Element {
+		return v3Element{Type: typ, SPDXID: id(localID), CreationInfo: creationInfoID, Name: name}
+	}
+
+	graph := v3CreationInfoGraph(&doc.Metadata, formatTimestamp(doc.Timestamp), element)
+
+	// Suppliers are organizations, defined once per name.
+	supplierIDs := map[string]string{}
+	supplier := func(name string) string {
+		if id, ok := supplierIDs[name]; ok || name == "" {
+			return id
+		}
+		localID := "SPDXRef-Supplier"
+		if len(supplierIDs) > 0 {
+			localID = fmt.Sprintf("SPDXRef-Supplier-%d", len(supplierIDs)+1)
+		}
+		e := element("Organization", localID, name)
+		supplierIDs[name] = e.SPDXID
+		graph = append(graph, &e)
+		return e.SPDXID
+	}
+
+	var manufacturerID string
+	supplierID := supplier(doc.Metadata.Supplier)
+	if doc.Metadata.Manufacturer != "" {
+		manufacturer := element("Organization", "SPDXRef-Manufacturer", doc.Metadata.Manufacturer)
+		manufacturerID = manufacturer.SPDXID
+		graph = append(graph, &manufacturer)
+	}
+
+	rootIDs := []string{}
+	elementIDs := []string{}
+	pkgIDs := make(map[string]string, len(doc.Components)+1)
+
+	add := func(spdxID string, e any) {
+		elementIDs = append(elementIDs, spdxID)
+		graph = append(graph, e)
+	}
+	licenses := 0
+	addPackage := func(c *bom.Component) *v3Package {
+		if _, ok := pkgIDs[c.BOMRef]; ok {
+			return nil
+		}
+		p := &v3Package{
+			v3Element:          element("software_Package", packageID(len(pkgIDs)+1, c), c.Name),
+			PackageVersion:     c.Version,
+			PackageURL:         c.PURL,
+			PrimaryPurpose:     v3Purpose(c.Type),
+			SuppliedBy:         supplier(c.Supplier),
+			ExternalIdentifier: v3ExternalIdentifiers(c.CPE),
+		}
+		for _, h := range c.Hashes {
+			if alg, ok := v3HashAlgorithms[h.Algorithm]; ok {
+				p.VerifiedUsing = append(p.VerifiedUsing, v3Hash{Type: "Hash", Algorithm: alg, HashValue: h.Value})
+			}
+		}
+		pkgIDs[c.BOMRef] = p.SPDXID
+		elementIDs = append(elementIDs, p.SPDXID)
+		graph = append(graph, p)
+		if len(c.Licenses) > 0 {
+			licenses++
+			addLicenses(c.Licenses, p.SPDXID, licenses, element, add)
+		}
+		return p
+	}
+
+	// The source revision is annotated on the described package, or on the
+	// SBOM if it describes none.
+	vcsSubject := id("SPDXRef-SBOM")
+	if root := doc.Metadata.Component; root != nil {
+		rootPkg := addPackage(root)
+		rootIDs = append(rootIDs, rootPkg.SPDXID)
+		vcsSubject = rootPkg.SPDXID
+		if supplierID != "" {
+			rootPkg.SuppliedBy = supplierID
+		}
+		if manufacturerID != "" {
+			rootPkg.OriginatedBy = []string{manufacturerID}
+		}
+	}
+	if v := doc.Metadata.VCS; v != nil {
+		a := &v3Annotation{
+			v3Element:      element("Annotation", "SPDXRef-Annotation-VCS", ""),
+			AnnotationType: v3AnnotationTypeOther,
+			Subject:        vcsSubject,
+			Statement:      vcsComment(v),
+		}
+		elementIDs = append(elementIDs, a.SPDXID)
+		graph = append(graph, a)
+	}
+	for _, c := range doc.Components {
+		addPackage(c)
+	}
+
+	for _, r := range v3DependencyRelationships(doc.Dependencies, pkgIDs, element) {
+		elementIDs = append(elementIDs, r.SPDXID)
+		graph = append(graph, r)
+	}
+
+	sbom := &v3SBOM{
+		v3Element:   element("software_Sbom", "SPDXRef-SBOM", documentName(doc)),
+		SBOMType:    v3SBOMTypesOf(doc.Metadata.Lifecycles),
+		RootElement: rootIDs,
+		Element:     elementIDs,
+	}
+	spdxDoc := &v3SPDXDocument{
+		v3Element:          element("SpdxDocument", "SPDXRef-DOCUMENT", documentName(doc)),
+		ProfileConformance: []string{profileCore, profileSoftware},
+		RootElement:        []string{sbom.SPDXID},
+		Element:            append([]string{sbom.SPDXID}, elementIDs...),
+	}
+	spdxDoc.Comment = propertiesComment(doc.Metadata.Properties)
+	graph = append(graph, spdxDoc, sbom)
+
+	return json.Marshal(&v3Document{Context: v3Context, Graph: graph})
+}
+
+// v3CreationInfoGraph returns the creation info of the document, followed by
+// the tools, persons and organization it refers to.
+func v3CreationInfoGraph(m *bom.Metadata, created string, element func(typ, localID, name string) v3Element) []any {
+	ci := &v3CreationInfo{
+		Type:        "CreationInfo",
+		ID:          creationInfoID,
+		SpecVersion: v3SpecVersion,
+		Created:     created,
+		CreatedBy:   []string{},
+	}
+	graph := []any{ci}
+
+	var vendor string
+	for i, t := range m.Tools {
+		if t.Vendor != "" && vendor == "" {
+			vendor = t.Vendor
+		}
+		tool := element("Tool", fmt.Sprintf("SPDXRef-Tool-%d", i+1), toolName(t))
+		ci.CreatedUsing = append(ci.CreatedUsing, tool.SPDXID)
+		graph = append(graph, &tool)
+	}
+	if vendor == "" {
+		vendor = "Snyk"
+	}
+	for i, a := range m.Authors {
+		person := element("Person", fmt.Sprintf("SPDXRef-Person-%d", i+1), a)
+		ci.CreatedBy = append(ci.CreatedBy, person.SPDXID)
+		graph = append(graph, &person)
+	}
+	org := element("Organization", "SPDXRef-Organization", vendor)
+	ci.CreatedBy = append(ci.CreatedBy, org.SPDXID)
+	return append(graph, &org)
+}
+
+// v3DependencyRelationships returns a dependsOn relationship for each
+// dependency between packages of the document.
+func v3DependencyRelationships(
+	deps []*bom.Dependency,
+	pkgIDs map[string]string,
+	element func(typ, localID, name string) v3Element,
+) []*v3Relationship {
+	var out []*v3Relationship
+	for i, dep := range deps {
+		from, ok := pkgIDs[dep.Ref]
+		if !ok {
+			continue
+		}
+		var to []string
+		for _, ref := range dep.DependsOn {
+			if id, ok := pkgIDs[ref]; ok {
+				to = append(to, id)
+			}
+		}
+		if len(to) == 0 {
+			continue
+		}
+		out = append(out, &v3Relationship{
+			v3Element:        element("Relationship", fmt.Sprintf("SPDXRef-Relationship-%d", i+1), ""),
+			From:             from,
+			RelationshipType: relationshipTypeDependsOn,
+			To:               to,
+		})
+	}
+	return out
+}
+
+// v3SBOMTypesOf returns the SBOM types of the lifecycle phases, defaulting to
+// build.
+func v3SBOMTypesOf(lifecycles []string) []string {
+	sbomTypes := []string{}
+	for _, l := range lifecycles {
+		if t, ok := v3SBOMTypes[l]; ok && !slices.Contains(sbomTypes, t) {
+			sbomTypes = append(sbomTypes, t)
+		}
+	}
+	if len(sbomTypes) == 0 {
+		sbomTypes = append(sbomTypes, sbomTypeBuild)
+	}
+	return sbomTypes
+}
+
+// addLicenses adds the elements declaring the licenses of a package: a
+// license expression, custom licenses for those without an SPDX identifier,
+// and the relationship of the package to them. n numbers the elements.
+func addLicenses(
+	licenses []bom.License,
+	pkgID string,
+	n int,
+	element func(typ, localID, name string) v3Element,
+	add func(spdxID string, e any),
+) {
+	var to []string
+	if expr := bom.LicenseExpression(licenses); expr != "" {
+		l := &v3LicenseExpression{
+			v3Element:         element("simplelicensing_LicenseExpression", fmt.Sprintf("SPDXRef-License-%d", n), ""),
+			LicenseExpression: expr,
+		}
+		to = append(to, l.SPDXID)
+		add(l.SPDXID, l)
+	}
+	for i, lic := range licenses {
+		if lic.Expression != "" {
+			continue
+		}
+		l := &v3CustomLicense{
+			v3Element:   element("expandedlicensing_CustomLicense", fmt.Sprintf("SPDXRef-License-%d-%d", n, i+1), lic.Name),
+			LicenseText: lic.Name,
+		}
+		to = append(to, l.SPDXID)
+		add(l.SPDXID, l)
+	}
+	r := &v3Relationship{
+		v3Element:        element("Relationship", fmt.Sprintf("SPDXRef-Relationship-License-%d", n), ""),
+		From:             pkgID,
+		RelationshipType: relationshipTypeHasDeclaredLicense,
+		To:               to,
+	}
+	add(r.SPDXID, r)
+}
+
+// v3Purpose returns the software purpose of a component type, or other if
+// SPDX 3.0 does not define it.
+func v3Purpose(t bom.ComponentType) string {
+	if t == "" {
+		return ""
+	}
+	if p, ok := v3Purposes[t]; ok {
+		return p
+	}
+	return v3PurposeOther
+}
+
+// v3ExternalIdentifiers returns the external identifier of a CPE name, which
+// is either a CPE 2.3 formatted string or a CPE 2.2 URI.
+func v3ExternalIdentifiers(cpe string) []v3ExternalIdentifier {
+	if cpe == "" {
+		return nil
+	}
+	t := "cpe22"
+	if strings.HasPrefix(cpe, "cpe:2.3:") {
+		t = "cpe23"
+	}
+	return []v3ExternalIdentifier{{Type: "ExternalIdentifier", ExternalIdentifierType: t, Identifier: cpe}}
+}
@@ -0,0 +1,92 @@
+package spdx
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+// Unrepresentable reports the information in doc that is lost when it is
+// encoded as an SPDX document of the given spec version.
+func Unrepresentable(doc *bom.Document, specVersion string) []bom.Warning {
+	var warnings []bom.Warning
+	check := func(c *bom.Component) {
+		if len(c.Properties) > 0 {
+			warnings = append(warnings, bom.Warning{
+				BOMRef: c.BOMRef,
+				Msg:    fmt.Sprintf("SPDX has no component properties, %d properties were dropped", len(c.Properties)),
+			})
+		}
+		if purpose, ok := purpose(c.Type, specVersion); !ok {
+			warnings = append(warnings, bom.Warning{
+				BOMRef: c.BOMRef,
+				Msg:    fmt.Sprintf("component type %q is not supported by SPDX %s and was replaced by %q", c.Type, specVersion, purpose),
+			})
+		}
+		for _, h := range c.Hashes {
+			if _, ok := hashAlgorithms(specVersion)[h.Algorithm]; !ok {
+				warnings = append(warnings, bom.Warning{
+					BOMRef: c.BOMRef,
+					Msg:    fmt.Sprintf("hash algorithm %s is not supported by SPDX and the hash was dropped", h.Algorithm),
+				})
+			}
+		}
+	}
+
+	if doc.Metadata.Component != nil {
+		check(doc.Metadata.Component)
+	}
+	for _, c := range doc.Components {
+		check(c)
+	}
+
+	if specVersion == SpecVersion3_0 {
+		for _, l := range doc.Metadata.Lifecycles {
+			if _, ok := v3SBOMTypes[l]; !ok {
+				warnings = append(warnings, bom.Warning{
+					Msg: fmt.Sprintf("lifecycle phase %q has no SPDX 3.0 SBOM type and was dropped", l),
+				})
+			}
+		}
+	}
+
+	return append(warnings, toolVendorWarnings(doc.Metadata.Tools)...)
+}
+
+// toolVendorWarnings reports the tool vendors other than the first, as SPDX
+// records a single organization for all tools.
+func toolVendorWarnings(tools []*bom.Tool) []bom.Warning {
+	var warnings []bom.Warning
+	var vendor string
+	for _, t := range tools {
+		switch {
+		case t.Vendor == "" || t.Vendor == vendor:
+		case vendor == "":
+			vendor = t.Vendor
+		default:
+			warnings = append(warnings, bom.Warning{
+				Msg: fmt.Sprintf("SPDX records a single tool vendor, the vendor %q of %s was dropped", t.Vendor, toolName(t)),
+			})
+		}
+	}
+	return warnings
+}
+
+// purpose returns the package purpose a component type is encoded as in the
+// given spec version, and whether the spec version defines the type.
+func purpose(t bom.ComponentType, specVersion string) (string, bool) {
+	if specVersion == SpecVersion3_0 {
+		_, ok := v3Purposes[t]
+		return v3Purpose(t), t == "" || ok
+	}
+	return v2Purpose(t), t == "" || slices.Contains(v2Purposes[:], strings.ToUpper(string(t)))
+}
+
+func hashAlgorithms(specVersion string) map[string]string {
+	if specVersion == SpecVersion3_0 {
+		return v3HashAlgorithms
+	}
+	return v2ChecksumAlgorithms
+}
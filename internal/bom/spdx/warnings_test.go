@@ -0,0 +1,48 @@
+package spdx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/bom/spdx"
+)
+
+func TestUnrepresentable(t *testing.T) {
+	doc := newTestDocument()
+	doc.Metadata.Tools = append(doc.Metadata.Tools, &bom.Tool{Vendor: "ACME", Name: "scanner", Version: "2.0.0"})
+	doc.Metadata.Lifecycles = []string{"build", "decommission"}
+	doc.Components[0].Type = "device-driver"
+	doc.Components[0].Properties = []bom.Property{{Name: "cdx:npm:package:development", Value: "true"}}
+	doc.Components[1].Type = "machine-learning-model"
+	doc.Components[1].Hashes = []bom.Hash{{Algorithm: "SHA-256", Value: "2c26b46b"}, {Algorithm: "CRC32", Value: "cbf43926"}}
+
+	t.Run("2.3", func(t *testing.T) {
+		assert.Equal(t, []bom.Warning{
+			{BOMRef: "@snyk/express@4.4.0", Msg: "SPDX has no component properties, 1 properties were dropped"},
+			{BOMRef: "@snyk/express@4.4.0", Msg: `component type "device-driver" is not supported by SPDX 2.3 and was replaced by "OTHER"`},
+			{BOMRef: "ws@1.0.0", Msg: `component type "machine-learning-model" is not supported by SPDX 2.3 and was replaced by "OTHER"`},
+			{BOMRef: "ws@1.0.0", Msg: "hash algorithm CRC32 is not supported by SPDX and the hash was dropped"},
+			{Msg: `SPDX records a single tool vendor, the vendor "ACME" of scanner-2.0.0 was dropped`},
+		}, spdx.Unrepresentable(doc, "2.3"))
+	})
+
+	t.Run("3.0", func(t *testing.T) {
+		assert.Equal(t, []bom.Warning{
+			{BOMRef: "@snyk/express@4.4.0", Msg: "SPDX has no component properties, 1 properties were dropped"},
+			{BOMRef: "@snyk/express@4.4.0", Msg: `component type "device-driver" is not supported by SPDX 3.0 and was replaced by "other"`},
+			{BOMRef: "ws@1.0.0", Msg: "hash algorithm CRC32 is not supported by SPDX and the hash was dropped"},
+			{Msg: `lifecycle phase "decommission" has no SPDX 3.0 SBOM type and was dropped`},
+			{Msg: `SPDX records a single tool vendor, the vendor "ACME" of scanner-2.0.0 was dropped`},
+		}, spdx.Unrepresentable(doc, "3.0"))
+	})
+}
+
+func TestUnrepresentable_NoWarnings(t *testing.T) {
+	doc := newTestDocument()
+	doc.Components[0].Licenses = []bom.License{{Name: "ACME Commercial License"}}
+	doc.Components[0].Hashes = []bom.Hash{{Algorithm: "SHA-256", Value: "2c26b46b"}}
+
+	assert.Empty(t, spdx.Unrepresentable(doc, "2.3"))
+}
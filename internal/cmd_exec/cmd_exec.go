@@ -1,6 +1,7 @@
 package cmd_exec
 
 import (
+	"net/url"
 	"os/exec"
 	"strings"
 )
@@ -30,6 +31,70 @@ func (g *cliRemoteRepoURLGetter) GetRemoteOriginURL() string {
 	return strings.TrimSpace(origin)
 }
 
+// GitInfo identifies the revision of a git working tree.
+type GitInfo struct {
+	RemoteURL string
+	Commit    string
+	// Branch is empty if HEAD is detached.
+	Branch string
+	// Dirty is set if the working tree has uncommitted changes.
+	Dirty bool
+}
+
+type GitInfoGetter interface {
+	// GetGitInfo returns the revision of the working tree containing dir, or
+	// nil if dir is not in a git working tree.
+	GetGitInfo(dir string) *GitInfo
+}
+
+type cliGitInfoGetter struct {
+	exec cmdExecutor
+}
+
+func NewCliGitInfoGetter() *cliGitInfoGetter {
+	return NewGitInfoGetter(&cliCmdExecutor{})
+}
+
+func NewGitInfoGetter(c cmdExecutor) *cliGitInfoGetter {
+	return &cliGitInfoGetter{exec: c}
+}
+
+func (g *cliGitInfoGetter) GetGitInfo(dir string) *GitInfo {
+	git := func(args ...string) (string, error) {
+		out, err := g.exec.Exec("git", append([]string{"-C", dir}, args...)...)
+		return strings.TrimSpace(out), err
+	}
+
+	commit, err := git("rev-parse", "HEAD")
+	if err != nil || commit == "" {
+		return nil
+	}
+	info := &GitInfo{Commit: commit}
+
+	if branch, err := git("rev-parse", "--abbrev-ref", "HEAD"); err == nil && branch != "HEAD" {
+		info.Branch = branch
+	}
+	if origin, err := git("remote", "get-url", "origin"); err == nil {
+		info.RemoteURL = redactCredentials(origin)
+	}
+	if status, err := git("status", "--porcelain"); err == nil {
+		info.Dirty = status != ""
+	}
+
+	return info
+}
+
+// redactCredentials removes the user info, which may hold an access token,
+// from a remote URL.
+func redactCredentials(remote string) string {
+	u, err := url.Parse(remote)
+	if err != nil || u.User == nil {
+		return remote
+	}
+	u.User = nil
+	return u.String()
+}
+
 type cmdExecutor interface {
 	Exec(string, ...string) (string, error)
 }
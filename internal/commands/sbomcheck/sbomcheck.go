@@ -0,0 +1,137 @@
+package sbomcheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/snyk/go-application-framework/pkg/workflow"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/errors"
+	"github.com/snyk/cli-extension-sbom/internal/flags"
+	"github.com/snyk/cli-extension-sbom/internal/sbom"
+	view "github.com/snyk/cli-extension-sbom/internal/view/sbomcheck"
+)
+
+var (
+	WorkflowID     = workflow.NewWorkflowIdentifier("sbom.check")
+	WorkflowDataID = workflow.NewTypeIdentifier(WorkflowID, "sbom.check")
+)
+
+const MIMETypeJSON = "application/json"
+
+type (
+	JSONOutput struct {
+		File       string          `json:"file"`
+		Score      int             `json:"score"`
+		Threshold  int             `json:"threshold"`
+		Passed     bool            `json:"passed"`
+		Elements   []JSONElement   `json:"elements"`
+		Missing    []string        `json:"missing"`
+		Components []JSONComponent `json:"components"`
+	}
+
+	JSONElement struct {
+		Name    string `json:"name"`
+		Present int    `json:"present"`
+		Total   int    `json:"total"`
+	}
+
+	JSONComponent struct {
+		BOMRef  string   `json:"bomRef,omitempty"`
+		Name    string   `json:"name,omitempty"`
+		Version string   `json:"version,omitempty"`
+		PURL    string   `json:"purl,omitempty"`
+		Missing []string `json:"missing"`
+	}
+)
+
+func RegisterWorkflows(e workflow.Engine) error {
+	sbomFlagset := flags.GetSBOMCheckFlagSet()
+
+	c := workflow.ConfigurationOptionsFromFlagset(sbomFlagset)
+
+	if _, err := e.Register(WorkflowID, c, CheckWorkflow); err != nil {
+		return fmt.Errorf("error while registering %s workflow: %w", WorkflowID, err)
+	}
+
+	return nil
+}
+
+// CheckWorkflow checks an SBOM document for the NTIA minimum elements. The
+// CLI exits with a non-zero exit code if the score is below the threshold.
+func CheckWorkflow(
+	ictx workflow.InvocationContext,
+	_ []workflow.Data,
+) ([]workflow.Data, error) {
+	config := ictx.GetConfiguration()
+	logger := ictx.GetEnhancedLogger()
+	filename := config.GetString(flags.FlagFile)
+	threshold := config.GetInt(flags.FlagThreshold)
+	errFactory := errors.NewErrorFactory(logger)
+
+	logger.Println("SBOM Check workflow start")
+
+	if filename == "" {
+		return nil, errFactory.NewMissingFilenameFlagError()
+	}
+	if threshold < 0 || threshold > 100 {
+		return nil, errFactory.NewInvalidThresholdError(threshold)
+	}
+
+	doc, err := sbom.ReadDocument(filename, errFactory)
+	if err != nil {
+		return nil, err
+	}
+
+	res := bom.CheckNTIA(doc)
+	logger.Printf("Checked %s: score %d, %d non-conforming component(s)\n", filename, res.Score(), len(res.Components))
+
+	var out workflow.Data
+	if config.GetBool(flags.FlagJSON) {
+		b, err := json.Marshal(toJSONOutput(filename, res, threshold))
+		if err != nil {
+			return nil, errFactory.NewRenderError(err)
+		}
+		out = workflow.NewData(WorkflowDataID, MIMETypeJSON, b)
+	} else {
+		var buf bytes.Buffer
+		if err := view.NewRenderer(&buf).RenderResult(filename, res, threshold); err != nil {
+			return nil, errFactory.NewRenderError(err)
+		}
+		out = workflow.NewData(WorkflowDataID, "text/plain", buf.Bytes())
+	}
+
+	summary, contentType, err := sbom.CheckSummary(filename, threshold, res)
+	if err != nil {
+		return nil, errFactory.NewRenderError(err)
+	}
+	return []workflow.Data{out, workflow.NewData(WorkflowDataID, contentType, summary)}, nil
+}
+
+func toJSONOutput(filename string, res *bom.NTIAResult, threshold int) *JSONOutput {
+	out := &JSONOutput{
+		File:       filename,
+		Score:      res.Score(),
+		Threshold:  threshold,
+		Passed:     res.Score() >= threshold,
+		Elements:   make([]JSONElement, 0, len(res.Elements)),
+		Missing:    make([]string, 0, len(res.Missing)),
+		Components: make([]JSONComponent, 0, len(res.Components)),
+	}
+	for _, e := range res.Elements {
+		out.Elements = append(out.Elements, JSONElement{Name: e.Element, Present: e.Present, Total: e.Total})
+	}
+	out.Missing = append(out.Missing, res.Missing...)
+	for _, c := range res.Components {
+		out.Components = append(out.Components, JSONComponent{
+			BOMRef:  c.Component.BOMRef,
+			Name:    c.Component.Name,
+			Version: c.Component.Version,
+			PURL:    c.Component.PURL,
+			Missing: c.Missing,
+		})
+	}
+	return out
+}
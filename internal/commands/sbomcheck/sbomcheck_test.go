@@ -0,0 +1,152 @@
+package sbomcheck_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	snyk_errors "github.com/snyk/error-catalog-golang-public/snyk_errors"
+	"github.com/snyk/go-application-framework/pkg/configuration"
+	"github.com/snyk/go-application-framework/pkg/local_workflows/content_type"
+	"github.com/snyk/go-application-framework/pkg/mocks"
+	"github.com/snyk/go-application-framework/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/commands/sbomcheck"
+	"github.com/snyk/cli-extension-sbom/internal/flags"
+)
+
+func TestSBOMCheckWorkflow_NoFileFlag(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+
+	_, err := sbomcheck.CheckWorkflow(mockICTX, []workflow.Data{})
+
+	assert.ErrorContains(t, err, "Flag `--file` is required to execute this command.")
+}
+
+func TestSBOMCheckWorkflow_InvalidThreshold(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+	mockICTX.GetConfiguration().Set(flags.FlagFile, "testdata/partial.cdx.json")
+	mockICTX.GetConfiguration().Set(flags.FlagThreshold, 101)
+
+	_, err := sbomcheck.CheckWorkflow(mockICTX, []workflow.Data{})
+
+	var snykErr snyk_errors.Error
+	require.True(t, errors.As(err, &snykErr))
+	assert.Equal(t, "Flag `--threshold` must be a score between 0 and 100 (got 101).", snykErr.Detail)
+}
+
+func TestSBOMCheckWorkflow_Text(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+	mockICTX.GetConfiguration().Set(flags.FlagFile, "testdata/partial.cdx.json")
+	mockICTX.GetConfiguration().Set(flags.FlagThreshold, 100)
+
+	result, err := sbomcheck.CheckWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, "text/plain", result[0].GetContentType())
+	payload, ok := result[0].GetPayload().([]byte)
+	require.True(t, ok)
+	assert.Contains(t, string(payload), "express@4.4.0: missing supplier")
+	assert.Contains(t, string(payload), "util: missing supplier, version, unique identifier, dependency relationships")
+	assert.Contains(t, string(payload), "Score 70 is below the threshold of 100")
+}
+
+func TestSBOMCheckWorkflow_JSON(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+	mockICTX.GetConfiguration().Set(flags.FlagFile, "testdata/partial.cdx.json")
+	mockICTX.GetConfiguration().Set(flags.FlagThreshold, 70)
+	mockICTX.GetConfiguration().Set(flags.FlagJSON, true)
+
+	result, err := sbomcheck.CheckWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, sbomcheck.MIMETypeJSON, result[0].GetContentType())
+	payload, ok := result[0].GetPayload().([]byte)
+	require.True(t, ok)
+	assert.JSONEq(t, `{
+		"file": "testdata/partial.cdx.json",
+		"score": 70,
+		"threshold": 70,
+		"passed": true,
+		"elements": [
+			{"name": "supplier", "present": 1, "total": 3},
+			{"name": "component name", "present": 3, "total": 3},
+			{"name": "version", "present": 2, "total": 3},
+			{"name": "unique identifier", "present": 2, "total": 3},
+			{"name": "dependency relationships", "present": 2, "total": 3},
+			{"name": "author", "present": 1, "total": 1},
+			{"name": "timestamp", "present": 1, "total": 1}
+		],
+		"missing": [],
+		"components": [
+			{"bomRef": "express@4.4.0", "name": "express", "version": "4.4.0", "purl": "pkg:npm/express@4.4.0", "missing": ["supplier"]},
+			{"bomRef": "util", "name": "util", "missing": ["supplier", "version", "unique identifier", "dependency relationships"]}
+		]
+	}`, string(payload))
+}
+
+func TestSBOMCheckWorkflow_Threshold(t *testing.T) {
+	tc := []struct {
+		name      string
+		file      string
+		threshold int
+		expected  string
+	}{
+		{
+			name:      "below threshold",
+			file:      "testdata/partial.cdx.json",
+			threshold: 100,
+			expected:  `{"results":[{"severity":"low","total":5,"open":5,"ignored":0}],"type":"sbom-check","artifacts":1,"path":"testdata/partial.cdx.json"}`,
+		},
+		{
+			name:      "meets threshold",
+			file:      "testdata/partial.cdx.json",
+			threshold: 70,
+			expected:  `{"results":[{"severity":"low","total":5,"open":0,"ignored":0}],"type":"sbom-check","artifacts":1,"path":"testdata/partial.cdx.json"}`,
+		},
+		{
+			name:      "conforming document",
+			file:      "testdata/conforming.cdx.json",
+			threshold: 100,
+			expected:  `{"results":[{"severity":"low","total":0,"open":0,"ignored":0}],"type":"sbom-check","artifacts":1,"path":"testdata/conforming.cdx.json"}`,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			mockICTX := mockInvocationContext(t)
+			mockICTX.GetConfiguration().Set(flags.FlagFile, tt.file)
+			mockICTX.GetConfiguration().Set(flags.FlagThreshold, tt.threshold)
+
+			result, err := sbomcheck.CheckWorkflow(mockICTX, []workflow.Data{})
+
+			require.NoError(t, err)
+			require.Len(t, result, 2)
+			assert.Equal(t, content_type.TEST_SUMMARY, result[1].GetContentType())
+			payload, ok := result[1].GetPayload().([]byte)
+			require.True(t, ok)
+			assert.JSONEq(t, tt.expected, string(payload))
+		})
+	}
+}
+
+// Helpers
+
+func mockInvocationContext(t *testing.T) workflow.InvocationContext {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	mockLogger := zerolog.New(io.Discard)
+
+	ictx := mocks.NewMockInvocationContext(ctrl)
+	ictx.EXPECT().GetConfiguration().Return(configuration.New()).AnyTimes()
+	ictx.EXPECT().GetEnhancedLogger().Return(&mockLogger).AnyTimes()
+
+	return ictx
+}
@@ -0,0 +1,90 @@
+package sbomconvert
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/snyk/go-application-framework/pkg/workflow"
+
+	"github.com/snyk/cli-extension-sbom/internal/errors"
+	"github.com/snyk/cli-extension-sbom/internal/flags"
+	"github.com/snyk/cli-extension-sbom/internal/sbom"
+	"github.com/snyk/cli-extension-sbom/internal/service"
+	view "github.com/snyk/cli-extension-sbom/internal/view/sbomconvert"
+)
+
+var (
+	WorkflowID     = workflow.NewWorkflowIdentifier("sbom.convert")
+	WorkflowDataID = workflow.NewTypeIdentifier(WorkflowID, "sbom")
+)
+
+func RegisterWorkflows(e workflow.Engine) error {
+	sbomFlagset := flags.GetSBOMConvertFlagSet()
+
+	c := workflow.ConfigurationOptionsFromFlagset(sbomFlagset)
+
+	if _, err := e.Register(WorkflowID, c, ConvertWorkflow); err != nil {
+		return fmt.Errorf("error while registering %s workflow: %w", WorkflowID, err)
+	}
+
+	return nil
+}
+
+// ConvertWorkflow converts an SBOM document to other formats locally. The
+// information a format cannot represent is reported as warnings on stderr,
+// leaving stdout to the converted documents.
+func ConvertWorkflow(
+	ictx workflow.InvocationContext,
+	_ []workflow.Data,
+) ([]workflow.Data, error) {
+	config := ictx.GetConfiguration()
+	logger := ictx.GetEnhancedLogger()
+	filename := config.GetString(flags.FlagFile)
+	errFactory := errors.NewErrorFactory(logger)
+
+	logger.Println("SBOM Convert workflow start")
+
+	if filename == "" {
+		return nil, errFactory.NewMissingFilenameFlagError()
+	}
+
+	formats, err := service.ParseSBOMFormats(errFactory, config.GetString(flags.FlagFormat))
+	if err != nil {
+		return nil, err
+	}
+
+	doc, dropped, err := sbom.ReadDocumentWithWarnings(filename, errFactory)
+	if err != nil {
+		return nil, err
+	}
+	// SPDX documents need a unique namespace, which is derived from the
+	// serial number.
+	if doc.SerialNumber == "" {
+		doc.SerialNumber = "urn:uuid:" + uuid.NewString()
+	}
+
+	r := view.NewRenderer(os.Stderr)
+	if err := r.RenderDroppedFields(filename, dropped); err != nil {
+		return nil, errFactory.NewRenderError(err)
+	}
+
+	out := make([]workflow.Data, 0, len(formats))
+	for _, format := range formats {
+		res, warnings, err := service.ConvertDocument(doc, format)
+		if err != nil {
+			return nil, errFactory.NewFailedToConvertSBOMError(err, format)
+		}
+
+		logger.Printf("Converted %s to %s with %d warnings\n", filename, format, len(warnings))
+		if err := r.RenderWarnings(format, warnings); err != nil {
+			return nil, errFactory.NewRenderError(err)
+		}
+
+		d := workflow.NewData(WorkflowDataID, res.MIMEType, res.Doc)
+		d.SetContentLocation(format)
+		out = append(out, d)
+	}
+
+	return out, nil
+}
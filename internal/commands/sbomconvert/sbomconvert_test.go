@@ -0,0 +1,197 @@
+package sbomconvert_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	snyk_errors "github.com/snyk/error-catalog-golang-public/snyk_errors"
+	"github.com/snyk/go-application-framework/pkg/configuration"
+	"github.com/snyk/go-application-framework/pkg/mocks"
+	"github.com/snyk/go-application-framework/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/bom/cyclonedx"
+	"github.com/snyk/cli-extension-sbom/internal/bom/spdx"
+	"github.com/snyk/cli-extension-sbom/internal/commands/sbomconvert"
+	"github.com/snyk/cli-extension-sbom/internal/flags"
+)
+
+func TestSBOMConvertWorkflow_NoFileFlag(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+
+	_, err := sbomconvert.ConvertWorkflow(mockICTX, []workflow.Data{})
+
+	assert.ErrorContains(t, err, "Flag `--file` is required to execute this command.")
+}
+
+func TestSBOMConvertWorkflow_NoFormat(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+	mockICTX.GetConfiguration().Set(flags.FlagFile, "testdata/bom.cdx.json")
+
+	_, err := sbomconvert.ConvertWorkflow(mockICTX, []workflow.Data{})
+
+	assert.ErrorContains(t, err, "Must set `--format` flag to specify an SBOM format.")
+}
+
+func TestSBOMConvertWorkflow_UnknownInput(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+	mockICTX.GetConfiguration().Set(flags.FlagFile, "testdata/unknown.json")
+	mockICTX.GetConfiguration().Set(flags.FlagFormat, "spdx2.3+json")
+
+	_, err := sbomconvert.ConvertWorkflow(mockICTX, []workflow.Data{})
+
+	var snykErr snyk_errors.Error
+	require.True(t, errors.As(err, &snykErr))
+	assert.Equal(t, `The file "testdata/unknown.json" is neither a CycloneDX JSON or XML document nor an SPDX JSON document.`, snykErr.Detail)
+}
+
+func TestSBOMConvertWorkflow_Success(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+	mockICTX.GetConfiguration().Set(flags.FlagFile, "testdata/bom.cdx.json")
+	mockICTX.GetConfiguration().Set(flags.FlagFormat, "spdx2.3+json,cyclonedx1.4+xml")
+
+	var result []workflow.Data
+	stderr := captureStderr(t, func() {
+		var err error
+		result, err = sbomconvert.ConvertWorkflow(mockICTX, []workflow.Data{})
+		require.NoError(t, err)
+	})
+
+	require.Len(t, result, 2)
+	assert.Equal(t, spdx.MIMETypeJSON, result[0].GetContentType())
+	assert.Equal(t, "spdx2.3+json", result[0].GetContentLocation())
+	assert.Equal(t, cyclonedx.MIMETypeXML, result[1].GetContentType())
+	assert.Equal(t, "cyclonedx1.4+xml", result[1].GetContentLocation())
+
+	payload, ok := result[0].GetPayload().([]byte)
+	require.True(t, ok)
+	doc, err := spdx.Decode(payload)
+	require.NoError(t, err)
+
+	assert.Equal(t, "frontend", doc.Metadata.Component.Name)
+	assert.Equal(t, []*bom.Component{
+		{
+			BOMRef:   "SPDXRef-2-express-4.4.0",
+			Type:     bom.ComponentTypeLibrary,
+			Name:     "express",
+			Version:  "4.4.0",
+			PURL:     "pkg:npm/express@4.4.0",
+			Licenses: []bom.License{{Expression: "MIT"}},
+			Hashes:   []bom.Hash{{Algorithm: "SHA-256", Value: "a1d5d8f3b6c1e6f0f4b6cf1c3a1c29e2d7f42b5f9ec5c3b8d6e3f0f7c9c3e1a2"}},
+		},
+		{
+			BOMRef:   "SPDXRef-3-util-1.0.0",
+			Type:     bom.ComponentTypeLibrary,
+			Name:     "util",
+			Version:  "1.0.0",
+			PURL:     "pkg:npm/util@1.0.0",
+			Licenses: []bom.License{{Expression: "MIT OR Apache-2.0"}},
+		},
+	}, doc.Components)
+	assert.Equal(t, []*bom.Dependency{
+		{Ref: "SPDXRef-1-frontend-1.0.0", DependsOn: []string{"SPDXRef-2-express-4.4.0"}},
+		{Ref: "SPDXRef-2-express-4.4.0", DependsOn: []string{"SPDXRef-3-util-1.0.0"}},
+	}, doc.Dependencies)
+
+	assert.Contains(t, stderr, "Some information cannot be represented in spdx2.3+json:")
+	assert.Contains(t, stderr, "WARNING: SPDX has no component properties, 1 properties were dropped (express@4.4.0)")
+	assert.NotContains(t, stderr, "cyclonedx1.4+xml")
+}
+
+func TestSBOMConvertWorkflow_UpgradeCycloneDX(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+	mockICTX.GetConfiguration().Set(flags.FlagFile, "testdata/bom-1.4.cdx.xml")
+	mockICTX.GetConfiguration().Set(flags.FlagFormat, "cyclonedx1.6+json")
+
+	var result []workflow.Data
+	stderr := captureStderr(t, func() {
+		var err error
+		result, err = sbomconvert.ConvertWorkflow(mockICTX, []workflow.Data{})
+		require.NoError(t, err)
+	})
+
+	require.Len(t, result, 1)
+	payload, ok := result[0].GetPayload().([]byte)
+	require.True(t, ok)
+	assert.Contains(t, string(payload), `"specVersion":"1.6"`)
+	assert.Contains(t, string(payload), `"tools":{"components":[`)
+
+	doc, err := cyclonedx.Decode(payload)
+	require.NoError(t, err)
+	assert.Equal(t, []*bom.Tool{{Vendor: "Snyk", Name: "snyk-cli", Version: "1.1290.0"}}, doc.Metadata.Tools)
+	assert.Equal(t, "frontend", doc.Metadata.Component.Name)
+	assert.Equal(t, "express", doc.Components[0].Name)
+
+	assert.Contains(t, stderr, "Some information in testdata/bom-1.4.cdx.xml is not supported and was dropped:")
+	assert.Contains(t, stderr, "WARNING: field components[].scope is not supported and was dropped")
+}
+
+func TestSBOMConvertWorkflow_DowngradeCycloneDX(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+	mockICTX.GetConfiguration().Set(flags.FlagFile, "testdata/bom-1.6.cdx.json")
+	mockICTX.GetConfiguration().Set(flags.FlagFormat, "cyclonedx1.4+xml")
+
+	var result []workflow.Data
+	stderr := captureStderr(t, func() {
+		var err error
+		result, err = sbomconvert.ConvertWorkflow(mockICTX, []workflow.Data{})
+		require.NoError(t, err)
+	})
+
+	require.Len(t, result, 1)
+	payload, ok := result[0].GetPayload().([]byte)
+	require.True(t, ok)
+	assert.Contains(t, string(payload), `xmlns="http://cyclonedx.org/schema/bom/1.4"`)
+
+	doc, err := cyclonedx.Decode(payload)
+	require.NoError(t, err)
+	assert.Equal(t, []*bom.Tool{
+		{Vendor: "Snyk", Name: "snyk-cli", Version: "1.1290.0"},
+		{Vendor: "ACME", Name: "acme-scanner", Version: "2.0.0"},
+	}, doc.Metadata.Tools)
+	assert.Equal(t, bom.ComponentTypeLibrary, doc.Metadata.Component.Type)
+
+	assert.NotContains(t, stderr, "was dropped:")
+	assert.Contains(t, stderr, "Some information cannot be represented in cyclonedx1.4+xml:")
+	assert.Contains(t, stderr, `WARNING: component type "machine-learning-model" is not supported by CycloneDX 1.4 and was replaced by "library" (model@1.0.0)`)
+}
+
+// Helpers
+
+func captureStderr(t *testing.T, f func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+
+	f()
+	require.NoError(t, w.Close())
+
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func mockInvocationContext(t *testing.T) workflow.InvocationContext {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	mockLogger := zerolog.New(io.Discard)
+
+	mockConfig := configuration.New()
+
+	ictx := mocks.NewMockInvocationContext(ctrl)
+	ictx.EXPECT().GetConfiguration().Return(mockConfig).AnyTimes()
+	ictx.EXPECT().GetEnhancedLogger().Return(&mockLogger).AnyTimes()
+
+	return ictx
+}
@@ -0,0 +1,69 @@
+package sbomcreate
+
+import (
+	"cmp"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/snyk/go-application-framework/pkg/workflow"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/errors"
+	"github.com/snyk/cli-extension-sbom/internal/sbom"
+	view "github.com/snyk/cli-extension-sbom/internal/view/sbomcheck"
+)
+
+// assessDocuments scores and checks the generated documents as requested by
+// the options, returning the test summary of the check, if any.
+func assessDocuments(docs []*sbomDocument, opts *options, logger *zerolog.Logger, errFactory *errors.ErrorFactory) ([]workflow.Data, error) {
+	out := []workflow.Data{}
+	if opts.quality || opts.qualityFile != "" {
+		if err := scoreDocuments(docs, opts.quality, opts.qualityFile, logger, errFactory); err != nil {
+			return nil, err
+		}
+	}
+	if opts.check {
+		summary, err := checkDocuments(docs, opts.threshold, logger, errFactory)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, summary)
+	}
+	return out, nil
+}
+
+// checkDocuments checks the generated documents for the NTIA minimum
+// elements, rendering the results to stderr to keep stdout to the documents.
+// Documents generated in several formats are checked once. It returns the
+// test summary that fails the command if a score is below the threshold.
+func checkDocuments(docs []*sbomDocument, threshold int, logger *zerolog.Logger, errFactory *errors.ErrorFactory) (workflow.Data, error) {
+	r := view.NewRenderer(os.Stderr)
+	var checked []string
+	var results []*bom.NTIAResult
+	for _, d := range docs {
+		name := cmp.Or(d.name, "the generated SBOM")
+		if slices.Contains(checked, name) {
+			continue
+		}
+		doc, _, err := sbom.Decode(d.Doc)
+		if err != nil {
+			logger.Printf("Not checking the %s document of %s: %v\n", d.format, name, err)
+			continue
+		}
+		checked = append(checked, name)
+
+		res := bom.CheckNTIA(doc)
+		if err := r.RenderResult(name, res, threshold); err != nil {
+			return nil, errFactory.NewRenderError(err)
+		}
+		results = append(results, res)
+	}
+
+	summary, contentType, err := sbom.CheckSummary(strings.Join(checked, ", "), threshold, results...)
+	if err != nil {
+		return nil, errFactory.NewRenderError(err)
+	}
+	return workflow.NewData(workflow.NewTypeIdentifier(WorkflowID, "sbom.check"), contentType, summary), nil
+}
@@ -22,7 +22,10 @@ var DepGraphWorkflowID = workflow.NewWorkflowIdentifier("depgraph")
 type DepGraphResult struct {
 	Name          string
 	DepGraphBytes []json.RawMessage
-	ScanErrors    []service.ScanError
+	// Locations holds the content location (usually the target file) of
+	// each entry in DepGraphBytes.
+	Locations  []string
+	ScanErrors []service.ScanError
 }
 
 func GetDepGraph(ictx workflow.InvocationContext) (*DepGraphResult, error) {
@@ -44,6 +47,7 @@ func GetDepGraph(ictx workflow.InvocationContext) (*DepGraphResult, error) {
 	}
 
 	depGraphsBytes := make([]json.RawMessage, 0, len(depGraphs))
+	locations := make([]string, 0, len(depGraphs))
 	var scanErrors []service.ScanError
 	for _, depGraph := range depGraphs {
 		if errList := depGraph.GetErrorList(); len(errList) > 0 {
@@ -63,6 +67,7 @@ func GetDepGraph(ictx workflow.InvocationContext) (*DepGraphResult, error) {
 			return nil, errFactory.NewDepGraphWorkflowError(err)
 		}
 		depGraphsBytes = append(depGraphsBytes, depGraphBytes)
+		locations = append(locations, depGraph.GetContentLocation())
 	}
 
 	numGraphs := len(depGraphsBytes)
@@ -82,6 +87,7 @@ func GetDepGraph(ictx workflow.InvocationContext) (*DepGraphResult, error) {
 	return &DepGraphResult{
 		Name:          name,
 		DepGraphBytes: depGraphsBytes,
+		Locations:     locations,
 		ScanErrors:    scanErrors,
 	}, nil
 }
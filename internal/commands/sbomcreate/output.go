@@ -0,0 +1,150 @@
+package sbomcreate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Permissions of written SBOM files and directories, before umask.
+const (
+	outputFilePerm = 0o644
+	outputDirPerm  = 0o755
+)
+
+const (
+	// depGraphFormat names saved depgraph files, e.g. `package-lock.json.depgraph.json`.
+	depGraphFormat     = "depgraph+json"
+	scanErrorsFileName = "scan-errors.json"
+)
+
+var unsafeFileNameChars = regexp.MustCompile(`[^a-zA-Z0-9._@-]+`)
+
+// writeOutput writes the documents to the file or directory set by
+// `--output-file` or `--output-dir`.
+func writeOutput(conv *converter, opts *options, docs []*sbomDocument) error {
+	logger := conv.ictx.GetEnhancedLogger()
+
+	if opts.outputFile != "" {
+		if err := writeFileAtomic(opts.outputFile, docs[0].Doc); err != nil {
+			return conv.errFactory.NewFailedToWriteOutputError(err, opts.outputFile)
+		}
+		logger.Printf("SBOM document written to %s\n", opts.outputFile)
+		return nil
+	}
+
+	if err := writeOutputDir(opts.outputDir, docs); err != nil {
+		return conv.errFactory.NewFailedToWriteOutputError(err, opts.outputDir)
+	}
+	logger.Printf("SBOM documents written to %s\n", opts.outputDir)
+	return nil
+}
+
+// writeFileAtomic writes b to path through a temporary file in the same
+// directory, so readers never observe a partially written document.
+func writeFileAtomic(path string, b []byte) (err error) {
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+base+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()           //nolint:errcheck // The write already failed.
+			os.Remove(tmp.Name()) //nolint:errcheck // Best effort clean-up.
+		}
+	}()
+
+	if _, err = tmp.Write(b); err != nil {
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err = tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to sync temporary file: %w", err)
+	}
+	if err = tmp.Chmod(outputFilePerm); err != nil {
+		return fmt.Errorf("failed to set file permissions: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to rename temporary file: %w", err)
+	}
+
+	return nil
+}
+
+// writeOutputDir writes each document to dir, creating it if needed.
+func writeOutputDir(dir string, docs []*sbomDocument) error {
+	if err := os.MkdirAll(dir, outputDirPerm); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	names := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		if err := writeFileAtomic(filepath.Join(dir, uniqueFileName(names, doc.name, doc.format)), doc.Doc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveDepGraphs writes each depgraph of res, and the scan errors if there are
+// any, to dir as JSON files.
+func saveDepGraphs(dir string, res *DepGraphResult) error {
+	if err := os.MkdirAll(dir, outputDirPerm); err != nil {
+		return fmt.Errorf("failed to create depgraph directory: %w", err)
+	}
+
+	names := make(map[string]bool, len(res.DepGraphBytes))
+	for i, depGraph := range res.DepGraphBytes {
+		name := fmt.Sprintf("project-%d", i+1)
+		if i < len(res.Locations) && res.Locations[i] != "" {
+			name = res.Locations[i]
+		}
+		if err := writeFileAtomic(filepath.Join(dir, uniqueFileName(names, name, depGraphFormat)), depGraph); err != nil {
+			return err
+		}
+	}
+
+	if len(res.ScanErrors) == 0 {
+		return nil
+	}
+	b, err := json.MarshalIndent(res.ScanErrors, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode scan errors: %w", err)
+	}
+	return writeFileAtomic(filepath.Join(dir, scanErrorsFileName), b)
+}
+
+// uniqueFileName returns outputFileName(name, format), adding a numeric
+// suffix to name if that file name was taken before, so that files of
+// projects sharing a location don't overwrite each other. The returned name
+// is marked as taken.
+func uniqueFileName(taken map[string]bool, name, format string) string {
+	fileName := outputFileName(name, format)
+	for i := 2; taken[fileName]; i++ {
+		fileName = outputFileName(fmt.Sprintf("%s-%d", name, i), format)
+	}
+	taken[fileName] = true
+	return fileName
+}
+
+// outputFileName derives the name of an SBOM file in an output directory from
+// the name of what it describes (a project's content location or the subject)
+// and its format, e.g. `app-package-lock.json.cyclonedx1.6.json`.
+func outputFileName(name, format string) string {
+	name = strings.Trim(unsafeFileNameChars.ReplaceAllString(filepath.ToSlash(name), "-"), "-.")
+	if name == "" {
+		name = "sbom"
+	}
+	return name + "." + strings.ReplaceAll(format, "+", ".")
+}
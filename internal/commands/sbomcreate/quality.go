@@ -0,0 +1,45 @@
+package sbomcreate
+
+import (
+	"os"
+
+	"github.com/rs/zerolog"
+
+	"github.com/snyk/cli-extension-sbom/internal/errors"
+	"github.com/snyk/cli-extension-sbom/internal/sbom"
+	view "github.com/snyk/cli-extension-sbom/internal/view/sbomquality"
+)
+
+// scoreDocuments scores the quality of each generated document. The reports
+// are rendered to stderr if render is set, to keep stdout to the documents,
+// and written as JSON to file if it is set.
+func scoreDocuments(docs []*sbomDocument, render bool, file string, logger *zerolog.Logger, errFactory *errors.ErrorFactory) error {
+	r := view.NewRenderer(os.Stderr)
+	reports := make([]*sbom.QualityReport, 0, len(docs))
+	for _, d := range docs {
+		name := d.format
+		if d.name != "" {
+			name = d.name + " " + d.format
+		}
+		report, err := sbom.Quality(name, d.Doc)
+		if err != nil {
+			logger.Printf("Not scoring the %s document: %v\n", name, err)
+			continue
+		}
+		if render {
+			if err := r.RenderReport(report); err != nil {
+				return errFactory.NewRenderError(err)
+			}
+		}
+		reports = append(reports, report)
+	}
+
+	if file == "" {
+		return nil
+	}
+	if err := sbom.WriteQualityReports(file, reports); err != nil {
+		return errFactory.NewFailedToWriteOutputError(err, file)
+	}
+	logger.Printf("Quality reports written to %s\n", file)
+	return nil
+}
@@ -1,12 +1,21 @@
 package sbomcreate
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/snyk/go-application-framework/pkg/configuration"
 	"github.com/snyk/go-application-framework/pkg/local_workflows/config_utils"
 	"github.com/snyk/go-application-framework/pkg/workflow"
 
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/cmd_exec"
 	"github.com/snyk/cli-extension-sbom/internal/constants"
 	"github.com/snyk/cli-extension-sbom/internal/errors"
 	"github.com/snyk/cli-extension-sbom/internal/flags"
@@ -15,6 +24,8 @@ import (
 
 var WorkflowID = workflow.NewWorkflowIdentifier("sbom")
 
+const sourceDateEpochEnv = "SOURCE_DATE_EPOCH"
+
 func RegisterWorkflows(e workflow.Engine) error {
 	flagset := flags.GetSBOMCreateFlagSet()
 
@@ -35,26 +46,35 @@ func RegisterWorkflows(e workflow.Engine) error {
 }
 
 func SBOMWorkflow(
+	ictx workflow.InvocationContext,
+	d []workflow.Data,
+) ([]workflow.Data, error) {
+	return SBOMWorkflowWithDI(ictx, d, cmd_exec.NewCliGitInfoGetter())
+}
+
+func SBOMWorkflowWithDI(
 	ictx workflow.InvocationContext,
 	_ []workflow.Data,
+	gitInfoGetter cmd_exec.GitInfoGetter,
 ) ([]workflow.Data, error) {
 	config := ictx.GetConfiguration()
 	logger := ictx.GetEnhancedLogger()
-	format := config.GetString(flags.FlagFormat)
-	version := config.GetString(flags.FlagVersion)
-	goModuleLevel := config.GetBool(flags.FlagGoModuleLevel)
 	errFactory := errors.NewErrorFactory(logger)
 
 	logger.Println("SBOM workflow start")
 
-	if err := service.ValidateSBOMFormat(errFactory, format); err != nil {
+	opts, err := readOptions(config, errFactory)
+	if err != nil {
 		return nil, err
 	}
 
-	logger.Println("Getting preferred organization ID")
-	orgID, err := config.GetStringWithError(configuration.ORGANIZATION)
-	if err != nil {
-		return nil, err
+	var orgID string
+	if slices.ContainsFunc(opts.formats, func(f string) bool { return !generatesLocally(opts.local, f) }) {
+		logger.Println("Getting preferred organization ID")
+		orgID, err = config.GetStringWithError(configuration.ORGANIZATION)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	ai := ictx.GetAnalytics()
@@ -62,36 +82,405 @@ func SBOMWorkflow(
 	ai.AddExtensionBoolValue(constants.ShowNpmScope, config.GetBool(constants.FeatureFlagShowNpmScope))
 	ai.AddExtensionBoolValue(constants.SbomIncludeComponentMetadata, config.GetBool(constants.FeatureFlagSbomIncludeComponentMetadata))
 	ai.AddExtensionBoolValue(constants.AllowIncompleteSBOM, config.GetBool(flags.FlagAllowIncompleteSBOM))
+	ai.AddExtensionBoolValue(constants.Offline, opts.offline)
+	ai.AddExtensionBoolValue(constants.SplitProjects, opts.splitProjects)
+	ai.AddExtensionBoolValue(constants.Reproducible, opts.offlineOpts != nil)
 
 	depGraphResult, err := GetDepGraph(ictx)
 	if err != nil {
 		return nil, err
 	}
 
+	if dir := config.GetString(flags.FlagSaveDepGraphs); dir != "" {
+		if err := saveDepGraphs(dir, depGraphResult); err != nil {
+			return nil, errFactory.NewFailedToWriteOutputError(err, dir)
+		}
+		logger.Printf("Dependency graphs written to %s\n", dir)
+	}
+
 	ri := ictx.GetRuntimeInfo()
+	conv := &converter{
+		ictx:  ictx,
+		orgID: orgID,
+		tool:  &service.Tool{Vendor: "Snyk", Name: ri.GetName(), Version: ri.GetVersion()},
+		// The source revision is looked up once a document embedding it is
+		// generated.
+		metadata: sync.OnceValue(func() *service.Metadata {
+			vcs := sourceRevision(config, gitInfoGetter)
+			if vcs == nil {
+				return opts.metadata
+			}
+			logger.Printf("Source revision: %s\n", vcs.Commit)
+			return withVCS(opts.metadata, vcs)
+		}),
+		local:         opts.local,
+		offlineOpts:   opts.offlineOpts,
+		goModuleLevel: opts.goModuleLevel,
+		errFactory:    errFactory,
+	}
+	docs, projectDocs, err := convertDocuments(config, conv, opts, depGraphResult)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := assessDocuments(docs, opts, logger, errFactory)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.outputFile != "" || opts.outputDir != "" {
+		if err := writeOutput(conv, opts, append(docs, projectDocs...)); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+
+	logger.Print("Successfully generated SBOM document.\n")
+
+	return append(toWorkflowData(docs, opts.splitProjects), out...), nil
+}
+
+func toWorkflowData(docs []*sbomDocument, splitProjects bool) []workflow.Data {
+	out := make([]workflow.Data, 0, len(docs))
+	for _, doc := range docs {
+		d := newWorkflowData(nil, doc.MIMEType, doc.Doc)
+		// The content location lets downstream consumers, like output writers,
+		// tell the documents of a multi-format or split run apart.
+		if splitProjects {
+			d.SetContentLocation(doc.name)
+		} else {
+			d.SetContentLocation(doc.format)
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// options are the sbom create flags, parsed and validated.
+type options struct {
+	formats       []string
+	version       string
+	goModuleLevel bool
+	offline       bool
+	// local is set when all documents are generated without calling the SBOM
+	// conversion API.
+	local         bool
+	offlineOpts   *service.OfflineOptions
+	outputFile    string
+	outputDir     string
+	splitProjects bool
+	metadata      *service.Metadata
+	// check is set to check the generated documents for the NTIA minimum
+	// elements, failing if a score is below threshold.
+	check     bool
+	threshold int
+	// quality is set to print a quality report of each generated document,
+	// and qualityFile to write the reports as JSON.
+	quality     bool
+	qualityFile string
+}
+
+func readOptions(config configuration.Configuration, errFactory *errors.ErrorFactory) (*options, error) {
+	opts := &options{
+		version:       config.GetString(flags.FlagVersion),
+		goModuleLevel: config.GetBool(flags.FlagGoModuleLevel),
+		offline:       config.GetBool(flags.FlagOffline),
+		outputFile:    config.GetString(flags.FlagOutputFile),
+		outputDir:     config.GetString(flags.FlagOutputDir),
+		splitProjects: config.GetBool(flags.FlagSplitProjects),
+		check:         config.GetBool(flags.FlagCheck),
+		threshold:     config.GetInt(flags.FlagThreshold),
+		quality:       config.GetBool(flags.FlagQuality),
+		qualityFile:   config.GetString(flags.FlagQualityFile),
+	}
+	reproducible := config.GetBool(flags.FlagReproducible)
+
+	formats, err := service.ParseSBOMFormats(errFactory, config.GetString(flags.FlagFormat))
+	if err != nil {
+		return nil, err
+	}
+	opts.formats = formats
+
+	if err := checkFlagCombinations(opts, reproducible, errFactory); err != nil {
+		return nil, err
+	}
+
+	if reproducible {
+		// The SBOM conversion API stamps documents with the current time and a
+		// random serial number, so reproducible documents are generated locally.
+		ts, err := sourceDateEpoch()
+		if err != nil {
+			return nil, errFactory.NewInvalidSourceDateEpochError(err)
+		}
+		opts.offlineOpts = &service.OfflineOptions{Reproducible: true, Timestamp: ts}
+	}
+	opts.local = opts.offline || reproducible
+
+	metadata, ok, err := documentMetadata(config, errFactory)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		opts.metadata = metadata
+	}
+
+	return opts, nil
+}
+
+// checkFlagCombinations rejects flags that cannot be used together.
+func checkFlagCombinations(opts *options, reproducible bool, errFactory *errors.ErrorFactory) error {
+	if opts.outputFile != "" && opts.outputDir != "" {
+		return errFactory.NewConflictingOutputFlagsError()
+	}
+	if opts.outputFile != "" && len(opts.formats) > 1 {
+		return errFactory.NewOutputFileWithMultipleFormatsError()
+	}
+	if opts.outputFile != "" && opts.splitProjects {
+		return errFactory.NewOutputFileWithSplitProjectsError()
+	}
+	if opts.check && (opts.threshold < 0 || opts.threshold > 100) {
+		return errFactory.NewInvalidThresholdError(opts.threshold)
+	}
+
+	// Go module level aggregation is only available remotely.
+	switch {
+	case opts.goModuleLevel && reproducible:
+		return errFactory.NewGoModuleLevelWithLocalGenerationError(flags.FlagReproducible)
+	case opts.goModuleLevel && opts.offline:
+		return errFactory.NewGoModuleLevelWithLocalGenerationError(flags.FlagOffline)
+	}
+
+	return nil
+}
+
+// sbomDocument is a generated SBOM along with what it describes.
+type sbomDocument struct {
+	*service.SBOMResult
+	// name identifies what the document describes, either a project's
+	// location or the subject. It is used to name output files.
+	name   string
+	format string
+}
+
+// converter turns depgraphs into SBOM documents, either locally or through
+// the SBOM conversion API depending on the format.
+type converter struct {
+	ictx  workflow.InvocationContext
+	orgID string
+	tool  *service.Tool
+	// metadata returns the metadata of the documents, including the source
+	// revision.
+	metadata      func() *service.Metadata
+	local         bool
+	offlineOpts   *service.OfflineOptions
+	goModuleLevel bool
+	errFactory    *errors.ErrorFactory
+}
+
+func (c *converter) convert(
+	depGraphs []json.RawMessage,
+	scanErrors []service.ScanError,
+	subject *service.Subject,
+	format string,
+) (*service.SBOMResult, error) {
+	logger := c.ictx.GetEnhancedLogger()
+
+	if generatesLocally(c.local, format) {
+		if c.goModuleLevel {
+			logger.Println("Go module level aggregation is not available for local generation, emitting package level dependencies")
+		}
+		return service.DepGraphsToSBOMOffline(depGraphs, scanErrors, subject, c.tool, c.metadata(), format, c.offlineOpts, logger, c.errFactory)
+	}
 
-	result, err := service.DepGraphsToSBOM(
-		ictx.GetNetworkAccess().GetHttpClient(),
-		config.GetString(configuration.API_URL),
-		orgID,
-		depGraphResult.DepGraphBytes,
-		depGraphResult.ScanErrors,
-		service.NewSubject(depGraphResult.Name, version),
-		&service.Tool{Vendor: "Snyk", Name: ri.GetName(), Version: ri.GetVersion()},
+	return service.DepGraphsToSBOM(
+		c.ictx.GetNetworkAccess().GetHttpClient(),
+		c.ictx.GetConfiguration().GetString(configuration.API_URL),
+		c.orgID,
+		depGraphs,
+		scanErrors,
+		subject,
+		c.tool,
+		c.metadata(),
 		format,
-		goModuleLevel,
+		c.goModuleLevel,
 		logger,
-		errFactory,
+		c.errFactory,
 	)
+}
+
+// convertDocuments generates the documents of the run: one per project and
+// format with `--split-projects`, or else one per format describing all
+// projects. With `--output-dir` and `--all-projects`, the latter are
+// accompanied by the documents of each project, which are returned apart.
+func convertDocuments(
+	config configuration.Configuration,
+	conv *converter,
+	opts *options,
+	res *DepGraphResult,
+) (docs, projectDocs []*sbomDocument, err error) {
+	subject := service.NewSubject(res.Name, opts.version)
+	switch {
+	case opts.splitProjects:
+		docs, err = conv.convertProjects(res, opts.formats)
+	case opts.outputDir != "" && config.GetBool(flags.FlagAllProjects) && res.Name != "":
+		// Without a subject, the aggregate document already describes the
+		// only project.
+		docs, projectDocs, err = conv.convertWithProjects(res, subject, opts.formats)
+	default:
+		docs, err = conv.convertAggregate(res, subject, opts.formats)
+	}
+	return docs, projectDocs, err
+}
+
+// convertAggregate generates one document per format, describing all
+// depgraphs and scan errors under the given subject.
+func (c *converter) convertAggregate(res *DepGraphResult, subject *service.Subject, formats []string) ([]*sbomDocument, error) {
+	name := res.Name
+	if name == "" && len(res.Locations) == 1 {
+		name = res.Locations[0]
+	}
+
+	docs := make([]*sbomDocument, 0, len(formats))
+	for _, format := range formats {
+		result, err := c.convert(res.DepGraphBytes, res.ScanErrors, subject, format)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, &sbomDocument{SBOMResult: result, name: name, format: format})
+	}
+
+	return docs, nil
+}
+
+// convertProjects generates one document per project and format.
+func (c *converter) convertProjects(res *DepGraphResult, formats []string) ([]*sbomDocument, error) {
+	projects, err := splitProjects(res)
 	if err != nil {
-		return nil, err
+		return nil, c.errFactory.NewFatalSBOMGenerationError(err)
 	}
+	return c.convertEach(projects, formats)
+}
 
-	sbomDoc := []workflow.Data{newWorkflowData(nil, result.MIMEType, result.Doc)}
+// convertWithProjects generates one document per project and format, along
+// with the aggregate document of each format, which is generated like that of
+// a run without `--output-dir`.
+func (c *converter) convertWithProjects(
+	res *DepGraphResult,
+	subject *service.Subject,
+	formats []string,
+) (docs, projectDocs []*sbomDocument, err error) {
+	projects, err := splitProjects(res)
+	if err != nil {
+		return nil, nil, c.errFactory.NewFatalSBOMGenerationError(err)
+	}
+	projectDocs, err = c.convertEach(projects, formats)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	logger.Print("Successfully generated SBOM document.\n")
+	docs, err = c.convertAggregate(res, subject, formats)
+	if err != nil {
+		return nil, nil, err
+	}
+	return docs, projectDocs, nil
+}
+
+// convertEach generates one document per project and format.
+func (c *converter) convertEach(projects []*project, formats []string) ([]*sbomDocument, error) {
+	docs := make([]*sbomDocument, 0, len(projects)*len(formats))
+	for i, p := range projects {
+		name := p.location
+		if name == "" {
+			name = fmt.Sprintf("project-%d", i+1)
+		}
+		for _, format := range formats {
+			result, err := c.convert(p.depGraphs, p.scanErrors, p.subject, format)
+			if err != nil {
+				return nil, err
+			}
+			docs = append(docs, &sbomDocument{SBOMResult: result, name: name, format: format})
+		}
+	}
+
+	return docs, nil
+}
+
+// generatesLocally reports whether the SBOM in the given format is generated
+// without calling the SBOM conversion API.
+func generatesLocally(local bool, format string) bool {
+	return local || service.RequiresLocalConversion(format)
+}
+
+// documentMetadata reads the document metadata flags, reporting whether any
+// are set.
+func documentMetadata(config configuration.Configuration, errFactory *errors.ErrorFactory) (metadata *service.Metadata, ok bool, err error) {
+	m := &service.Metadata{
+		Supplier:     strings.TrimSpace(config.GetString(flags.FlagSupplier)),
+		Authors:      splitList(config.GetString(flags.FlagAuthor)),
+		Manufacturer: strings.TrimSpace(config.GetString(flags.FlagManufacturer)),
+		Lifecycles:   splitList(config.GetString(flags.FlagLifecycle)),
+	}
+
+	for _, l := range m.Lifecycles {
+		if !slices.Contains(bom.LifecyclePhases[:], l) {
+			return nil, false, errFactory.NewInvalidLifecycleError(l, bom.LifecyclePhases[:])
+		}
+	}
+
+	ok = m.Supplier != "" || len(m.Authors) > 0 || m.Manufacturer != "" || len(m.Lifecycles) > 0
+	return m, ok, nil
+}
+
+// sourceRevision returns the git revision of the scanned directory, or nil if
+// it is not in a git working tree.
+func sourceRevision(config configuration.Configuration, gitInfoGetter cmd_exec.GitInfoGetter) *service.VCS {
+	dirs, err := getInputDirectories(config)
+	if err != nil {
+		return nil
+	}
+	info := gitInfoGetter.GetGitInfo(dirs[0])
+	if info == nil {
+		return nil
+	}
+	return &service.VCS{URL: info.RemoteURL, Commit: info.Commit, Branch: info.Branch, Dirty: info.Dirty}
+}
+
+// withVCS returns a copy of metadata, which may be nil, recording the source
+// revision.
+func withVCS(metadata *service.Metadata, vcs *service.VCS) *service.Metadata {
+	m := service.Metadata{}
+	if metadata != nil {
+		m = *metadata
+	}
+	m.VCS = vcs
+	return &m
+}
 
-	return sbomDoc, nil
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(v string) []string {
+	var out []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// sourceDateEpoch returns the time set by the SOURCE_DATE_EPOCH environment
+// variable (see https://reproducible-builds.org/specs/source-date-epoch/),
+// or the UNIX epoch if it is not set.
+func sourceDateEpoch() (time.Time, error) {
+	v, ok := os.LookupEnv(sourceDateEpochEnv)
+	if !ok || v == "" {
+		return time.Unix(0, 0).UTC(), nil
+	}
+
+	secs, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q: %w", sourceDateEpochEnv, v, err)
+	}
+	return time.Unix(secs, 0).UTC(), nil
 }
 
 func newWorkflowData(depGraph workflow.Data, contentType string, sbom []byte) workflow.Data {
@@ -2,12 +2,14 @@ package sbomcreate_test
 
 import (
 	_ "embed"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -16,6 +18,8 @@ import (
 	"github.com/snyk/error-catalog-golang-public/snyk_errors"
 	"github.com/snyk/go-application-framework/pkg/analytics"
 	"github.com/snyk/go-application-framework/pkg/configuration"
+	"github.com/snyk/go-application-framework/pkg/local_workflows/content_type"
+	"github.com/snyk/go-application-framework/pkg/local_workflows/json_schemas"
 	"github.com/snyk/go-application-framework/pkg/mocks"
 	"github.com/snyk/go-application-framework/pkg/networking"
 	"github.com/snyk/go-application-framework/pkg/runtimeinfo"
@@ -23,6 +27,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/snyk/cli-extension-sbom/internal/cmd_exec"
 	"github.com/snyk/cli-extension-sbom/internal/commands/sbomcreate"
 	"github.com/snyk/cli-extension-sbom/internal/commands/sbomtest"
 	"github.com/snyk/cli-extension-sbom/internal/constants"
@@ -88,6 +93,593 @@ func TestSBOMWorkflow_GoModuleLevel(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestSBOMWorkflow_Offline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No SBOM service is running, so any request would fail.
+	mockICTX := mockInvocationContext(t, ctrl, "http://localhost:0", nil)
+	mockICTX.GetConfiguration().Set(flags.FlagOffline, true)
+	mockICTX.GetConfiguration().Unset(configuration.ORGANIZATION)
+	mockICTX.GetConfiguration().Set("name", "")
+
+	results, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "application/vnd.cyclonedx+json", results[0].GetContentType())
+	sbomBytes, ok := results[0].GetPayload().([]byte)
+	require.True(t, ok)
+
+	var doc struct {
+		BOMFormat   string `json:"bomFormat"`
+		SpecVersion string `json:"specVersion"`
+		Metadata    struct {
+			Component struct {
+				Name string `json:"name"`
+			} `json:"component"`
+		} `json:"metadata"`
+		Components []struct {
+			PURL string `json:"purl"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(sbomBytes, &doc))
+	assert.Equal(t, "CycloneDX", doc.BOMFormat)
+	assert.Equal(t, "1.4", doc.SpecVersion)
+	assert.Equal(t, "demo-app-for-test", doc.Metadata.Component.Name)
+	require.Len(t, doc.Components, 2)
+	assert.Equal(t, "pkg:npm/express@4.4.0", doc.Components[0].PURL)
+	assert.Equal(t, "pkg:npm/ws@1.0.0", doc.Components[1].PURL)
+}
+
+func TestSBOMWorkflow_Check(t *testing.T) {
+	tc := []struct {
+		name      string
+		threshold int
+		open      int
+	}{
+		{name: "below threshold", threshold: flags.DefaultCheckThreshold, open: 3},
+		{name: "meets threshold", threshold: 50, open: 0},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockICTX := mockInvocationContext(t, ctrl, "http://localhost:0", nil)
+			mockICTX.GetConfiguration().Set(flags.FlagOffline, true)
+			mockICTX.GetConfiguration().Set(flags.FlagFormat, "cyclonedx1.4+json,spdx2.3+json")
+			mockICTX.GetConfiguration().Set(flags.FlagCheck, true)
+			mockICTX.GetConfiguration().Set(flags.FlagThreshold, tt.threshold)
+			mockICTX.GetConfiguration().Set("name", "")
+
+			var results []workflow.Data
+			stderr := captureStderr(t, func() {
+				var err error
+				results, err = sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+				require.NoError(t, err)
+			})
+
+			require.Len(t, results, 3)
+			assert.Equal(t, content_type.TEST_SUMMARY, results[2].GetContentType())
+			payload, ok := results[2].GetPayload().([]byte)
+			require.True(t, ok)
+			var summary json_schemas.TestSummary
+			require.NoError(t, json.Unmarshal(payload, &summary))
+			assert.Equal(t, 1, summary.Artifacts)
+			assert.Equal(t, 3, summary.Results[0].Total)
+			assert.Equal(t, tt.open, summary.Results[0].Open)
+
+			// The document is checked once, although generated in two formats.
+			assert.Equal(t, 1, strings.Count(stderr, "NTIA minimum elements of"))
+			assert.Contains(t, stderr, "express@4.4.0: missing supplier")
+		})
+	}
+}
+
+func TestSBOMWorkflow_Check_InvalidThreshold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockICTX := mockInvocationContext(t, ctrl, "http://localhost:0", nil)
+	mockICTX.GetConfiguration().Set(flags.FlagCheck, true)
+	mockICTX.GetConfiguration().Set(flags.FlagThreshold, -1)
+
+	_, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+
+	var snykErr snyk_errors.Error
+	require.True(t, errors.As(err, &snykErr))
+	assert.Equal(t, "Flag `--threshold` must be a score between 0 and 100 (got -1).", snykErr.Detail)
+}
+
+func TestSBOMWorkflow_Quality(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	qualityFile := filepath.Join(t.TempDir(), "quality.json")
+	mockICTX := mockInvocationContext(t, ctrl, "http://localhost:0", nil)
+	mockICTX.GetConfiguration().Set(flags.FlagOffline, true)
+	mockICTX.GetConfiguration().Set(flags.FlagFormat, "cyclonedx1.6+json,spdx2.3+json")
+	mockICTX.GetConfiguration().Set(flags.FlagQuality, true)
+	mockICTX.GetConfiguration().Set(flags.FlagQualityFile, qualityFile)
+	mockICTX.GetConfiguration().Set("name", "")
+
+	var results []workflow.Data
+	stderr := captureStderr(t, func() {
+		var err error
+		results, err = sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+		require.NoError(t, err)
+	})
+
+	// Quality reports do not fail the command, so only the documents are
+	// returned.
+	require.Len(t, results, 2)
+	assert.Contains(t, stderr, "SBOM quality of cyclonedx1.6+json (CycloneDX 1.6)")
+	assert.Contains(t, stderr, "SBOM quality of spdx2.3+json (SPDX 2.3)")
+
+	b, err := os.ReadFile(qualityFile)
+	require.NoError(t, err)
+	var reports []struct {
+		Name   string `json:"name"`
+		Format struct {
+			Standard string `json:"standard"`
+		} `json:"format"`
+		Categories []struct {
+			Name  string `json:"name"`
+			Score int    `json:"score"`
+		} `json:"categories"`
+	}
+	require.NoError(t, json.Unmarshal(b, &reports))
+	require.Len(t, reports, 2)
+	assert.Equal(t, "cyclonedx1.6+json", reports[0].Name)
+	assert.Equal(t, "SPDX", reports[1].Format.Standard)
+	assert.Equal(t, "spec compliance", reports[1].Categories[5].Name)
+	assert.Equal(t, 100, reports[1].Categories[5].Score)
+}
+
+func TestSBOMWorkflow_SPDX3GeneratedLocally(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The SBOM conversion API does not support SPDX 3.0, so no request is expected.
+	mockICTX := mockInvocationContext(t, ctrl, "http://localhost:0", nil)
+	mockICTX.GetConfiguration().Set(flags.FlagFormat, "spdx3.0+json")
+	mockICTX.GetConfiguration().Set("name", "")
+
+	results, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "application/spdx+json", results[0].GetContentType())
+	sbomBytes, ok := results[0].GetPayload().([]byte)
+	require.True(t, ok)
+	assert.Contains(t, string(sbomBytes), `"@context":"https://spdx.org/rdf/3.0.1/spdx-context.jsonld"`)
+	assert.Contains(t, string(sbomBytes), `"software_packageUrl":"pkg:npm/express@4.4.0"`)
+}
+
+func TestSBOMWorkflow_MultipleFormats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var requestedFormats []string
+	mockResponse := svcmocks.NewMockResponse("application/vnd.cyclonedx+json", expectedSBOM, http.StatusOK)
+	mockSBOMService := svcmocks.NewMockSBOMService(mockResponse, func(r *http.Request) {
+		requestedFormats = append(requestedFormats, r.URL.Query().Get("format"))
+	})
+	defer mockSBOMService.Close()
+
+	mockEngine := mocks.NewMockEngine(ctrl)
+	mockEngine.EXPECT().
+		InvokeWithConfig(gomock.Eq(sbomcreate.DepGraphWorkflowID), gomock.Any()).
+		Return([]workflow.Data{newDepGraphData(t, depGraphData)}, nil).
+		Times(1)
+	mockICTX := mockInvocationContext(t, ctrl, mockSBOMService.URL, mockEngine)
+	mockICTX.GetConfiguration().Set(flags.FlagFormat, "cyclonedx1.6+json,spdx3.0+json")
+	mockICTX.GetConfiguration().Set("name", "")
+
+	results, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, []string{"cyclonedx1.6+json"}, requestedFormats, "only formats supported by the API are converted remotely")
+	assert.Equal(t, "application/vnd.cyclonedx+json", results[0].GetContentType())
+	assert.Equal(t, "cyclonedx1.6+json", results[0].GetContentLocation())
+	assert.Equal(t, "application/spdx+json", results[1].GetContentType())
+	assert.Equal(t, "spdx3.0+json", results[1].GetContentLocation())
+}
+
+func TestSBOMWorkflow_OutputFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockResponse := svcmocks.NewMockResponse("application/vnd.cyclonedx+json", expectedSBOM, http.StatusOK)
+	mockSBOMService := svcmocks.NewMockSBOMService(mockResponse)
+	defer mockSBOMService.Close()
+	outputFile := filepath.Join(t.TempDir(), "sbom.cdx.json")
+	mockICTX := mockInvocationContext(t, ctrl, mockSBOMService.URL, nil)
+	mockICTX.GetConfiguration().Set(flags.FlagOutputFile, outputFile)
+
+	results, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	written, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Equal(t, string(expectedSBOM), string(written))
+	entries, err := os.ReadDir(filepath.Dir(outputFile))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files are left behind")
+}
+
+func TestSBOMWorkflow_OutputFileWithMultipleFormats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockICTX := mockInvocationContext(t, ctrl, "", nil)
+	mockICTX.GetConfiguration().Set(flags.FlagFormat, "cyclonedx1.6+json,spdx2.3+json")
+	mockICTX.GetConfiguration().Set(flags.FlagOutputFile, filepath.Join(t.TempDir(), "sbom.json"))
+
+	_, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+
+	var catalogErr snyk_errors.Error
+	require.ErrorAs(t, err, &catalogErr)
+	assert.Contains(t, catalogErr.Detail, "can only be used with a single format")
+}
+
+func TestSBOMWorkflow_ConflictingOutputFlags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockICTX := mockInvocationContext(t, ctrl, "", nil)
+	mockICTX.GetConfiguration().Set(flags.FlagOutputFile, filepath.Join(t.TempDir(), "sbom.json"))
+	mockICTX.GetConfiguration().Set(flags.FlagOutputDir, t.TempDir())
+
+	_, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+
+	var catalogErr snyk_errors.Error
+	require.ErrorAs(t, err, &catalogErr)
+	assert.Contains(t, catalogErr.Detail, "cannot be used together")
+}
+
+func TestSBOMWorkflow_OutputDir_AllProjects(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	otherDepGraph := []byte(`{"pkgManager":{"name":"pip"},"pkgs":[{"id":"api@0.1.0","info":{"name":"api","version":"0.1.0"}}],` +
+		`"graph":{"rootNodeId":"root-node","nodes":[{"nodeId":"root-node","pkgId":"api@0.1.0","deps":[]}]}}`)
+	mockEngine := newMockEngine(ctrl, []workflow.Data{
+		newDepGraphDataWithLocation(t, depGraphData, "package-lock.json"),
+		newDepGraphDataWithLocation(t, otherDepGraph, "api/requirements.txt"),
+	}, nil)
+	outputDir := filepath.Join(t.TempDir(), "sboms")
+	mockICTX := mockInvocationContext(t, ctrl, "", mockEngine)
+	mockICTX.GetConfiguration().Set(flags.FlagOffline, true)
+	mockICTX.GetConfiguration().Set(flags.FlagAllProjects, true)
+	mockICTX.GetConfiguration().Set(flags.FlagFormat, "cyclonedx1.6+json,spdx2.3+json")
+	mockICTX.GetConfiguration().Set(flags.FlagOutputDir, outputDir)
+	mockICTX.GetConfiguration().Set("name", "my-repo")
+
+	results, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	entries, err := os.ReadDir(outputDir)
+	require.NoError(t, err)
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.ElementsMatch(t, []string{
+		"my-repo.cyclonedx1.6.json",
+		"my-repo.spdx2.3.json",
+		"package-lock.json.cyclonedx1.6.json",
+		"package-lock.json.spdx2.3.json",
+		"api-requirements.txt.cyclonedx1.6.json",
+		"api-requirements.txt.spdx2.3.json",
+	}, names)
+
+	project, err := os.ReadFile(filepath.Join(outputDir, "api-requirements.txt.cyclonedx1.6.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(project), `"purl":"pkg:pypi/api@0.1.0"`)
+	assert.NotContains(t, string(project), "demo-app-for-test")
+}
+
+func TestSBOMWorkflow_OutputDir_AllProjects_RequestsAggregate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The service serves one response per project document and one for the
+	// aggregate document, and fails on any other request.
+	mockResponse := svcmocks.NewMockResponse("application/vnd.cyclonedx+json", expectedSBOM, http.StatusOK)
+	var requests []string
+	mockSBOMService := svcmocks.NewMockSBOMServiceMultiResponse([]svcmocks.MockResponse{mockResponse, mockResponse, mockResponse, mockResponse},
+		func(r *http.Request) {
+			b, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			requests = append(requests, string(b))
+		})
+	defer mockSBOMService.Close()
+	mockEngine := newMockEngine(ctrl, []workflow.Data{
+		newDepGraphDataWithLocation(t, depGraphData, "package-lock.json"),
+		newDepGraphDataWithLocation(t, depGraphData, "api/package-lock.json"),
+		newDepGraphDataWithError(t, "broken/pom.xml", &snyk_errors.Error{Detail: "missing lockfile"}),
+	}, nil)
+	outputDir := t.TempDir()
+	mockICTX := mockInvocationContext(t, ctrl, mockSBOMService.URL, mockEngine)
+	mockICTX.GetConfiguration().Set(flags.FlagAllProjects, true)
+	mockICTX.GetConfiguration().Set(flags.FlagFormat, "cyclonedx1.6+json")
+	mockICTX.GetConfiguration().Set(flags.FlagOutputDir, outputDir)
+	mockICTX.GetConfiguration().Set("name", "my-repo")
+
+	_, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	entries, err := os.ReadDir(outputDir)
+	require.NoError(t, err)
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.ElementsMatch(t, []string{
+		"my-repo.cyclonedx1.6.json",
+		"package-lock.json.cyclonedx1.6.json",
+		"api-package-lock.json.cyclonedx1.6.json",
+		"broken-pom.xml.cyclonedx1.6.json",
+	}, names)
+
+	// The aggregate document is written as the API returned it.
+	aggregate, err := os.ReadFile(filepath.Join(outputDir, "my-repo.cyclonedx1.6.json"))
+	require.NoError(t, err)
+	assert.Equal(t, expectedSBOM, aggregate)
+	require.Len(t, requests, 4)
+	assert.Contains(t, requests[2], "missing lockfile", "scan errors are converted with their project")
+	assert.Contains(t, requests[3], "my-repo")
+	assert.Contains(t, requests[3], "missing lockfile")
+}
+
+func TestSBOMWorkflow_OutputDir_UniqueFileNames(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEngine := newMockEngine(ctrl, []workflow.Data{
+		newDepGraphDataWithLocation(t, depGraphData, "app"),
+		newDepGraphDataWithLocation(t, depGraphData, "app"),
+		newDepGraphDataWithLocation(t, depGraphData, "app-2"),
+	}, nil)
+	outputDir := t.TempDir()
+	mockICTX := mockInvocationContext(t, ctrl, "", mockEngine)
+	mockICTX.GetConfiguration().Set(flags.FlagOffline, true)
+	mockICTX.GetConfiguration().Set(flags.FlagSplitProjects, true)
+	mockICTX.GetConfiguration().Set(flags.FlagOutputDir, outputDir)
+
+	_, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	entries, err := os.ReadDir(outputDir)
+	require.NoError(t, err)
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.ElementsMatch(t, []string{"app.cyclonedx1.4.json", "app-2.cyclonedx1.4.json", "app-2-2.cyclonedx1.4.json"}, names)
+}
+
+func TestSBOMWorkflow_SplitProjects(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	otherDepGraph := []byte(`{"pkgManager":{"name":"pip"},"pkgs":[{"id":"api@0.1.0","info":{"name":"api","version":"0.1.0"}}],` +
+		`"graph":{"rootNodeId":"root-node","nodes":[{"nodeId":"root-node","pkgId":"api@0.1.0","deps":[]}]}}`)
+	mockEngine := newMockEngine(ctrl, []workflow.Data{
+		newDepGraphDataWithLocation(t, depGraphData, "package-lock.json"),
+		newDepGraphDataWithLocation(t, otherDepGraph, "api/requirements.txt"),
+		newDepGraphDataWithError(t, "api/requirements.txt", &snyk_errors.Error{Detail: "unresolved extras"}),
+		newDepGraphDataWithError(t, "broken/pom.xml", &snyk_errors.Error{Detail: "missing lockfile"}),
+	}, nil)
+	mockICTX := mockInvocationContext(t, ctrl, "", mockEngine)
+	mockICTX.GetConfiguration().Set(flags.FlagOffline, true)
+	mockICTX.GetConfiguration().Set(flags.FlagAllProjects, true)
+	mockICTX.GetConfiguration().Set(flags.FlagSplitProjects, true)
+	mockICTX.GetConfiguration().Set("name", "my-repo")
+
+	results, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	type document struct {
+		Metadata struct {
+			Component struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+			} `json:"component"`
+			Properties []struct {
+				Value string `json:"value"`
+			} `json:"properties"`
+		} `json:"metadata"`
+	}
+	docs := make(map[string]document, len(results))
+	for _, r := range results {
+		payload, ok := r.GetPayload().([]byte)
+		require.True(t, ok)
+		var doc document
+		require.NoError(t, json.Unmarshal(payload, &doc))
+		docs[r.GetContentLocation()] = doc
+	}
+
+	npm := docs["package-lock.json"]
+	assert.Equal(t, "demo-app-for-test", npm.Metadata.Component.Name)
+	assert.Equal(t, "1.1.1", npm.Metadata.Component.Version)
+	assert.Empty(t, npm.Metadata.Properties)
+
+	api := docs["api/requirements.txt"]
+	assert.Equal(t, "api", api.Metadata.Component.Name)
+	assert.Equal(t, "0.1.0", api.Metadata.Component.Version)
+	require.Len(t, api.Metadata.Properties, 1)
+	assert.Equal(t, "api/requirements.txt: unresolved extras", api.Metadata.Properties[0].Value)
+
+	broken := docs["broken/pom.xml"]
+	assert.Equal(t, "broken/pom.xml", broken.Metadata.Component.Name)
+	require.Len(t, broken.Metadata.Properties, 1)
+	assert.Equal(t, "broken/pom.xml: missing lockfile", broken.Metadata.Properties[0].Value)
+}
+
+func TestSBOMWorkflow_SplitProjectsWithOutputFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockICTX := mockInvocationContext(t, ctrl, "", nil)
+	mockICTX.GetConfiguration().Set(flags.FlagSplitProjects, true)
+	mockICTX.GetConfiguration().Set(flags.FlagOutputFile, filepath.Join(t.TempDir(), "sbom.json"))
+
+	_, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+
+	var catalogErr snyk_errors.Error
+	require.ErrorAs(t, err, &catalogErr)
+	assert.Contains(t, catalogErr.Detail, "cannot be used with `--split-projects`")
+}
+
+func TestSBOMWorkflow_SaveDepGraphs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	otherDepGraph := []byte(`{"pkgManager":{"name":"gradle"},"pkgs":[{"id":"sub@1.0.0","info":{"name":"sub","version":"1.0.0"}}],` +
+		`"graph":{"rootNodeId":"root-node","nodes":[{"nodeId":"root-node","pkgId":"sub@1.0.0","deps":[]}]}}`)
+	mockEngine := newMockEngine(ctrl, []workflow.Data{
+		newDepGraphDataWithLocation(t, depGraphData, "build.gradle"),
+		newDepGraphDataWithLocation(t, otherDepGraph, "build.gradle"),
+		newDepGraphDataWithError(t, "broken/pom.xml", &snyk_errors.Error{Detail: "missing lockfile"}),
+	}, nil)
+	depGraphDir := filepath.Join(t.TempDir(), "depgraphs")
+	mockICTX := mockInvocationContext(t, ctrl, "", mockEngine)
+	mockICTX.GetConfiguration().Set(flags.FlagOffline, true)
+	mockICTX.GetConfiguration().Set(flags.FlagSaveDepGraphs, depGraphDir)
+	mockICTX.GetConfiguration().Set("name", "my-repo")
+
+	results, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	assert.Len(t, results, 1, "the SBOM is still produced")
+
+	saved, err := os.ReadFile(filepath.Join(depGraphDir, "build.gradle.depgraph.json"))
+	require.NoError(t, err)
+	assert.Equal(t, string(depGraphData), string(saved))
+	saved, err = os.ReadFile(filepath.Join(depGraphDir, "build.gradle-2.depgraph.json"))
+	require.NoError(t, err)
+	assert.Equal(t, string(otherDepGraph), string(saved))
+	saved, err = os.ReadFile(filepath.Join(depGraphDir, "scan-errors.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"subject":"broken/pom.xml","text":"missing lockfile"}]`, string(saved))
+}
+
+func TestSBOMWorkflow_Reproducible(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+
+	run := func() []byte {
+		// No SBOM service is running, reproducible documents are generated locally.
+		mockICTX := mockInvocationContext(t, ctrl, "http://localhost:0", nil)
+		mockICTX.GetConfiguration().Set(flags.FlagReproducible, true)
+		mockICTX.GetConfiguration().Set(flags.FlagFormat, "cyclonedx1.6+json")
+		mockICTX.GetConfiguration().Set("name", "")
+
+		results, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		sbomBytes, ok := results[0].GetPayload().([]byte)
+		require.True(t, ok)
+		return sbomBytes
+	}
+
+	first := run()
+	assert.Equal(t, string(first), string(run()))
+	assert.Contains(t, string(first), `"timestamp":"2023-11-14T22:13:20Z"`)
+}
+
+func TestSBOMWorkflow_GoModuleLevelWithLocalGeneration(t *testing.T) {
+	for _, flag := range []string{flags.FlagReproducible, flags.FlagOffline} {
+		t.Run(flag, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockICTX := mockInvocationContext(t, ctrl, "", nil)
+			mockICTX.GetConfiguration().Set(flags.FlagGoModuleLevel, true)
+			mockICTX.GetConfiguration().Set(flag, true)
+
+			_, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+
+			var catalogErr snyk_errors.Error
+			require.ErrorAs(t, err, &catalogErr)
+			assert.Contains(t, catalogErr.Detail, "The `--go-module-level` flag cannot be used with `--"+flag+"`")
+		})
+	}
+}
+
+func TestSBOMWorkflow_Reproducible_InvalidSourceDateEpoch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	t.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+	mockICTX := mockInvocationContext(t, ctrl, "", nil)
+	mockICTX.GetConfiguration().Set(flags.FlagReproducible, true)
+
+	_, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+
+	assert.ErrorContains(t, err, "SOURCE_DATE_EPOCH environment variable must be a UNIX timestamp")
+}
+
+func TestSBOMWorkflow_Metadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockICTX := mockInvocationContext(t, ctrl, "http://localhost:0", nil)
+	config := mockICTX.GetConfiguration()
+	config.Set(flags.FlagOffline, true)
+	config.Set(flags.FlagFormat, "cyclonedx1.6+json")
+	config.Set(flags.FlagSupplier, "ACME")
+	config.Set(flags.FlagAuthor, "Jane Doe, John Doe")
+	config.Set(flags.FlagLifecycle, "build")
+	config.Set("name", "")
+
+	results, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	sbomBytes, ok := results[0].GetPayload().([]byte)
+	require.True(t, ok)
+
+	var doc struct {
+		Metadata struct {
+			Lifecycles []struct {
+				Phase string `json:"phase"`
+			} `json:"lifecycles"`
+			Authors []struct {
+				Name string `json:"name"`
+			} `json:"authors"`
+			Supplier struct {
+				Name string `json:"name"`
+			} `json:"supplier"`
+		} `json:"metadata"`
+	}
+	require.NoError(t, json.Unmarshal(sbomBytes, &doc))
+	require.Len(t, doc.Metadata.Authors, 2)
+	assert.Equal(t, "Jane Doe", doc.Metadata.Authors[0].Name)
+	assert.Equal(t, "John Doe", doc.Metadata.Authors[1].Name)
+	assert.Equal(t, "ACME", doc.Metadata.Supplier.Name)
+	require.Len(t, doc.Metadata.Lifecycles, 1)
+	assert.Equal(t, "build", doc.Metadata.Lifecycles[0].Phase)
+}
+
+func TestSBOMWorkflow_InvalidLifecycle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockICTX := mockInvocationContext(t, ctrl, "", nil)
+	mockICTX.GetConfiguration().Set(flags.FlagLifecycle, "build,shipping")
+
+	_, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+
+	var catalogErr snyk_errors.Error
+	require.ErrorAs(t, err, &catalogErr)
+	assert.Contains(t, catalogErr.Detail, "The lifecycle provided (shipping) is not one of the available lifecycle phases.")
+}
+
 func TestSBOMWorkflow_EmptyFormat(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -108,7 +700,8 @@ func TestSBOMWorkflow_InvalidFormat(t *testing.T) {
 	_, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
 
 	assert.ErrorContains(t, err, "The format provided (cyclonedx+json) is not one of the available formats. "+
-		"Available formats are: cyclonedx1.4+json, cyclonedx1.4+xml, cyclonedx1.5+json, cyclonedx1.5+xml, cyclonedx1.6+json, cyclonedx1.6+xml, spdx2.3+json")
+		"Available formats are: cyclonedx1.4+json, cyclonedx1.4+xml, cyclonedx1.5+json, cyclonedx1.5+xml, "+
+		"cyclonedx1.6+json, cyclonedx1.6+xml, spdx2.3+json, spdx3.0+json")
 }
 
 func TestSBOMWorkflow_NoOrgID(t *testing.T) {
@@ -122,6 +715,19 @@ func TestSBOMWorkflow_NoOrgID(t *testing.T) {
 	mockConfig.EXPECT().GetString(flags.FlagFormat).Return("cyclonedx1.4+json")
 	mockConfig.EXPECT().GetString(flags.FlagVersion).Return("0.0.0")
 	mockConfig.EXPECT().GetBool(flags.FlagGoModuleLevel).Return(false)
+	mockConfig.EXPECT().GetBool(flags.FlagOffline).Return(false)
+	mockConfig.EXPECT().GetBool(flags.FlagReproducible).Return(false)
+	mockConfig.EXPECT().GetString(flags.FlagSupplier).Return("")
+	mockConfig.EXPECT().GetString(flags.FlagAuthor).Return("")
+	mockConfig.EXPECT().GetString(flags.FlagManufacturer).Return("")
+	mockConfig.EXPECT().GetString(flags.FlagLifecycle).Return("")
+	mockConfig.EXPECT().GetString(flags.FlagOutputFile).Return("")
+	mockConfig.EXPECT().GetString(flags.FlagOutputDir).Return("")
+	mockConfig.EXPECT().GetBool(flags.FlagSplitProjects).Return(false)
+	mockConfig.EXPECT().GetBool(flags.FlagCheck).Return(false)
+	mockConfig.EXPECT().GetInt(flags.FlagThreshold).Return(flags.DefaultCheckThreshold)
+	mockConfig.EXPECT().GetBool(flags.FlagQuality).Return(false)
+	mockConfig.EXPECT().GetString(flags.FlagQualityFile).Return("")
 	mockConfig.EXPECT().GetStringWithError(configuration.ORGANIZATION).Return("", expectedErr)
 
 	mockICTX := mocks.NewMockInvocationContext(ctrl)
@@ -180,7 +786,7 @@ func TestSBOMWorkflow_MultipleDepGraphs(t *testing.T) {
 	}, nil)
 	mockICTX := mockInvocationContext(t, ctrl, mockSBOMService.URL, mockEngine)
 
-	results, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+	results, err := sbomcreate.SBOMWorkflowWithDI(mockICTX, []workflow.Data{}, &testGitInfoGetter{})
 
 	require.NoError(t, err)
 	assert.Len(t, results, 1)
@@ -211,7 +817,7 @@ func TestSBOMWorkflow_MergeSubject_DefaultNameToCurrentFolder(t *testing.T) {
 	mockICTX.GetConfiguration().Set("name", "")
 	mockICTX.GetConfiguration().Set("version", "")
 
-	_, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+	_, err := sbomcreate.SBOMWorkflowWithDI(mockICTX, []workflow.Data{}, &testGitInfoGetter{})
 	require.NoError(t, err)
 }
 
@@ -235,8 +841,105 @@ func TestSBOMWorkflow_MergeSubject(t *testing.T) {
 	mockICTX.GetConfiguration().Set("name", "myDepGraph")
 	mockICTX.GetConfiguration().Set("version", "")
 
-	_, err := sbomcreate.SBOMWorkflow(mockICTX, []workflow.Data{})
+	_, err := sbomcreate.SBOMWorkflowWithDI(mockICTX, []workflow.Data{}, &testGitInfoGetter{})
+	require.NoError(t, err)
+}
+
+func TestSBOMWorkflow_GitProvenance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockResponse := svcmocks.NewMockResponse("application/vnd.cyclonedx+json", []byte("{}"), http.StatusOK)
+	mockSBOMService := svcmocks.NewMockSBOMService(mockResponse, func(r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		defer r.Body.Close() //nolint:errcheck // Test cleanup, error can be safely ignored
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"depGraphs":[{},{}],"subject":{"name":"goof","version":"0.0.0"},`+
+			`"tools":[{"name":"test-app","vendor":"Snyk","version":"1.2.3"}],`+
+			`"metadata":{"vcs":{"url":"https://github.com/snyk/goof","commit":"4b825dc642cb6eb9a060e54bf8d69288fbee4904","branch":"main","dirty":true}}}`,
+			string(body))
+	})
+	defer mockSBOMService.Close()
+	mockEngine := newMockEngine(ctrl, []workflow.Data{newDepGraphData(t, []byte(`{}`)), newDepGraphData(t, []byte(`{}`))}, nil)
+	mockICTX := mockInvocationContext(t, ctrl, mockSBOMService.URL, mockEngine)
+	mockICTX.GetConfiguration().Set(configuration.INPUT_DIRECTORY, []string{"/src/goof"})
+	gitInfoGetter := &testGitInfoGetter{info: &cmd_exec.GitInfo{
+		RemoteURL: "https://github.com/snyk/goof",
+		Commit:    "4b825dc642cb6eb9a060e54bf8d69288fbee4904",
+		Branch:    "main",
+		Dirty:     true,
+	}}
+
+	_, err := sbomcreate.SBOMWorkflowWithDI(mockICTX, []workflow.Data{}, gitInfoGetter)
+
 	require.NoError(t, err)
+	assert.Equal(t, []string{"/src/goof"}, gitInfoGetter.dirs)
+}
+
+func TestSBOMWorkflow_GitProvenance_Offline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockICTX := mockInvocationContext(t, ctrl, "http://localhost:0", nil)
+	mockICTX.GetConfiguration().Set(flags.FlagOffline, true)
+	mockICTX.GetConfiguration().Set(flags.FlagFormat, "cyclonedx1.6+json")
+	mockICTX.GetConfiguration().Set("name", "")
+	gitInfoGetter := &testGitInfoGetter{info: &cmd_exec.GitInfo{
+		RemoteURL: "https://github.com/snyk/goof",
+		Commit:    "4b825dc642cb6eb9a060e54bf8d69288fbee4904",
+	}}
+
+	results, err := sbomcreate.SBOMWorkflowWithDI(mockICTX, []workflow.Data{}, gitInfoGetter)
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	sbomBytes, ok := results[0].GetPayload().([]byte)
+	require.True(t, ok)
+
+	var doc struct {
+		Metadata struct {
+			Component struct {
+				ExternalReferences []struct {
+					URL  string `json:"url"`
+					Type string `json:"type"`
+				} `json:"externalReferences"`
+				Properties []struct {
+					Name  string `json:"name"`
+					Value string `json:"value"`
+				} `json:"properties"`
+			} `json:"component"`
+		} `json:"metadata"`
+	}
+	require.NoError(t, json.Unmarshal(sbomBytes, &doc))
+	require.Len(t, doc.Metadata.Component.ExternalReferences, 1)
+	assert.Equal(t, "vcs", doc.Metadata.Component.ExternalReferences[0].Type)
+	assert.Equal(t, "https://github.com/snyk/goof", doc.Metadata.Component.ExternalReferences[0].URL)
+	assert.Contains(t, doc.Metadata.Component.Properties, struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}{Name: "snyk:git:commit", Value: "4b825dc642cb6eb9a060e54bf8d69288fbee4904"})
+}
+
+func TestSBOMWorkflow_GitProvenance_LookedUpOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockICTX := mockInvocationContext(t, ctrl, "", nil)
+	mockICTX.GetConfiguration().Set(flags.FlagOffline, true)
+	mockICTX.GetConfiguration().Set(flags.FlagFormat, "cyclonedx1.6+json,spdx2.3+json,spdx3.0+json")
+	mockICTX.GetConfiguration().Set(configuration.INPUT_DIRECTORY, []string{"/src/goof"})
+	gitInfoGetter := &testGitInfoGetter{info: &cmd_exec.GitInfo{Commit: "4b825dc642cb6eb9a060e54bf8d69288fbee4904"}}
+
+	results, err := sbomcreate.SBOMWorkflowWithDI(mockICTX, []workflow.Data{}, gitInfoGetter)
+
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	for _, r := range results {
+		payload, ok := r.GetPayload().([]byte)
+		require.True(t, ok)
+		assert.Contains(t, string(payload), "4b825dc642cb6eb9a060e54bf8d69288fbee4904")
+	}
+	assert.Equal(t, []string{"/src/goof"}, gitInfoGetter.dirs)
 }
 
 func mockInvocationContext(
@@ -280,6 +983,33 @@ func mockInvocationContext(
 	return ictx
 }
 
+type testGitInfoGetter struct {
+	info *cmd_exec.GitInfo
+	dirs []string
+}
+
+func (g *testGitInfoGetter) GetGitInfo(dir string) *cmd_exec.GitInfo {
+	g.dirs = append(g.dirs, dir)
+	return g.info
+}
+
+func captureStderr(t *testing.T, f func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+
+	f()
+	require.NoError(t, w.Close())
+
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(b)
+}
+
 func newMockEngine(ctrl *gomock.Controller, result []workflow.Data, err error) *mocks.MockEngine {
 	mockEngine := mocks.NewMockEngine(ctrl)
 
@@ -302,6 +1032,14 @@ func newDepGraphData(t *testing.T, bts []byte) workflow.Data {
 	)
 }
 
+func newDepGraphDataWithLocation(t *testing.T, bts []byte, location string) workflow.Data {
+	t.Helper()
+
+	d := newDepGraphData(t, bts)
+	d.SetContentLocation(location)
+	return d
+}
+
 func assertWorkflowExists(t *testing.T, e workflow.Engine, id *url.URL) {
 	t.Helper()
 
@@ -0,0 +1,70 @@
+package sbomcreate
+
+import (
+	"encoding/json"
+
+	"github.com/snyk/cli-extension-sbom/internal/depgraph"
+	"github.com/snyk/cli-extension-sbom/internal/service"
+)
+
+// project is the input for one SBOM document in `--split-projects` mode.
+type project struct {
+	// location is the content location of the project's depgraph, or the
+	// subject of its scan errors.
+	location   string
+	depGraphs  []json.RawMessage
+	scanErrors []service.ScanError
+	// subject is nil when the project's depgraph root describes the project.
+	subject *service.Subject
+}
+
+// splitProjects turns every depgraph into a project of its own. Scan errors
+// are attached to the project at the location they refer to; errors of
+// projects that could not be resolved at all make up projects of their own.
+func splitProjects(res *DepGraphResult) ([]*project, error) {
+	projects := make([]*project, 0, len(res.DepGraphBytes))
+	byLocation := make(map[string]*project, len(res.DepGraphBytes))
+	for i, depGraph := range res.DepGraphBytes {
+		p := &project{depGraphs: []json.RawMessage{depGraph}}
+		if i < len(res.Locations) {
+			p.location = res.Locations[i]
+		}
+		projects = append(projects, p)
+		if _, ok := byLocation[p.location]; !ok && p.location != "" {
+			byLocation[p.location] = p
+		}
+	}
+
+	for _, se := range res.ScanErrors {
+		p, ok := byLocation[se.Subject]
+		if !ok {
+			name := se.Subject
+			if name == "" {
+				name = res.Name
+			}
+			p = &project{location: se.Subject, subject: service.NewSubject(name, "")}
+			projects = append(projects, p)
+			if se.Subject != "" {
+				byLocation[se.Subject] = p
+			}
+		}
+		p.scanErrors = append(p.scanErrors, se)
+	}
+
+	// Scan errors are only carried by documents with a subject, so projects
+	// with both a depgraph and scan errors use their root package as subject.
+	for _, p := range projects {
+		if p.subject != nil || len(p.scanErrors) == 0 {
+			continue
+		}
+		dg, err := depgraph.Parse(p.depGraphs[0])
+		if err != nil {
+			return nil, err
+		}
+		if root := dg.RootPkg(); root != nil {
+			p.subject = service.NewSubject(root.Info.Name, root.Info.Version)
+		}
+	}
+
+	return projects, nil
+}
@@ -0,0 +1,164 @@
+package sbomdiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/snyk/go-application-framework/pkg/local_workflows/content_type"
+	"github.com/snyk/go-application-framework/pkg/local_workflows/json_schemas"
+	"github.com/snyk/go-application-framework/pkg/workflow"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/errors"
+	"github.com/snyk/cli-extension-sbom/internal/flags"
+	"github.com/snyk/cli-extension-sbom/internal/sbom"
+	view "github.com/snyk/cli-extension-sbom/internal/view/sbomdiff"
+)
+
+var (
+	WorkflowID     = workflow.NewWorkflowIdentifier("sbom.diff")
+	WorkflowDataID = workflow.NewTypeIdentifier(WorkflowID, "sbom.diff")
+)
+
+const MIMETypeJSON = "application/json"
+
+type (
+	JSONOutput struct {
+		From    string          `json:"from"`
+		To      string          `json:"to"`
+		Added   []JSONComponent `json:"added"`
+		Removed []JSONComponent `json:"removed"`
+		Changed []JSONChange    `json:"changed"`
+	}
+
+	JSONComponent struct {
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+		PURL    string `json:"purl,omitempty"`
+	}
+
+	JSONChange struct {
+		Name        string `json:"name"`
+		FromVersion string `json:"fromVersion"`
+		ToVersion   string `json:"toVersion"`
+		PURL        string `json:"purl,omitempty"`
+	}
+)
+
+func RegisterWorkflows(e workflow.Engine) error {
+	sbomFlagset := flags.GetSBOMDiffFlagSet()
+
+	c := workflow.ConfigurationOptionsFromFlagset(sbomFlagset)
+
+	if _, err := e.Register(WorkflowID, c, DiffWorkflow); err != nil {
+		return fmt.Errorf("error while registering %s workflow: %w", WorkflowID, err)
+	}
+
+	return nil
+}
+
+func DiffWorkflow(
+	ictx workflow.InvocationContext,
+	_ []workflow.Data,
+) ([]workflow.Data, error) {
+	config := ictx.GetConfiguration()
+	logger := ictx.GetEnhancedLogger()
+	filenames := config.GetStringSlice(flags.FlagFile)
+	errFactory := errors.NewErrorFactory(logger)
+
+	logger.Println("SBOM Diff workflow start")
+
+	if len(filenames) == 0 {
+		return nil, errFactory.NewMissingFilenameFlagError()
+	}
+	if len(filenames) != 2 {
+		return nil, errFactory.NewDiffFileCountError(len(filenames))
+	}
+	from, to := filenames[0], filenames[1]
+
+	fromDoc, err := sbom.ReadDocument(from, errFactory)
+	if err != nil {
+		return nil, err
+	}
+	toDoc, err := sbom.ReadDocument(to, errFactory)
+	if err != nil {
+		return nil, err
+	}
+
+	d := bom.DiffDocuments(fromDoc, toDoc)
+	logger.Printf("Diff: %d added, %d removed, %d changed\n", len(d.Added), len(d.Removed), len(d.Changed))
+
+	var out workflow.Data
+	if config.GetBool(flags.FlagJSON) {
+		b, err := json.Marshal(toJSONOutput(from, to, d))
+		if err != nil {
+			return nil, errFactory.NewRenderError(err)
+		}
+		out = workflow.NewData(WorkflowDataID, MIMETypeJSON, b)
+	} else {
+		var buf bytes.Buffer
+		if err := view.NewRenderer(&buf).RenderDiff(from, to, d); err != nil {
+			return nil, errFactory.NewRenderError(err)
+		}
+		out = workflow.NewData(WorkflowDataID, "text/plain", buf.Bytes())
+	}
+
+	if !config.GetBool(flags.FlagFailOnDiff) {
+		return []workflow.Data{out}, nil
+	}
+
+	summary, contentType, err := BuildDiffSummary(to, d)
+	if err != nil {
+		return nil, errFactory.NewRenderError(err)
+	}
+	return []workflow.Data{out, workflow.NewData(WorkflowDataID, contentType, summary)}, nil
+}
+
+// BuildDiffSummary reports each difference as an open finding, so that the
+// CLI exits with a non-zero exit code if the documents differ.
+func BuildDiffSummary(path string, d *bom.Diff) (data []byte, contentType string, err error) {
+	n := len(d.Added) + len(d.Removed) + len(d.Changed)
+	summary := json_schemas.TestSummary{
+		Type:      "sbom-diff",
+		Path:      path,
+		Artifacts: 1,
+		Results: []json_schemas.TestSummaryResult{
+			{
+				Severity: "low",
+				Total:    n,
+				Open:     n,
+			},
+		},
+	}
+	data, err = json.Marshal(summary)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, content_type.TEST_SUMMARY, nil
+}
+
+func toJSONOutput(from, to string, d *bom.Diff) *JSONOutput {
+	out := &JSONOutput{
+		From:    from,
+		To:      to,
+		Added:   make([]JSONComponent, 0, len(d.Added)),
+		Removed: make([]JSONComponent, 0, len(d.Removed)),
+		Changed: make([]JSONChange, 0, len(d.Changed)),
+	}
+	for _, c := range d.Added {
+		out.Added = append(out.Added, JSONComponent{Name: c.Name, Version: c.Version, PURL: c.PURL})
+	}
+	for _, c := range d.Removed {
+		out.Removed = append(out.Removed, JSONComponent{Name: c.Name, Version: c.Version, PURL: c.PURL})
+	}
+	for _, c := range d.Changed {
+		out.Changed = append(out.Changed, JSONChange{
+			Name:        c.To.Name,
+			FromVersion: c.From.Version,
+			ToVersion:   c.To.Version,
+			PURL:        c.To.PURL,
+		})
+	}
+	return out
+}
@@ -0,0 +1,147 @@
+package sbomdiff_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	snyk_errors "github.com/snyk/error-catalog-golang-public/snyk_errors"
+	"github.com/snyk/go-application-framework/pkg/configuration"
+	"github.com/snyk/go-application-framework/pkg/local_workflows/content_type"
+	"github.com/snyk/go-application-framework/pkg/mocks"
+	"github.com/snyk/go-application-framework/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/commands/sbomdiff"
+	"github.com/snyk/cli-extension-sbom/internal/flags"
+)
+
+func TestSBOMDiffWorkflow_NoFileFlag(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+
+	_, err := sbomdiff.DiffWorkflow(mockICTX, []workflow.Data{})
+
+	assert.ErrorContains(t, err, "Flag `--file` is required to execute this command.")
+}
+
+func TestSBOMDiffWorkflow_WrongFileCount(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+	mockICTX.GetConfiguration().Set(flags.FlagFile, []string{"testdata/old.cdx.json"})
+
+	_, err := sbomdiff.DiffWorkflow(mockICTX, []workflow.Data{})
+
+	var snykErr snyk_errors.Error
+	require.True(t, errors.As(err, &snykErr))
+	assert.Equal(t, "Flag `--file` must be set exactly twice, to the SBOM to compare from and the SBOM to compare to (got 1).", snykErr.Detail)
+}
+
+func TestSBOMDiffWorkflow_Text(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+	mockICTX.GetConfiguration().Set(flags.FlagFile, []string{"testdata/old.cdx.json", "testdata/new.spdx.json"})
+
+	result, err := sbomdiff.DiffWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "text/plain", result[0].GetContentType())
+	payload, ok := result[0].GetPayload().([]byte)
+	require.True(t, ok)
+	assert.Contains(t, string(payload), "+ lodash@4.17.21")
+	assert.Contains(t, string(payload), "- ws@1.0.0")
+	assert.Contains(t, string(payload), "~ express 4.4.0 → 4.5.0")
+}
+
+func TestSBOMDiffWorkflow_JSON(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+	mockICTX.GetConfiguration().Set(flags.FlagFile, []string{"testdata/old.cdx.json", "testdata/new.spdx.json"})
+	mockICTX.GetConfiguration().Set(flags.FlagJSON, true)
+
+	result, err := sbomdiff.DiffWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, sbomdiff.MIMETypeJSON, result[0].GetContentType())
+	payload, ok := result[0].GetPayload().([]byte)
+	require.True(t, ok)
+	assert.JSONEq(t, `{
+		"from": "testdata/old.cdx.json",
+		"to": "testdata/new.spdx.json",
+		"added": [{"name": "lodash", "version": "4.17.21", "purl": "pkg:npm/lodash@4.17.21"}],
+		"removed": [{"name": "ws", "version": "1.0.0", "purl": "pkg:npm/ws@1.0.0"}],
+		"changed": [{"name": "express", "fromVersion": "4.4.0", "toVersion": "4.5.0", "purl": "pkg:npm/express@4.5.0"}]
+	}`, string(payload))
+}
+
+func TestSBOMDiffWorkflow_XMLToJSON(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+	mockICTX.GetConfiguration().Set(flags.FlagFile, []string{"testdata/old.cdx.xml", "testdata/new.spdx.json"})
+	mockICTX.GetConfiguration().Set(flags.FlagJSON, true)
+
+	result, err := sbomdiff.DiffWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	payload, ok := result[0].GetPayload().([]byte)
+	require.True(t, ok)
+	assert.JSONEq(t, `{
+		"from": "testdata/old.cdx.xml",
+		"to": "testdata/new.spdx.json",
+		"added": [{"name": "lodash", "version": "4.17.21", "purl": "pkg:npm/lodash@4.17.21"}],
+		"removed": [{"name": "ws", "version": "1.0.0", "purl": "pkg:npm/ws@1.0.0"}],
+		"changed": [{"name": "express", "fromVersion": "4.4.0", "toVersion": "4.5.0", "purl": "pkg:npm/express@4.5.0"}]
+	}`, string(payload))
+}
+
+func TestSBOMDiffWorkflow_FailOnDiff(t *testing.T) {
+	tc := []struct {
+		name     string
+		files    []string
+		expected string
+	}{
+		{
+			name:     "documents differ",
+			files:    []string{"testdata/old.cdx.json", "testdata/new.spdx.json"},
+			expected: `{"results":[{"severity":"low","total":3,"open":3,"ignored":0}],"type":"sbom-diff","artifacts":1,"path":"testdata/new.spdx.json"}`,
+		},
+		{
+			name:     "documents are equal",
+			files:    []string{"testdata/old.cdx.json", "testdata/old.cdx.json"},
+			expected: `{"results":[{"severity":"low","total":0,"open":0,"ignored":0}],"type":"sbom-diff","artifacts":1,"path":"testdata/old.cdx.json"}`,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			mockICTX := mockInvocationContext(t)
+			mockICTX.GetConfiguration().Set(flags.FlagFile, tt.files)
+			mockICTX.GetConfiguration().Set(flags.FlagFailOnDiff, true)
+
+			result, err := sbomdiff.DiffWorkflow(mockICTX, []workflow.Data{})
+
+			require.NoError(t, err)
+			require.Len(t, result, 2)
+			assert.Equal(t, content_type.TEST_SUMMARY, result[1].GetContentType())
+			payload, ok := result[1].GetPayload().([]byte)
+			require.True(t, ok)
+			assert.JSONEq(t, tt.expected, string(payload))
+		})
+	}
+}
+
+// Helpers
+
+func mockInvocationContext(t *testing.T) workflow.InvocationContext {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	mockLogger := zerolog.New(io.Discard)
+
+	ictx := mocks.NewMockInvocationContext(ctrl)
+	ictx.EXPECT().GetConfiguration().Return(configuration.New()).AnyTimes()
+	ictx.EXPECT().GetEnhancedLogger().Return(&mockLogger).AnyTimes()
+
+	return ictx
+}
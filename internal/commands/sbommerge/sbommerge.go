@@ -0,0 +1,96 @@
+package sbommerge
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/snyk/go-application-framework/pkg/workflow"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/errors"
+	"github.com/snyk/cli-extension-sbom/internal/flags"
+	"github.com/snyk/cli-extension-sbom/internal/sbom"
+	"github.com/snyk/cli-extension-sbom/internal/service"
+)
+
+var (
+	WorkflowID     = workflow.NewWorkflowIdentifier("sbom.merge")
+	WorkflowDataID = workflow.NewTypeIdentifier(WorkflowID, "sbom")
+)
+
+func RegisterWorkflows(e workflow.Engine) error {
+	sbomFlagset := flags.GetSBOMMergeFlagSet()
+
+	c := workflow.ConfigurationOptionsFromFlagset(sbomFlagset)
+
+	if _, err := e.Register(WorkflowID, c, MergeWorkflow); err != nil {
+		return fmt.Errorf("error while registering %s workflow: %w", WorkflowID, err)
+	}
+
+	return nil
+}
+
+func MergeWorkflow(
+	ictx workflow.InvocationContext,
+	_ []workflow.Data,
+) ([]workflow.Data, error) {
+	config := ictx.GetConfiguration()
+	logger := ictx.GetEnhancedLogger()
+	filenames := config.GetStringSlice(flags.FlagFile)
+	name := config.GetString(flags.FlagName)
+	version := config.GetString(flags.FlagVersion)
+	errFactory := errors.NewErrorFactory(logger)
+
+	logger.Println("SBOM Merge workflow start")
+
+	if len(filenames) == 0 {
+		return nil, errFactory.NewMissingFilenameFlagError()
+	}
+
+	formats, err := service.ParseSBOMFormats(errFactory, config.GetString(flags.FlagFormat))
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, errFactory.IndeterminateWorkingDirectory(err)
+		}
+		name = filepath.Base(wd)
+	}
+	logger.Printf("Document subject: { Name: %q, Version: %q }\n", name, version)
+
+	docs := make([]*bom.Document, 0, len(filenames))
+	for _, filename := range filenames {
+		fileDocs, err := sbom.ReadDocuments(filename, errFactory)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, fileDocs...)
+	}
+
+	merged := bom.Merge(service.SubjectComponent(name, version), docs...)
+	merged.SerialNumber = "urn:uuid:" + uuid.NewString()
+	merged.Timestamp = time.Now().UTC()
+	ri := ictx.GetRuntimeInfo()
+	merged.Metadata.Tools = []*bom.Tool{{Vendor: "Snyk", Name: ri.GetName(), Version: ri.GetVersion()}}
+
+	logger.Printf("Merged %d documents into %d components\n", len(docs), len(merged.Components))
+
+	out := make([]workflow.Data, 0, len(formats))
+	for _, format := range formats {
+		res, err := service.EncodeDocument(merged, format)
+		if err != nil {
+			return nil, errFactory.NewFatalSBOMGenerationError(err)
+		}
+		d := workflow.NewData(WorkflowDataID, res.MIMEType, res.Doc)
+		d.SetContentLocation(format)
+		out = append(out, d)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,162 @@
+package sbommerge_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	snyk_errors "github.com/snyk/error-catalog-golang-public/snyk_errors"
+	"github.com/snyk/go-application-framework/pkg/configuration"
+	"github.com/snyk/go-application-framework/pkg/mocks"
+	"github.com/snyk/go-application-framework/pkg/runtimeinfo"
+	"github.com/snyk/go-application-framework/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/bom/cyclonedx"
+	"github.com/snyk/cli-extension-sbom/internal/bom/spdx"
+	"github.com/snyk/cli-extension-sbom/internal/commands/sbommerge"
+	"github.com/snyk/cli-extension-sbom/internal/flags"
+)
+
+func TestSBOMMergeWorkflow_NoFileFlag(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+
+	_, err := sbommerge.MergeWorkflow(mockICTX, []workflow.Data{})
+
+	assert.ErrorContains(t, err, "Flag `--file` is required to execute this command.")
+}
+
+func TestSBOMMergeWorkflow_InvalidFormat(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+	mockICTX.GetConfiguration().Set(flags.FlagFile, []string{"testdata/frontend.cdx.json"})
+	mockICTX.GetConfiguration().Set(flags.FlagFormat, "cyclonedx1.7+json")
+
+	_, err := sbommerge.MergeWorkflow(mockICTX, []workflow.Data{})
+
+	assert.ErrorContains(t, err, "The format provided (cyclonedx1.7+json) is not one of the available formats.")
+}
+
+func TestSBOMMergeWorkflow_Success(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+	mockICTX.GetConfiguration().Set(flags.FlagFile, []string{"testdata/frontend.cdx.json", "testdata/backend.spdx.json"})
+	mockICTX.GetConfiguration().Set(flags.FlagName, "product")
+	mockICTX.GetConfiguration().Set(flags.FlagVersion, "2.0.0")
+	mockICTX.GetConfiguration().Set(flags.FlagFormat, "cyclonedx1.6+json,spdx2.3+json")
+
+	result, err := sbommerge.MergeWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, cyclonedx.MIMETypeJSON, result[0].GetContentType())
+	assert.Equal(t, "cyclonedx1.6+json", result[0].GetContentLocation())
+	assert.Equal(t, spdx.MIMETypeJSON, result[1].GetContentType())
+	assert.Equal(t, "spdx2.3+json", result[1].GetContentLocation())
+
+	payload, ok := result[0].GetPayload().([]byte)
+	require.True(t, ok)
+	doc, err := cyclonedx.Decode(payload)
+	require.NoError(t, err)
+
+	assert.Equal(t, &bom.Component{BOMRef: "product@2.0.0", Type: bom.ComponentTypeApplication, Name: "product", Version: "2.0.0"},
+		doc.Metadata.Component)
+	assert.Equal(t, []*bom.Tool{{Vendor: "Snyk", Name: "snyk-cli", Version: "1.2.3"}}, doc.Metadata.Tools)
+	assert.Equal(t, []*bom.Component{
+		{BOMRef: "frontend@1.0.0", Type: bom.ComponentTypeApplication, Name: "frontend", Version: "1.0.0"},
+		{BOMRef: "express@4.4.0", Type: bom.ComponentTypeLibrary, Name: "express", Version: "4.4.0", PURL: "pkg:npm/express@4.4.0"},
+		{BOMRef: "util", Type: bom.ComponentTypeLibrary, Name: "util", Version: "1.0.0"},
+		{BOMRef: "SPDXRef-backend", Type: bom.ComponentTypeApplication, Name: "backend", Version: "3.1.0"},
+		{BOMRef: "util-2", Type: bom.ComponentTypeLibrary, Name: "util", Version: "2.0.0"},
+	}, doc.Components)
+	assert.Equal(t, []*bom.Dependency{
+		{Ref: "product@2.0.0", DependsOn: []string{"frontend@1.0.0", "SPDXRef-backend"}},
+		{Ref: "frontend@1.0.0", DependsOn: []string{"express@4.4.0", "util"}},
+		{Ref: "SPDXRef-backend", DependsOn: []string{"express@4.4.0", "util-2"}},
+	}, doc.Dependencies)
+}
+
+func TestSBOMMergeWorkflow_CompressedInput(t *testing.T) {
+	b, err := os.ReadFile("testdata/backend.spdx.json")
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err = zw.Write(b)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	compressed := filepath.Join(t.TempDir(), "backend.spdx.json.gz")
+	require.NoError(t, os.WriteFile(compressed, buf.Bytes(), 0o600))
+
+	mockICTX := mockInvocationContext(t)
+	mockICTX.GetConfiguration().Set(flags.FlagFile, []string{"testdata/frontend.cdx.json", compressed})
+	mockICTX.GetConfiguration().Set(flags.FlagName, "product")
+
+	result, err := sbommerge.MergeWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	payload, ok := result[0].GetPayload().([]byte)
+	require.True(t, ok)
+	doc, err := cyclonedx.Decode(payload)
+	require.NoError(t, err)
+	assert.NotNil(t, doc.Component("SPDXRef-backend"))
+}
+
+func TestSBOMMergeWorkflow_UnsupportedInputs(t *testing.T) {
+	tc := []struct {
+		file     string
+		expected string
+	}{
+		{
+			file:     "testdata/unknown.json",
+			expected: `The file "testdata/unknown.json" is neither a CycloneDX JSON or XML document nor an SPDX JSON document.`,
+		},
+		{
+			file: "testdata/spdx-3.0.json",
+			expected: `The file "testdata/spdx-3.0.json" is a SPDX 3.0 document, which is not supported by this command. ` +
+				"Supported are CycloneDX JSON and XML documents and SPDX 2.x JSON documents.",
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.file, func(t *testing.T) {
+			mockICTX := mockInvocationContext(t)
+			mockICTX.GetConfiguration().Set(flags.FlagFile, []string{"testdata/frontend.cdx.json", tt.file})
+
+			_, err := sbommerge.MergeWorkflow(mockICTX, []workflow.Data{})
+
+			var snykErr snyk_errors.Error
+			require.True(t, errors.As(err, &snykErr))
+			assert.Equal(t, tt.expected, snykErr.Detail)
+		})
+	}
+}
+
+// Helpers
+
+func mockInvocationContext(t *testing.T) workflow.InvocationContext {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	mockLogger := zerolog.New(io.Discard)
+
+	mockConfig := configuration.New()
+	mockConfig.Set(flags.FlagFormat, "cyclonedx1.6+json")
+
+	mockRuntimeInfo := runtimeinfo.New(
+		runtimeinfo.WithName("snyk-cli"),
+		runtimeinfo.WithVersion("1.2.3"))
+
+	ictx := mocks.NewMockInvocationContext(ctrl)
+	ictx.EXPECT().GetConfiguration().Return(mockConfig).AnyTimes()
+	ictx.EXPECT().GetEnhancedLogger().Return(&mockLogger).AnyTimes()
+	ictx.EXPECT().GetRuntimeInfo().Return(mockRuntimeInfo).AnyTimes()
+
+	return ictx
+}
@@ -0,0 +1,190 @@
+package sbommonitor
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/snyk/cli-extension-sbom/internal/errors"
+	"github.com/snyk/cli-extension-sbom/internal/policy"
+	"github.com/snyk/cli-extension-sbom/internal/sbom"
+	"github.com/snyk/cli-extension-sbom/internal/snykclient"
+)
+
+// documentResult is the outcome of monitoring an SBOM document: the warnings
+// converting it, and the outcome of monitoring each of its projects, or the
+// error reading, checking or converting it.
+type documentResult struct {
+	name     string
+	warnings []*snykclient.ConversionWarning
+	projects []projectResult
+	err      error
+}
+
+// projectResult is the outcome of monitoring a dep-graph of a document.
+type projectResult struct {
+	scan     *snykclient.ScanResult
+	response *snykclient.MonitorDependenciesResponse
+	err      error
+}
+
+// monitor converts SBOM documents to dep-graphs and monitors them, running
+// at most as many requests to the Snyk API at once as it has workers.
+type monitor struct {
+	client        *snykclient.SnykClient
+	remoteRepoURL string
+	targetRef     string
+	workers       chan struct{}
+	logger        *zerolog.Logger
+	errFactory    *errors.ErrorFactory
+}
+
+func newMonitor(
+	client *snykclient.SnykClient,
+	remoteRepoURL, targetRef string,
+	workers int,
+	logger *zerolog.Logger,
+	errFactory *errors.ErrorFactory,
+) *monitor {
+	return &monitor{
+		client:        client,
+		remoteRepoURL: remoteRepoURL,
+		targetRef:     targetRef,
+		workers:       make(chan struct{}, workers),
+		logger:        logger,
+		errFactory:    errFactory,
+	}
+}
+
+// monitorFiles reads the SBOM documents of the files and monitors them
+// concurrently, applying the policy found for each file. At most as many files
+// are processed at once as the monitor has workers, each read as its turn
+// comes, so that only their documents are held in memory. A file failing to
+// be read, or a document failing to be checked or converted, does not keep the
+// others from being monitored, its error is recorded on its result. The
+// results are returned in the order of the files and of their documents,
+// regardless of the order requests complete in.
+func (m *monitor) monitorFiles(filenames []string, policyPath string) []*documentResult {
+	results := make([][]*documentResult, len(filenames))
+	files := make(chan struct{}, cap(m.workers))
+	var wg sync.WaitGroup
+	for i, name := range filenames {
+		files <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-files }()
+			results[i] = m.monitorFile(name, policy.LoadPolicyFile(policyPath, name))
+		})
+	}
+	wg.Wait()
+	return slices.Concat(results...)
+}
+
+// monitorFile reads the documents of a file and monitors them one after the
+// other. Documents are normalised to JSON, which is what the SBOM conversion
+// API accepts, and compressed files and archives are extracted. JSON documents
+// too large to be read into memory are checked and uploaded as they are read
+// from disk.
+func (m *monitor) monitorFile(name string, plc []byte) []*documentResult {
+	files, err := sbom.StreamSBOMFiles(name, sbom.FileSizeLimit, m.errFactory)
+	if err != nil {
+		return []*documentResult{{name: name, err: err}}
+	}
+
+	results := make([]*documentResult, len(files))
+	for i, f := range files {
+		results[i] = m.monitorDocument(f, plc)
+	}
+	return results
+}
+
+// monitorDocument checks a document against the schema of its format,
+// converts it to dep-graphs and monitors them concurrently.
+func (m *monitor) monitorDocument(f *sbom.SBOMFile, plc []byte) *documentResult {
+	res := &documentResult{name: f.Name}
+	if res.err = sbom.CheckFileSchema(f, m.errFactory); res.err != nil {
+		return res
+	}
+
+	var scans []*snykclient.ScanResult
+	var warnings []*snykclient.ConversionWarning
+	m.run(func() {
+		scans, warnings, res.err = m.convert(f)
+	})
+	res.warnings = append(droppedFieldWarnings(f), warnings...)
+	if res.err != nil {
+		return res
+	}
+
+	res.projects = make([]projectResult, len(scans))
+	var wg sync.WaitGroup
+	for i, s := range scans {
+		m.start(&wg, func() {
+			res.projects[i] = m.monitorScan(s, plc)
+		})
+	}
+	wg.Wait()
+	return res
+}
+
+// droppedFieldWarnings reports the fields of a CycloneDX XML document that were
+// lost normalising it to JSON, and are therefore not monitored, as conversion
+// warnings.
+func droppedFieldWarnings(f *sbom.SBOMFile) []*snykclient.ConversionWarning {
+	warnings := make([]*snykclient.ConversionWarning, 0, len(f.Dropped))
+	for _, w := range f.Dropped {
+		warnings = append(warnings, &snykclient.ConversionWarning{Type: "UnsupportedField", Msg: w.Msg})
+	}
+	return warnings
+}
+
+// convert converts a document to dep-graphs.
+func (m *monitor) convert(f *sbom.SBOMFile) ([]*snykclient.ScanResult, []*snykclient.ConversionWarning, error) {
+	m.logger.Println("Converting SBOM document:", f.Name)
+
+	scans, warnings, err := m.client.SBOMConvert(context.Background(), m.errFactory, f.Open, m.remoteRepoURL)
+	if err != nil {
+		// Snyk Client returns err from error factory
+		return nil, nil, err
+	}
+
+	m.logger.Println("Successfully converted SBOM")
+
+	if len(scans) < 1 {
+		return nil, warnings, m.errFactory.NewNoSupportedProjectsError(concatConversionWarnings(warnings))
+	}
+
+	return scans, warnings, nil
+}
+
+func (m *monitor) monitorScan(s *snykclient.ScanResult, plc []byte) projectResult {
+	m.logger.Printf("Monitoring dep-graph (%s)\n", s.Identity.Type)
+
+	mres, merr := m.client.MonitorDependencies(context.Background(), m.errFactory,
+		s.WithSnykPolicy(plc).
+			WithTargetReference(m.targetRef).
+			WithTargetRemoteURL(m.remoteRepoURL))
+	if merr != nil {
+		m.logger.Println("Failed to monitor dep-graph", merr)
+	}
+
+	return projectResult{scan: s, response: mres, err: merr}
+}
+
+// start runs a request in a goroutine of the group once a worker is
+// available, so that requests are started in order.
+func (m *monitor) start(wg *sync.WaitGroup, request func()) {
+	m.workers <- struct{}{}
+	wg.Go(func() {
+		defer func() { <-m.workers }()
+		request()
+	})
+}
+
+// run runs a request once a worker is available.
+func (m *monitor) run(request func()) {
+	m.workers <- struct{}{}
+	defer func() { <-m.workers }()
+	request()
+}
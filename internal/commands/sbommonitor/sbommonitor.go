@@ -2,9 +2,8 @@ package sbommonitor
 
 import (
 	"bytes"
-	"context"
+	"encoding/json"
 	"fmt"
-	"os"
 
 	"github.com/snyk/go-application-framework/pkg/configuration"
 	"github.com/snyk/go-application-framework/pkg/local_workflows/config_utils"
@@ -13,7 +12,7 @@ import (
 	"github.com/snyk/cli-extension-sbom/internal/cmd_exec"
 	"github.com/snyk/cli-extension-sbom/internal/errors"
 	"github.com/snyk/cli-extension-sbom/internal/flags"
-	"github.com/snyk/cli-extension-sbom/internal/policy"
+	"github.com/snyk/cli-extension-sbom/internal/sbom"
 	"github.com/snyk/cli-extension-sbom/internal/snykclient"
 	view "github.com/snyk/cli-extension-sbom/internal/view/sbommonitor"
 )
@@ -92,53 +91,96 @@ func MonitorWorkflowWithDI(
 
 	logger.Println("Remote repo URL:", remoteRepoURL)
 
-	fd, err := os.Open(filename) //nolint:gosec // G304 - filename is user-provided input, intentional
+	filenames, err := sbom.ExpandFiles(filename, errFactory)
 	if err != nil {
-		return nil, errFactory.NewFailedToOpenFileError(err)
+		return nil, err
 	}
-	defer fd.Close() //nolint:errcheck // File already read successfully, and OS will cleanup on exit
 
 	c := snykclient.NewSnykClient(
 		ictx.GetNetworkAccess().GetHttpClient(),
 		config.GetString(configuration.API_URL),
-		orgID)
+		orgID).
+		WithUploadSizeLimit(snykclient.UploadSizeLimit(config.GetInt(flags.FlagMaxUploadSize)))
+
+	workers := config.GetInt(flags.FlagConcurrency)
+	if workers <= 0 {
+		workers = flags.DefaultConcurrency
+	}
+
+	// Directories, glob patterns and archives holding several documents are
+	// monitored as a batch.
+	results := newMonitor(c, remoteRepoURL, targetRef, workers, logger, errFactory).monitorFiles(filenames, policyPath)
+	if len(results) == 1 && results[0].err != nil {
+		return nil, results[0].err
+	}
+
+	return output(config, results, errFactory)
+}
 
-	scans, warnings, err := c.SBOMConvert(context.Background(), errFactory, fd, remoteRepoURL)
+// output returns the rendered results, or the JSON summary of them if the
+// `--json` flag is set. With the `--fail-on-partial` flag, it is followed by a
+// test summary the CLI's exit code is taken from, so that it exits with a
+// non-zero exit code if any project failed to be monitored.
+func output(config configuration.Configuration, results []*documentResult, errFactory *errors.ErrorFactory) ([]workflow.Data, error) {
+	summary := summarize(results)
+	data, err := outputData(config, summary, results, errFactory)
 	if err != nil {
-		// Snyk Client returns err from error factory
 		return nil, err
 	}
+	if !config.GetBool(flags.FlagFailOnPartial) {
+		return data, nil
+	}
 
-	logger.Println("Successfully converted SBOM")
-
-	if len(scans) < 1 {
-		return nil, errFactory.NewNoSupportedProjectsError(concatConversionWarnings(warnings))
+	b, contentType, err := summary.testSummary(config.GetString(flags.FlagFile), len(results))
+	if err != nil {
+		return nil, errFactory.NewRenderError(err)
 	}
+	return append(data, workflow.NewData(WorkflowDataID, contentType, b)), nil
+}
 
-	plc := policy.LoadPolicyFile(policyPath, filename)
+// outputData returns the JSON summary if the `--json` flag is set, or the
+// rendered results otherwise.
+func outputData(config configuration.Configuration, summary *Summary, results []*documentResult, errFactory *errors.ErrorFactory) ([]workflow.Data, error) {
+	if config.GetBool(flags.FlagJSON) {
+		b, err := json.Marshal(summary)
+		if err != nil {
+			return nil, errFactory.NewRenderError(err)
+		}
+		return []workflow.Data{workflow.NewData(WorkflowDataID, MIMETypeJSON, b)}, nil
+	}
 
 	var buf bytes.Buffer
-	r := view.NewRenderer(&buf)
-
-	if err := r.RenderWarnings(warnings); err != nil {
+	if err := render(view.NewRenderer(&buf), results); err != nil {
 		return nil, errFactory.NewRenderError(err)
 	}
 
-	for _, s := range scans {
-		logger.Printf("Monitoring dep-graph (%s)\n", s.Identity.Type)
+	return []workflow.Data{workflow.NewData(WorkflowDataID, "text/plain", buf.Bytes())}, nil
+}
 
-		mres, merr := c.MonitorDependencies(context.Background(), errFactory,
-			s.WithSnykPolicy(plc).
-				WithTargetReference(targetRef).
-				WithTargetRemoteURL(remoteRepoURL))
-		if merr != nil {
-			logger.Println("Failed to monitor dep-graph", merr)
+// render renders the conversion warnings and the monitored projects of each
+// document, or the error converting it, in the order of the documents, under a
+// heading naming the document when there are several.
+func render(r *view.Renderer, results []*documentResult) error {
+	for _, res := range results {
+		if len(results) > 1 {
+			if err := r.RenderDocument(res.name); err != nil {
+				return err
+			}
 		}
-
-		if err := r.RenderMonitor(mres, merr); err != nil {
-			return nil, errFactory.NewRenderError(err)
+		if res.err != nil {
+			if err := r.RenderConversionError(res.err); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := r.RenderWarnings(res.warnings); err != nil {
+			return err
+		}
+		for _, p := range res.projects {
+			if err := r.RenderMonitor(p.response, p.err); err != nil {
+				return err
+			}
 		}
 	}
-
-	return []workflow.Data{workflow.NewData(WorkflowDataID, "text/plain", buf.Bytes())}, nil
+	return nil
 }
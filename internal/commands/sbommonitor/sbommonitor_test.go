@@ -2,16 +2,26 @@ package sbommonitor_test
 
 import (
 	"bytes"
+	"compress/gzip"
 	_ "embed"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/bradleyjkemp/cupaloy/v2"
 	"github.com/golang/mock/gomock"
 	"github.com/rs/zerolog"
 	"github.com/snyk/go-application-framework/pkg/configuration"
+	"github.com/snyk/go-application-framework/pkg/local_workflows/content_type"
+	"github.com/snyk/go-application-framework/pkg/local_workflows/json_schemas"
 	"github.com/snyk/go-application-framework/pkg/mocks"
 	"github.com/snyk/go-application-framework/pkg/networking"
 	"github.com/snyk/go-application-framework/pkg/runtimeinfo"
@@ -121,6 +131,9 @@ func TestSBOMMonitorWorkflow_Success_WithWarnings(t *testing.T) {
 	mockICTX.GetConfiguration().Set(flags.FlagRemoteRepoURL, remoteGitURL)
 	mockICTX.GetConfiguration().Set("file", "testdata/bom.json")
 
+	// The projects are monitored one at a time, in the order of the responses.
+	mockICTX.GetConfiguration().Set(flags.FlagConcurrency, 1)
+
 	data, err := sbommonitor.MonitorWorkflow(mockICTX, []workflow.Data{})
 
 	require.NoError(t, err)
@@ -224,6 +237,472 @@ func TestSBOMMonitorWorkflow_NoTestableProjects(t *testing.T) {
 		"Please check that your SBOM contains supported ecosystems and dependency relationships.")
 }
 
+func TestSBOMMonitorWorkflow_CycloneDXXML(t *testing.T) {
+	responses := []svcmocks.MockResponse{
+		svcmocks.NewMockResponse("application/vnd.api+json", testResultMockResponse, http.StatusOK),
+		svcmocks.NewMockResponse("application/vnd.api+json", monitorDependenciesResultMockResponse, http.StatusOK),
+	}
+
+	var convertRequestBody string
+	mockSBOMService := svcmocks.NewMockSBOMServiceMultiResponse(responses, func(r *http.Request) {
+		if !strings.Contains(r.RequestURI, "monitor-dependencies") {
+			body, err := processRequest(r)
+			require.NoError(t, err)
+			// The document is sent gzip-compressed.
+			zr, err := gzip.NewReader(strings.NewReader(body))
+			require.NoError(t, err)
+			b, err := io.ReadAll(zr)
+			require.NoError(t, err)
+			convertRequestBody = string(b)
+		}
+	})
+	defer mockSBOMService.Close()
+
+	mockICTX := createMockICTXWithURL(t, mockSBOMService.URL)
+	mockICTX.GetConfiguration().Set("experimental", true)
+	mockICTX.GetConfiguration().Set(sbommonitor.FeatureFlagSBOMMonitor, true)
+	mockICTX.GetConfiguration().Set(flags.FlagRemoteRepoURL, "https://example.com/flag-url")
+	mockICTX.GetConfiguration().Set("file", "testdata/bom.xml")
+
+	_, err := sbommonitor.MonitorWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	assert.Contains(t, convertRequestBody, `"bomFormat":"CycloneDX"`, "the XML document should be converted to JSON")
+	assert.Contains(t, convertRequestBody, `"specVersion":"1.5"`)
+}
+
+func TestSBOMMonitorWorkflow_Gzip(t *testing.T) {
+	responses := []svcmocks.MockResponse{
+		svcmocks.NewMockResponse("application/vnd.api+json", testResultMockResponse, http.StatusOK),
+		svcmocks.NewMockResponse("application/vnd.api+json", monitorDependenciesResultMockResponse, http.StatusOK),
+	}
+
+	mockSBOMService := svcmocks.NewMockSBOMServiceMultiResponse(responses, func(_ *http.Request) {})
+	defer mockSBOMService.Close()
+
+	b, err := os.ReadFile("testdata/bom.json")
+	require.NoError(t, err)
+	filename := filepath.Join(t.TempDir(), "bom.json.gz")
+	f, err := os.Create(filename)
+	require.NoError(t, err)
+	zw := gzip.NewWriter(f)
+	_, err = zw.Write(b)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+
+	mockICTX := createMockICTXWithURL(t, mockSBOMService.URL)
+	mockICTX.GetConfiguration().Set("experimental", true)
+	mockICTX.GetConfiguration().Set(sbommonitor.FeatureFlagSBOMMonitor, true)
+	mockICTX.GetConfiguration().Set(flags.FlagRemoteRepoURL, "https://example.com/flag-url")
+	mockICTX.GetConfiguration().Set("file", filename)
+
+	data, err := sbommonitor.MonitorWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	assert.Len(t, data, 1)
+}
+
+func TestSBOMMonitorWorkflow_InvalidSBOM(t *testing.T) {
+	mockSBOMService := svcmocks.NewMockSBOMServiceMultiResponse(nil, func(r *http.Request) {
+		t.Errorf("unexpected request to %s", r.RequestURI)
+	})
+	defer mockSBOMService.Close()
+
+	mockICTX := createMockICTXWithURL(t, mockSBOMService.URL)
+	mockICTX.GetConfiguration().Set("experimental", true)
+	mockICTX.GetConfiguration().Set(sbommonitor.FeatureFlagSBOMMonitor, true)
+	mockICTX.GetConfiguration().Set(flags.FlagRemoteRepoURL, "https://example.com/flag-url")
+	mockICTX.GetConfiguration().Set("file", "testdata/invalid-bom.json")
+
+	_, err := sbommonitor.MonitorWorkflow(mockICTX, []workflow.Data{})
+
+	assert.ErrorContains(t, err, "Invalid flag option")
+}
+
+func TestSBOMMonitorWorkflow_Directory(t *testing.T) {
+	// The first document is converted last, its results are rendered first.
+	mockSBOMService := newMockSBOMService(t, func(name string) bool {
+		if name == "alice" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		return true
+	})
+	defer mockSBOMService.Close()
+
+	dir := t.TempDir()
+	copyTestFile(t, "testdata/bom.json", filepath.Join(dir, "a.json"))
+	copyTestFile(t, "testdata/bom.xml", filepath.Join(dir, "b.xml"))
+
+	mockICTX := createMockICTXWithURL(t, mockSBOMService.URL)
+	mockICTX.GetConfiguration().Set("experimental", true)
+	mockICTX.GetConfiguration().Set(sbommonitor.FeatureFlagSBOMMonitor, true)
+	mockICTX.GetConfiguration().Set(flags.FlagRemoteRepoURL, "https://example.com/flag-url")
+	mockICTX.GetConfiguration().Set("file", dir)
+
+	data, err := sbommonitor.MonitorWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, data, 1)
+	out := strings.ReplaceAll(string(data[0].GetPayload().([]byte)), dir, "<dir>")
+	assert.Less(t, strings.Index(out, "alice"), strings.Index(out, "bob"))
+	snapshotter.SnapshotT(t, out)
+}
+
+func TestSBOMMonitorWorkflow_Directory_LimitsConcurrentRequests(t *testing.T) {
+	var mu sync.Mutex
+	var inFlight, maxInFlight int
+	mockSBOMService := newMockSBOMService(t, func(string) bool {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return true
+	})
+	defer mockSBOMService.Close()
+
+	dir := t.TempDir()
+	for _, name := range []string{"a.json", "b.json", "c.json", "d.json"} {
+		copyTestFile(t, "testdata/bom.json", filepath.Join(dir, name))
+	}
+
+	mockICTX := createMockICTXWithURL(t, mockSBOMService.URL)
+	mockICTX.GetConfiguration().Set("experimental", true)
+	mockICTX.GetConfiguration().Set(sbommonitor.FeatureFlagSBOMMonitor, true)
+	mockICTX.GetConfiguration().Set(flags.FlagRemoteRepoURL, "https://example.com/flag-url")
+	mockICTX.GetConfiguration().Set("file", dir)
+	mockICTX.GetConfiguration().Set(flags.FlagConcurrency, 2)
+
+	_, err := sbommonitor.MonitorWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, maxInFlight)
+}
+
+// newMockSBOMService returns a server converting CycloneDX 1.4 documents to a
+// dep-graph named "alice", documents mentioning "no-supported-projects" to
+// none, and others to one named "bob", and monitoring them as projects of the
+// same name. It serves requests concurrently, calling
+// handle with the name of the dep-graph of each, and fails to monitor the
+// dep-graphs handle returns false for.
+func newMockSBOMService(t *testing.T, handle func(name string) bool) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		if strings.Contains(r.RequestURI, "monitor-dependencies") {
+			name := "bob"
+			if strings.Contains(string(body), `"name":"alice"`) {
+				name = "alice"
+			}
+			if !handle(name) {
+				w.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprintf(w, `{"message":"%s cannot be monitored"}`, name)
+				return
+			}
+			fmt.Fprintf(w, `{"ok":true,"org":"my-org","id":"%[1]s-id","isMonitored":true,"uri":"https://app.snyk.io/%[1]s","projectName":"%[1]s"}`, name)
+			return
+		}
+
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if !assert.NoError(t, err) {
+			return
+		}
+		doc, err := io.ReadAll(zr)
+		if !assert.NoError(t, err) {
+			return
+		}
+		if strings.Contains(string(doc), "no-supported-projects") {
+			fmt.Fprint(w, `{"scanResults":[],"warnings":[{"type":"NoComponents","msg":"This is a warning"}]}`)
+			return
+		}
+		name := "bob"
+		if strings.Contains(string(doc), `"specVersion": "1.4"`) {
+			name = "alice"
+		}
+		handle(name)
+		fmt.Fprintf(w, `{"scanResults":[{"name":"%[1]s","policy":"","target":{"name":"%[1]s-target"},"identity":{"type":"npm"}}]}`, name)
+	}))
+}
+
+func copyTestFile(t *testing.T, src, dst string) {
+	t.Helper()
+
+	b, err := os.ReadFile(src)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(dst, b, 0o600))
+}
+
+func TestSBOMMonitorWorkflow_Directory_Summary(t *testing.T) {
+	mockSBOMService := newMockSBOMService(t, func(name string) bool {
+		return name != "bob"
+	})
+	defer mockSBOMService.Close()
+
+	dir := t.TempDir()
+	copyTestFile(t, "testdata/bom.json", filepath.Join(dir, "a.json"))
+	copyTestFile(t, "testdata/bom.xml", filepath.Join(dir, "b.xml"))
+
+	mockICTX := createMockICTXWithURL(t, mockSBOMService.URL)
+	mockICTX.GetConfiguration().Set("experimental", true)
+	mockICTX.GetConfiguration().Set(sbommonitor.FeatureFlagSBOMMonitor, true)
+	mockICTX.GetConfiguration().Set(flags.FlagRemoteRepoURL, "https://example.com/flag-url")
+	mockICTX.GetConfiguration().Set("file", dir)
+	mockICTX.GetConfiguration().Set(flags.FlagJSON, true)
+
+	data, err := sbommonitor.MonitorWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, data, 1)
+	assert.Equal(t, sbommonitor.MIMETypeJSON, data[0].GetContentType())
+	var summary sbommonitor.Summary
+	require.NoError(t, json.Unmarshal(data[0].GetPayload().([]byte), &summary))
+	require.Len(t, summary.Projects, 2)
+	assert.False(t, summary.OK)
+	assert.Equal(t, 1, summary.Monitored)
+	assert.Equal(t, 1, summary.Failed)
+	assert.Equal(t, sbommonitor.ProjectSummary{
+		Document:    filepath.Join(dir, "a.json"),
+		Name:        "alice",
+		Type:        "npm",
+		ID:          "alice-id",
+		URI:         "https://app.snyk.io/alice",
+		ProjectName: "alice",
+		Org:         "my-org",
+	}, summary.Projects[0])
+	assert.Equal(t, filepath.Join(dir, "b.xml"), summary.Projects[1].Document)
+	assert.Equal(t, "bob", summary.Projects[1].Name)
+	assert.Contains(t, summary.Projects[1].Error, "bob cannot be monitored")
+}
+
+func TestSBOMMonitorWorkflow_Directory_ConversionError(t *testing.T) {
+	mockSBOMService := newMockSBOMService(t, func(string) bool { return true })
+	defer mockSBOMService.Close()
+
+	dir := t.TempDir()
+	copyTestFile(t, "testdata/bom.json", filepath.Join(dir, "a.json"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.json"), []byte(`{"bomFormat":"CycloneDX","specVersion":"1.5","version":1,`+
+		`"metadata":{"component":{"type":"application","name":"no-supported-projects"}}}`), 0o600))
+	copyTestFile(t, "testdata/bom.xml", filepath.Join(dir, "c.xml"))
+
+	mockICTX := createMockICTXWithURL(t, mockSBOMService.URL)
+	mockICTX.GetConfiguration().Set("experimental", true)
+	mockICTX.GetConfiguration().Set(sbommonitor.FeatureFlagSBOMMonitor, true)
+	mockICTX.GetConfiguration().Set(flags.FlagRemoteRepoURL, "https://example.com/flag-url")
+	mockICTX.GetConfiguration().Set("file", dir)
+
+	data, err := sbommonitor.MonitorWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, data, 1)
+	out := strings.ReplaceAll(string(data[0].GetPayload().([]byte)), dir, "<dir>")
+	snapshotter.SnapshotT(t, out)
+
+	mockICTX.GetConfiguration().Set(flags.FlagJSON, true)
+	data, err = sbommonitor.MonitorWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, data, 1)
+	var summary sbommonitor.Summary
+	require.NoError(t, json.Unmarshal(data[0].GetPayload().([]byte), &summary))
+	assert.False(t, summary.OK)
+	assert.Equal(t, 2, summary.Monitored)
+	require.Len(t, summary.ConversionErrors, 1)
+	assert.Equal(t, filepath.Join(dir, "b.json"), summary.ConversionErrors[0].Document)
+	assert.Contains(t, summary.ConversionErrors[0].Error, "No supported projects were found")
+}
+
+func TestSBOMMonitorWorkflow_Directory_InvalidDocuments(t *testing.T) {
+	mockSBOMService := newMockSBOMService(t, func(string) bool { return true })
+	defer mockSBOMService.Close()
+
+	dir := t.TempDir()
+	copyTestFile(t, "testdata/bom.json", filepath.Join(dir, "a.json"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.json"), []byte(`{"bomFormat":`), 0o600))
+	copyTestFile(t, "testdata/invalid-bom.json", filepath.Join(dir, "c.json"))
+	copyTestFile(t, "testdata/bom.xml", filepath.Join(dir, "d.xml"))
+
+	mockICTX := createMockICTXWithURL(t, mockSBOMService.URL)
+	mockICTX.GetConfiguration().Set("experimental", true)
+	mockICTX.GetConfiguration().Set(sbommonitor.FeatureFlagSBOMMonitor, true)
+	mockICTX.GetConfiguration().Set(flags.FlagRemoteRepoURL, "https://example.com/flag-url")
+	mockICTX.GetConfiguration().Set("file", dir)
+	mockICTX.GetConfiguration().Set(flags.FlagJSON, true)
+
+	data, err := sbommonitor.MonitorWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, data, 1)
+	var summary sbommonitor.Summary
+	require.NoError(t, json.Unmarshal(data[0].GetPayload().([]byte), &summary))
+	assert.False(t, summary.OK)
+	assert.Equal(t, 2, summary.Monitored)
+	require.Len(t, summary.Projects, 2)
+	assert.Equal(t, filepath.Join(dir, "a.json"), summary.Projects[0].Document)
+	assert.Equal(t, filepath.Join(dir, "d.xml"), summary.Projects[1].Document)
+	require.Len(t, summary.ConversionErrors, 2)
+	assert.Equal(t, filepath.Join(dir, "b.json"), summary.ConversionErrors[0].Document)
+	assert.Equal(t, filepath.Join(dir, "c.json"), summary.ConversionErrors[1].Document)
+}
+
+func TestSBOMMonitorWorkflow_XMLDroppedFields(t *testing.T) {
+	mockSBOMService := newMockSBOMService(t, func(string) bool { return true })
+	defer mockSBOMService.Close()
+
+	b, err := os.ReadFile("testdata/bom.xml")
+	require.NoError(t, err)
+	filename := filepath.Join(t.TempDir(), "bom.xml")
+	services := []byte("<services><service><name>api</name></service></services></bom>")
+	require.NoError(t, os.WriteFile(filename, bytes.Replace(b, []byte("</bom>"), services, 1), 0o600))
+
+	mockICTX := createMockICTXWithURL(t, mockSBOMService.URL)
+	mockICTX.GetConfiguration().Set("experimental", true)
+	mockICTX.GetConfiguration().Set(sbommonitor.FeatureFlagSBOMMonitor, true)
+	mockICTX.GetConfiguration().Set(flags.FlagRemoteRepoURL, "https://example.com/flag-url")
+	mockICTX.GetConfiguration().Set("file", filename)
+	mockICTX.GetConfiguration().Set(flags.FlagJSON, true)
+
+	data, err := sbommonitor.MonitorWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, data, 1)
+	var summary sbommonitor.Summary
+	require.NoError(t, json.Unmarshal(data[0].GetPayload().([]byte), &summary))
+	assert.Equal(t, []sbommonitor.WarningSummary{{
+		Document: filename,
+		Type:     "UnsupportedField",
+		Msg:      "field services is not supported and was dropped",
+	}}, summary.Warnings)
+}
+
+func TestSBOMMonitorWorkflow_FailOnPartial(t *testing.T) {
+	mockSBOMService := newMockSBOMService(t, func(name string) bool {
+		return name != "bob"
+	})
+	defer mockSBOMService.Close()
+
+	dir := t.TempDir()
+	copyTestFile(t, "testdata/bom.json", filepath.Join(dir, "a.json"))
+	copyTestFile(t, "testdata/bom.xml", filepath.Join(dir, "b.xml"))
+
+	mockICTX := createMockICTXWithURL(t, mockSBOMService.URL)
+	mockICTX.GetConfiguration().Set("experimental", true)
+	mockICTX.GetConfiguration().Set(sbommonitor.FeatureFlagSBOMMonitor, true)
+	mockICTX.GetConfiguration().Set(flags.FlagRemoteRepoURL, "https://example.com/flag-url")
+	mockICTX.GetConfiguration().Set("file", dir)
+	mockICTX.GetConfiguration().Set(flags.FlagFailOnPartial, true)
+
+	data, err := sbommonitor.MonitorWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, data, 2)
+	assert.Contains(t, string(data[0].GetPayload().([]byte)), "Monitoring 'alice'...")
+	assert.Contains(t, string(data[0].GetPayload().([]byte)), "bob cannot be monitored")
+	assert.Equal(t, json_schemas.TestSummaryResult{Severity: "critical", Total: 1, Open: 1}, testSummaryResult(t, data[1]))
+}
+
+func TestSBOMMonitorWorkflow_FailOnPartial_JSON(t *testing.T) {
+	mockSBOMService := newMockSBOMService(t, func(name string) bool {
+		return name != "bob"
+	})
+	defer mockSBOMService.Close()
+
+	dir := t.TempDir()
+	copyTestFile(t, "testdata/bom.json", filepath.Join(dir, "a.json"))
+	copyTestFile(t, "testdata/bom.xml", filepath.Join(dir, "b.xml"))
+
+	mockICTX := createMockICTXWithURL(t, mockSBOMService.URL)
+	mockICTX.GetConfiguration().Set("experimental", true)
+	mockICTX.GetConfiguration().Set(sbommonitor.FeatureFlagSBOMMonitor, true)
+	mockICTX.GetConfiguration().Set(flags.FlagRemoteRepoURL, "https://example.com/flag-url")
+	mockICTX.GetConfiguration().Set("file", dir)
+	mockICTX.GetConfiguration().Set(flags.FlagFailOnPartial, true)
+	mockICTX.GetConfiguration().Set(flags.FlagJSON, true)
+
+	data, err := sbommonitor.MonitorWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, data, 2)
+	assert.Equal(t, sbommonitor.MIMETypeJSON, data[0].GetContentType())
+	var summary sbommonitor.Summary
+	require.NoError(t, json.Unmarshal(data[0].GetPayload().([]byte), &summary))
+	assert.Equal(t, 1, summary.Monitored)
+	assert.Equal(t, 1, summary.Failed)
+	assert.Equal(t, json_schemas.TestSummaryResult{Severity: "critical", Total: 1, Open: 1}, testSummaryResult(t, data[1]))
+}
+
+func TestSBOMMonitorWorkflow_FailOnPartial_AllMonitored(t *testing.T) {
+	mockSBOMService := newMockSBOMService(t, func(string) bool { return true })
+	defer mockSBOMService.Close()
+
+	mockICTX := createMockICTXWithURL(t, mockSBOMService.URL)
+	mockICTX.GetConfiguration().Set("experimental", true)
+	mockICTX.GetConfiguration().Set(sbommonitor.FeatureFlagSBOMMonitor, true)
+	mockICTX.GetConfiguration().Set(flags.FlagRemoteRepoURL, "https://example.com/flag-url")
+	mockICTX.GetConfiguration().Set("file", "testdata/bom.json")
+	mockICTX.GetConfiguration().Set(flags.FlagFailOnPartial, true)
+
+	data, err := sbommonitor.MonitorWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, data, 2)
+	assert.Equal(t, json_schemas.TestSummaryResult{Severity: "critical"}, testSummaryResult(t, data[1]))
+}
+
+// testSummaryResult returns the single result of the test summary the data
+// holds.
+func testSummaryResult(t *testing.T, d workflow.Data) json_schemas.TestSummaryResult {
+	t.Helper()
+
+	require.Equal(t, content_type.TEST_SUMMARY, d.GetContentType())
+	b, ok := d.GetPayload().([]byte)
+	require.True(t, ok)
+	var summary json_schemas.TestSummary
+	require.NoError(t, json.Unmarshal(b, &summary))
+	require.Len(t, summary.Results, 1)
+	return summary.Results[0]
+}
+
+func TestSBOMMonitorWorkflow_JSON(t *testing.T) {
+	responses := []svcmocks.MockResponse{
+		svcmocks.NewMockResponse("application/vnd.api+json", testResultMockResponseWithWarnings, http.StatusOK),
+		svcmocks.NewMockResponse("application/vnd.api+json", monitorDependenciesResultMockResponse, http.StatusOK),
+		svcmocks.NewMockResponse("application/json", []byte(`{"message":"project limit reached"}`), http.StatusForbidden),
+	}
+
+	mockSBOMService := svcmocks.NewMockSBOMServiceMultiResponse(responses, func(_ *http.Request) {})
+	defer mockSBOMService.Close()
+
+	mockICTX := createMockICTXWithURL(t, mockSBOMService.URL)
+	mockICTX.GetConfiguration().Set("experimental", true)
+	mockICTX.GetConfiguration().Set(sbommonitor.FeatureFlagSBOMMonitor, true)
+	mockICTX.GetConfiguration().Set(flags.FlagRemoteRepoURL, "https://example.com/flag-url")
+	mockICTX.GetConfiguration().Set("file", "testdata/bom.json")
+	mockICTX.GetConfiguration().Set(flags.FlagJSON, true)
+	// The projects are monitored one at a time, in the order of the responses.
+	mockICTX.GetConfiguration().Set(flags.FlagConcurrency, 1)
+
+	data, err := sbommonitor.MonitorWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, data, 1)
+	assert.Equal(t, sbommonitor.MIMETypeJSON, data[0].GetContentType())
+	var out bytes.Buffer
+	require.NoError(t, json.Indent(&out, data[0].GetPayload().([]byte), "", "  "))
+	snapshotter.SnapshotT(t, out.String())
+}
+
 func processRequest(r *http.Request) (string, error) {
 	bodyBytes, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -0,0 +1,115 @@
+package sbommonitor
+
+import (
+	"encoding/json"
+
+	"github.com/snyk/go-application-framework/pkg/local_workflows/content_type"
+	"github.com/snyk/go-application-framework/pkg/local_workflows/json_schemas"
+)
+
+const MIMETypeJSON = "application/json"
+
+type (
+	// Summary is the JSON summary of the projects monitored, or failing to
+	// be, from a batch of SBOM documents, and of the warnings and errors
+	// converting the documents to dep-graphs.
+	Summary struct {
+		OK               bool                     `json:"ok"`
+		Monitored        int                      `json:"monitored"`
+		Failed           int                      `json:"failed"`
+		Warnings         []WarningSummary         `json:"warnings"`
+		ConversionErrors []ConversionErrorSummary `json:"conversionErrors"`
+		Projects         []ProjectSummary         `json:"projects"`
+	}
+
+	// WarningSummary is a warning converting a document to dep-graphs.
+	WarningSummary struct {
+		Document string `json:"document"`
+		Type     string `json:"type"`
+		Msg      string `json:"msg"`
+		BOMRef   string `json:"bomRef,omitempty"`
+	}
+
+	// ConversionErrorSummary is the error reading, checking or converting a
+	// document to dep-graphs, none of which are monitored.
+	ConversionErrorSummary struct {
+		Document string `json:"document"`
+		Error    string `json:"error"`
+	}
+
+	// ProjectSummary is the outcome of monitoring a dep-graph of a document,
+	// the project it is monitored as or the error monitoring it.
+	ProjectSummary struct {
+		Document    string `json:"document"`
+		Name        string `json:"name"`
+		Type        string `json:"type"`
+		ID          string `json:"id,omitempty"`
+		URI         string `json:"uri,omitempty"`
+		ProjectName string `json:"projectName,omitempty"`
+		Org         string `json:"org,omitempty"`
+		Error       string `json:"error,omitempty"`
+	}
+)
+
+// summarize collects the conversion warnings and the outcome of monitoring
+// each project of the documents, in the order of the documents.
+func summarize(results []*documentResult) *Summary {
+	summary := &Summary{Warnings: []WarningSummary{}, ConversionErrors: []ConversionErrorSummary{}, Projects: []ProjectSummary{}}
+	for _, res := range results {
+		for _, w := range res.warnings {
+			summary.Warnings = append(summary.Warnings, WarningSummary{
+				Document: res.name,
+				Type:     w.Type,
+				Msg:      w.Msg,
+				BOMRef:   w.BOMRef,
+			})
+		}
+		if res.err != nil {
+			summary.ConversionErrors = append(summary.ConversionErrors, ConversionErrorSummary{Document: res.name, Error: res.err.Error()})
+		}
+		for _, p := range res.projects {
+			project := ProjectSummary{
+				Document: res.name,
+				Name:     p.scan.Name,
+				Type:     p.scan.Identity.Type,
+			}
+			if p.err != nil {
+				project.Error = p.err.Error()
+				summary.Failed++
+			} else {
+				project.ID = p.response.ID
+				project.URI = p.response.URI
+				project.ProjectName = p.response.ProjectName
+				project.Org = p.response.Org
+				summary.Monitored++
+			}
+			summary.Projects = append(summary.Projects, project)
+		}
+	}
+	summary.OK = summary.Failed == 0 && len(summary.ConversionErrors) == 0
+	return summary
+}
+
+// testSummary reports each project that failed to be monitored, and each
+// document that failed to convert, as an open finding of the documents at
+// path, so that the CLI exits with a non-zero exit code if there are any.
+func (s *Summary) testSummary(path string, documents int) (data []byte, contentType string, err error) {
+	failed := s.Failed + len(s.ConversionErrors)
+	summary := json_schemas.TestSummary{
+		Type:      "sbom-monitor",
+		Path:      path,
+		Artifacts: documents,
+		Results: []json_schemas.TestSummaryResult{
+			{
+				Severity: "critical",
+				Total:    failed,
+				Open:     failed,
+			},
+		},
+	}
+	data, err = json.Marshal(summary)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, content_type.TEST_SUMMARY, nil
+}
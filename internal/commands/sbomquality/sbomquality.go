@@ -0,0 +1,72 @@
+package sbomquality
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/snyk/go-application-framework/pkg/workflow"
+
+	"github.com/snyk/cli-extension-sbom/internal/errors"
+	"github.com/snyk/cli-extension-sbom/internal/flags"
+	"github.com/snyk/cli-extension-sbom/internal/sbom"
+	view "github.com/snyk/cli-extension-sbom/internal/view/sbomquality"
+)
+
+var (
+	WorkflowID     = workflow.NewWorkflowIdentifier("sbom.quality")
+	WorkflowDataID = workflow.NewTypeIdentifier(WorkflowID, "sbom.quality")
+)
+
+const MIMETypeJSON = "application/json"
+
+func RegisterWorkflows(e workflow.Engine) error {
+	sbomFlagset := flags.GetSBOMQualityFlagSet()
+
+	c := workflow.ConfigurationOptionsFromFlagset(sbomFlagset)
+
+	if _, err := e.Register(WorkflowID, c, QualityWorkflow); err != nil {
+		return fmt.Errorf("error while registering %s workflow: %w", WorkflowID, err)
+	}
+
+	return nil
+}
+
+// QualityWorkflow scores the quality of an SBOM document, reporting the
+// score of each category as text or JSON.
+func QualityWorkflow(
+	ictx workflow.InvocationContext,
+	_ []workflow.Data,
+) ([]workflow.Data, error) {
+	config := ictx.GetConfiguration()
+	logger := ictx.GetEnhancedLogger()
+	filename := config.GetString(flags.FlagFile)
+	errFactory := errors.NewErrorFactory(logger)
+
+	logger.Println("SBOM Quality workflow start")
+
+	if filename == "" {
+		return nil, errFactory.NewMissingFilenameFlagError()
+	}
+
+	report, err := sbom.ReadQualityReport(filename, errFactory)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Printf("Scored %s: quality score %d\n", filename, report.Score)
+
+	if config.GetBool(flags.FlagJSON) {
+		b, err := json.Marshal(report)
+		if err != nil {
+			return nil, errFactory.NewRenderError(err)
+		}
+		return []workflow.Data{workflow.NewData(WorkflowDataID, MIMETypeJSON, b)}, nil
+	}
+
+	var buf bytes.Buffer
+	if err := view.NewRenderer(&buf).RenderReport(report); err != nil {
+		return nil, errFactory.NewRenderError(err)
+	}
+	return []workflow.Data{workflow.NewData(WorkflowDataID, "text/plain", buf.Bytes())}, nil
+}
@@ -0,0 +1,97 @@
+package sbomquality_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	snyk_errors "github.com/snyk/error-catalog-golang-public/snyk_errors"
+	"github.com/snyk/go-application-framework/pkg/configuration"
+	"github.com/snyk/go-application-framework/pkg/mocks"
+	"github.com/snyk/go-application-framework/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/commands/sbomquality"
+	"github.com/snyk/cli-extension-sbom/internal/flags"
+)
+
+func TestSBOMQualityWorkflow_NoFileFlag(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+
+	_, err := sbomquality.QualityWorkflow(mockICTX, []workflow.Data{})
+
+	assert.ErrorContains(t, err, "Flag `--file` is required to execute this command.")
+}
+
+func TestSBOMQualityWorkflow_Text(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+	mockICTX.GetConfiguration().Set(flags.FlagFile, "testdata/bom.cdx.json")
+
+	result, err := sbomquality.QualityWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "text/plain", result[0].GetContentType())
+	payload, ok := result[0].GetPayload().([]byte)
+	require.True(t, ok)
+	assert.Contains(t, string(payload), "SBOM quality of testdata/bom.cdx.json (CycloneDX 1.5)")
+	assert.Contains(t, string(payload), "Hash coverage                     33   1/3 components")
+	assert.Contains(t, string(payload), "Quality score: 66")
+}
+
+func TestSBOMQualityWorkflow_JSON(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+	mockICTX.GetConfiguration().Set(flags.FlagFile, "testdata/bom.cdx.json")
+	mockICTX.GetConfiguration().Set(flags.FlagJSON, true)
+
+	result, err := sbomquality.QualityWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, sbomquality.MIMETypeJSON, result[0].GetContentType())
+	payload, ok := result[0].GetPayload().([]byte)
+	require.True(t, ok)
+	assert.JSONEq(t, `{
+		"name": "testdata/bom.cdx.json",
+		"format": {"standard": "CycloneDX", "specVersion": "1.5"},
+		"score": 66,
+		"categories": [
+			{"name": "identifier coverage", "score": 66, "present": 2, "total": 3},
+			{"name": "license coverage", "score": 66, "present": 2, "total": 3},
+			{"name": "hash coverage", "score": 33, "present": 1, "total": 3},
+			{"name": "dependency completeness", "score": 66, "present": 2, "total": 3},
+			{"name": "versions present", "score": 66, "present": 2, "total": 3},
+			{"name": "spec compliance", "score": 100, "present": 1, "total": 1}
+		],
+		"violations": 0
+	}`, string(payload))
+}
+
+func TestSBOMQualityWorkflow_UnknownFormat(t *testing.T) {
+	mockICTX := mockInvocationContext(t)
+	mockICTX.GetConfiguration().Set(flags.FlagFile, "testdata/unknown.json")
+
+	_, err := sbomquality.QualityWorkflow(mockICTX, []workflow.Data{})
+
+	var snykErr snyk_errors.Error
+	require.True(t, errors.As(err, &snykErr))
+	assert.Equal(t, `The file "testdata/unknown.json" is neither a CycloneDX JSON or XML document nor an SPDX JSON document.`, snykErr.Detail)
+}
+
+// Helpers
+
+func mockInvocationContext(t *testing.T) workflow.InvocationContext {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	mockLogger := zerolog.New(io.Discard)
+
+	ictx := mocks.NewMockInvocationContext(ctrl)
+	ictx.EXPECT().GetConfiguration().Return(configuration.New()).AnyTimes()
+	ictx.EXPECT().GetEnhancedLogger().Return(&mockLogger).AnyTimes()
+
+	return ictx
+}
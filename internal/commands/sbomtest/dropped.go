@@ -0,0 +1,22 @@
+package sbomtest
+
+import (
+	"os"
+
+	"github.com/snyk/cli-extension-sbom/internal/errors"
+	"github.com/snyk/cli-extension-sbom/internal/sbom"
+	view "github.com/snyk/cli-extension-sbom/internal/view/sbomconvert"
+)
+
+// renderDroppedFields renders the fields of the CycloneDX XML documents that
+// were lost normalising them to JSON, and are therefore not tested. They are
+// rendered to stderr, as stdout carries the test result.
+func renderDroppedFields(files []*sbom.SBOMFile, errFactory *errors.ErrorFactory) error {
+	r := view.NewRenderer(os.Stderr)
+	for _, f := range files {
+		if err := r.RenderDroppedFields(f.Name, f.Dropped); err != nil {
+			return errFactory.NewRenderError(err)
+		}
+	}
+	return nil
+}
@@ -1,19 +1,28 @@
 package sbomtest
 
 import (
+	stderr "errors"
 	"fmt"
+	"io"
+	"os"
+	"sync"
 
+	"github.com/rs/zerolog"
 	"github.com/snyk/go-application-framework/pkg/configuration"
 	"github.com/snyk/go-application-framework/pkg/local_workflows/config_utils"
 	"github.com/snyk/go-application-framework/pkg/workflow"
 
 	"github.com/snyk/cli-extension-sbom/internal/errors"
 	"github.com/snyk/cli-extension-sbom/internal/flags"
+	"github.com/snyk/cli-extension-sbom/internal/policy"
 	"github.com/snyk/cli-extension-sbom/internal/sbom"
+	"github.com/snyk/cli-extension-sbom/internal/snykclient"
+	view "github.com/snyk/cli-extension-sbom/internal/view/sbomquality"
 )
 
 var (
 	WorkflowID            = workflow.NewWorkflowIdentifier("sbom.test")
+	WorkflowDataID        = workflow.NewTypeIdentifier(WorkflowID, "sbom.test")
 	OsFlowsTestWorkflowID = workflow.NewWorkflowIdentifier("test")
 )
 
@@ -60,9 +69,15 @@ func TestWorkflow(
 
 	logger.Println("Target SBOM document:", filename)
 
-	if _, err := sbom.ReadSBOMFile(filename, errFactory); err != nil {
+	// Directories, glob patterns and archives holding several documents are
+	// tested as a batch.
+	docs, err := readSBOMs(filename, errFactory)
+	if err != nil {
 		return nil, err
 	}
+	if len(docs) == 1 && docs[0].err != nil {
+		return nil, docs[0].err
+	}
 
 	// `--report` is the successor to the (removed) `sbom monitor` command;
 	// keep it behind the same rollout FF so that we don't widen access
@@ -77,8 +92,231 @@ func TestWorkflow(
 		}
 	}
 
+	files := readFiles(docs)
+	if err := renderDroppedFields(files, errFactory); err != nil {
+		return nil, err
+	}
+	if err := reportQuality(config, files, logger, errFactory); err != nil {
+		return nil, err
+	}
+
+	testFiles(engine, config, docs, logger, errFactory)
+	if len(docs) == 1 {
+		return docs[0].results, docs[0].err
+	}
+
+	return aggregateResults(docs, filename, logger), nil
+}
+
+// document is a document of the tested batch, along with the outcome of
+// testing it. Documents that could not be read or failed the schema check
+// are not tested, and only carry the error.
+type document struct {
+	name    string
+	file    *sbom.SBOMFile
+	results []workflow.Data
+	err     error
+}
+
+// readSBOMs reads the documents of the SBOM files the `--file` flag value
+// designates, checking they are SBOM documents of a known format. JSON
+// documents too large to be read into memory are streamed from disk. The error
+// reading a file or checking a document is recorded on its document, so that
+// the other documents of the batch are still tested.
+func readSBOMs(filename string, errFactory *errors.ErrorFactory) ([]*document, error) {
+	filenames, err := sbom.ExpandFiles(filename, errFactory)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []*document
+	for _, name := range filenames {
+		files, err := sbom.StreamSBOMFiles(name, sbom.FileSizeLimit, errFactory)
+		if err != nil {
+			docs = append(docs, &document{name: name, err: err})
+			continue
+		}
+		for _, f := range files {
+			docs = append(docs, &document{name: f.Name, file: f, err: sbom.CheckFileSchema(f, errFactory)})
+		}
+	}
+
+	return docs, nil
+}
+
+// readFiles returns the files of the documents that were read.
+func readFiles(docs []*document) []*sbom.SBOMFile {
+	files := make([]*sbom.SBOMFile, 0, len(docs))
+	for _, d := range docs {
+		if d.err == nil {
+			files = append(files, d.file)
+		}
+	}
+	return files
+}
+
+// testFiles tests the documents that were read concurrently, starting them in
+// order and running at most as many tests at once as the `--concurrency` flag
+// allows. The results or the error of each test are recorded on its document.
+func testFiles(
+	engine workflow.Engine,
+	config configuration.Configuration,
+	docs []*document,
+	logger *zerolog.Logger,
+	errFactory *errors.ErrorFactory,
+) {
+	concurrency := config.GetInt(flags.FlagConcurrency)
+	if concurrency <= 0 {
+		concurrency = flags.DefaultConcurrency
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, d := range docs {
+		if d.err != nil {
+			continue
+		}
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			d.results, d.err = testFile(engine, config, d.file, logger, errFactory)
+		})
+	}
+	wg.Wait()
+}
+
+// testFile delegates the test of a document to the os-flows test workflow.
+func testFile(
+	engine workflow.Engine,
+	config configuration.Configuration,
+	f *sbom.SBOMFile,
+	logger *zerolog.Logger,
+	errFactory *errors.ErrorFactory,
+) ([]workflow.Data, error) {
+	if err := checkUploadSize(f, snykclient.UploadSizeLimit(config.GetInt(flags.FlagMaxUploadSize)), errFactory); err != nil {
+		return nil, err
+	}
+
+	sbomFile, err := jsonSBOMFile(f, logger, errFactory)
+	if err != nil {
+		return nil, err
+	}
+	if sbomFile != f.Name {
+		defer os.Remove(sbomFile) //nolint:errcheck // Best effort clean-up.
+	}
+
 	osFlowsTestConfig := config.Clone()
-	osFlowsTestConfig.Set(flags.FlagSBOM, filename)
+	osFlowsTestConfig.Set(flags.FlagSBOM, sbomFile)
+	if f.InMemory {
+		forwardPolicyPath(osFlowsTestConfig, f.Path)
+	}
 
 	return engine.InvokeWithConfig(OsFlowsTestWorkflowID, osFlowsTestConfig)
 }
+
+// checkUploadSize checks that the gzip-compressed document fits the upload
+// size limit, as the os-flows test uploads it compressed. Documents fitting
+// the limit uncompressed fit it compressed too and are not measured, others
+// are compressed as they are read, without being kept.
+func checkUploadSize(f *sbom.SBOMFile, limit int64, errFactory *errors.ErrorFactory) error {
+	size := int64(len(f.Content))
+	if f.Streamed {
+		info, err := os.Stat(f.Path)
+		if err != nil {
+			return errFactory.NewFailedToReadFileError(err)
+		}
+		size = info.Size()
+	}
+	if size <= limit {
+		return nil
+	}
+
+	r, err := f.Open()
+	if err != nil {
+		return errFactory.NewFailedToOpenFileError(err)
+	}
+	body := snykclient.NewGzipBody(r, limit)
+	defer body.Close() //nolint:errcheck // Pipe reader, always returns nil
+
+	_, err = io.Copy(io.Discard, body)
+	switch {
+	case stderr.Is(err, snykclient.ErrUploadSizeExceedsLimit):
+		return errFactory.NewUploadSizeExceedsLimitError(limit)
+	case err != nil:
+		return errFactory.NewFailedToReadFileError(err)
+	}
+	return nil
+}
+
+// forwardPolicyPath sets the policy path to that of the policy file applying
+// to the given SBOM file, if there is one. The os-flows test looks for it next
+// to the tested file, which is a temporary one for documents held in memory.
+func forwardPolicyPath(config configuration.Configuration, filename string) {
+	policyPath := policy.FilePath(config.GetString(flags.FlagPolicyPath), filename)
+	if _, err := os.Stat(policyPath); err == nil {
+		config.Set(flags.FlagPolicyPath, policyPath)
+	}
+}
+
+// reportQuality scores the quality of the tested documents, rendering the
+// reports to stderr (stdout carries the test result) and writing them as
+// JSON to the quality file, as requested by the flags.
+func reportQuality(config configuration.Configuration, files []*sbom.SBOMFile, logger *zerolog.Logger, errFactory *errors.ErrorFactory) error {
+	render := config.GetBool(flags.FlagQuality)
+	file := config.GetString(flags.FlagQualityFile)
+	if !render && file == "" {
+		return nil
+	}
+
+	reports := make([]*sbom.QualityReport, 0, len(files))
+	for _, f := range files {
+		if f.Streamed {
+			logger.Printf("Not scoring %s: too large to be read into memory\n", f.Name)
+			continue
+		}
+		report, err := sbom.Quality(f.Name, f.Content)
+		if err != nil {
+			logger.Printf("Not scoring %s: %v\n", f.Name, err)
+			continue
+		}
+		reports = append(reports, report)
+	}
+
+	if render {
+		r := view.NewRenderer(os.Stderr)
+		for _, report := range reports {
+			if err := r.RenderReport(report); err != nil {
+				return errFactory.NewRenderError(err)
+			}
+		}
+	}
+	if file != "" {
+		if err := sbom.WriteQualityReports(file, reports); err != nil {
+			return errFactory.NewFailedToWriteOutputError(err, file)
+		}
+	}
+	return nil
+}
+
+// jsonSBOMFile returns the name of a file holding the JSON encoding of the
+// tested document. Documents of other encodings were converted to JSON while
+// reading them, and those extracted from an archive are not found on disk,
+// so they are written to a temporary file the caller removes.
+func jsonSBOMFile(f *sbom.SBOMFile, logger *zerolog.Logger, errFactory *errors.ErrorFactory) (string, error) {
+	if f.Encoding == sbom.EncodingJSON && !f.InMemory {
+		return f.Name, nil
+	}
+	logger.Printf("Writing %s document %s as JSON to a temporary file\n", f.Encoding, f.Name)
+
+	tmp, err := os.CreateTemp("", "sbom-*.json")
+	if err != nil {
+		return "", errFactory.NewFailedToWriteOutputError(err, os.TempDir())
+	}
+	defer tmp.Close() //nolint:errcheck // Written file is checked below.
+
+	if _, err := tmp.Write(f.Content); err != nil {
+		os.Remove(tmp.Name()) //nolint:errcheck // Best effort clean-up.
+		return "", errFactory.NewFailedToWriteOutputError(err, tmp.Name())
+	}
+	return tmp.Name(), nil
+}
@@ -1,14 +1,24 @@
 package sbomtest_test
 
 import (
+	"archive/zip"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/rs/zerolog"
 	snyk_errors "github.com/snyk/error-catalog-golang-public/snyk_errors"
 	"github.com/snyk/go-application-framework/pkg/configuration"
+	"github.com/snyk/go-application-framework/pkg/local_workflows/content_type"
+	"github.com/snyk/go-application-framework/pkg/local_workflows/json_schemas"
 	"github.com/snyk/go-application-framework/pkg/mocks"
 	"github.com/snyk/go-application-framework/pkg/networking"
 	"github.com/snyk/go-application-framework/pkg/runtimeinfo"
@@ -18,6 +28,7 @@ import (
 
 	"github.com/snyk/cli-extension-sbom/internal/commands/sbomtest"
 	"github.com/snyk/cli-extension-sbom/internal/flags"
+	"github.com/snyk/cli-extension-sbom/internal/sbom"
 )
 
 func TestSBOMTestWorkflow_NoFileFlag(t *testing.T) {
@@ -48,6 +59,26 @@ func TestSBOMTestWorkflow_InvalidFilePath(t *testing.T) {
 	assert.Equal(t, `The given filepath "missing-file.txt" does not exist.`, snykErr.Detail)
 }
 
+func TestSBOMTestWorkflow_InvalidSBOM(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEngine := mocks.NewMockEngine(ctrl)
+
+	mockICTX := mockInvocationContext(t, ctrl, mockEngine)
+	mockICTX.GetConfiguration().Set("file", "testdata/invalid-bom.json")
+
+	mockEngine.EXPECT().InvokeWithConfig(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := sbomtest.TestWorkflow(mockICTX, []workflow.Data{})
+
+	var snykErr snyk_errors.Error
+	require.True(t, errors.As(err, &snykErr))
+	assert.Equal(t, "The file provided by the `--file` flag is not a valid CycloneDX 1.5 document:\n"+
+		"/components/0: missing property 'name'\n"+
+		"/components/0/type: value must be one of 'application', 'framework', 'library', 'container', "+
+		"'operating-system', 'device', 'firmware', 'file', 'platform', 'device-driver', 'machine-learning-model', 'data'", snykErr.Detail)
+}
+
 func TestSBOMTestWorkflow_DelegatesToOSF(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -88,6 +119,359 @@ func TestSBOMTestWorkflow_PassesConfigToOSF(t *testing.T) {
 	require.NotNil(t, result)
 }
 
+func TestSBOMTestWorkflow_QualityFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEngine := mocks.NewMockEngine(ctrl)
+
+	qualityFile := filepath.Join(t.TempDir(), "quality.json")
+	mockICTX := mockInvocationContext(t, ctrl, mockEngine)
+	mockICTX.GetConfiguration().Set("file", "testdata/bom.json")
+	mockICTX.GetConfiguration().Set(flags.FlagQualityFile, qualityFile)
+
+	mockEngine.EXPECT().InvokeWithConfig(sbomtest.OsFlowsTestWorkflowID, gomock.Any()).Return([]workflow.Data{}, nil).Times(1)
+
+	_, err := sbomtest.TestWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	b, err := os.ReadFile(qualityFile)
+	require.NoError(t, err)
+	var reports []struct {
+		Name  string `json:"name"`
+		Score int    `json:"score"`
+	}
+	require.NoError(t, json.Unmarshal(b, &reports))
+	require.Len(t, reports, 1)
+	assert.Equal(t, "testdata/bom.json", reports[0].Name)
+}
+
+func TestSBOMTestWorkflow_TagValue_DelegatesConvertedJSONToOSF(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEngine := mocks.NewMockEngine(ctrl)
+
+	mockICTX := mockInvocationContext(t, ctrl, mockEngine)
+	mockICTX.GetConfiguration().Set("file", "testdata/bom.spdx")
+
+	var forwardedFile string
+	var forwarded []byte
+	mockEngine.EXPECT().
+		InvokeWithConfig(sbomtest.OsFlowsTestWorkflowID, gomock.Any()).
+		DoAndReturn(func(_ workflow.Identifier, cfg configuration.Configuration) ([]workflow.Data, error) {
+			forwardedFile = cfg.GetString(flags.FlagSBOM)
+			var err error
+			forwarded, err = os.ReadFile(forwardedFile)
+			return []workflow.Data{}, err
+		}).
+		Times(1)
+
+	_, err := sbomtest.TestWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	assert.Equal(t, ".json", filepath.Ext(forwardedFile))
+	var doc struct {
+		SPDXVersion string `json:"spdxVersion"`
+		Packages    []struct {
+			Name string `json:"name"`
+		} `json:"packages"`
+	}
+	require.NoError(t, json.Unmarshal(forwarded, &doc))
+	assert.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+	assert.Len(t, doc.Packages, 2)
+	assert.NoFileExists(t, forwardedFile, "the converted document should be removed once tested")
+}
+
+func TestSBOMTestWorkflow_Archive_DelegatesEachDocumentToOSF(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEngine := mocks.NewMockEngine(ctrl)
+
+	archive := filepath.Join(t.TempDir(), "sboms.zip")
+	writeZip(t, archive, "testdata/bom.json", "testdata/bom.spdx")
+
+	mockICTX := mockInvocationContext(t, ctrl, mockEngine)
+	mockICTX.GetConfiguration().Set("file", archive)
+	mockICTX.GetConfiguration().Set(flags.FlagConcurrency, 1)
+
+	var forwarded []string
+	mockEngine.EXPECT().
+		InvokeWithConfig(sbomtest.OsFlowsTestWorkflowID, gomock.Any()).
+		DoAndReturn(func(_ workflow.Identifier, cfg configuration.Configuration) ([]workflow.Data, error) {
+			b, err := os.ReadFile(cfg.GetString(flags.FlagSBOM))
+			forwarded = append(forwarded, string(b))
+			return []workflow.Data{workflow.NewData(sbomtest.OsFlowsTestWorkflowID, "text/plain", b)}, err
+		}).
+		Times(2)
+
+	result, err := sbomtest.TestWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+	assert.Equal(t, filepath.Join(archive, "bom.json"), result[0].GetContentLocation())
+	assert.Equal(t, filepath.Join(archive, "bom.spdx"), result[1].GetContentLocation())
+	assert.Equal(t, content_type.TEST_SUMMARY, result[2].GetContentType())
+	require.Len(t, forwarded, 2)
+	assert.Contains(t, forwarded[0], `"bomFormat": "CycloneDX"`)
+	assert.Contains(t, forwarded[1], `"spdxVersion":"SPDX-2.3"`)
+}
+
+func TestSBOMTestWorkflow_Directory_AggregatesResults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEngine := mocks.NewMockEngine(ctrl)
+
+	dir := t.TempDir()
+	copyTestFile(t, "testdata/bom.json", filepath.Join(dir, "a.json"))
+	copyTestFile(t, "testdata/bom.json", filepath.Join(dir, "b.json"))
+	copyTestFile(t, "testdata/bom.spdx", filepath.Join(dir, "c.spdx"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("SBOMs"), 0o600))
+
+	mockICTX := mockInvocationContext(t, ctrl, mockEngine)
+	mockICTX.GetConfiguration().Set("file", dir)
+
+	summaries := map[string]json_schemas.TestSummary{
+		"a.json": {Type: "sbom", Artifacts: 1, Results: []json_schemas.TestSummaryResult{
+			{Severity: "critical", Total: 1, Open: 1},
+			{Severity: "high", Total: 2, Open: 2},
+		}},
+		"b.json": {Type: "sbom", Artifacts: 2, Results: []json_schemas.TestSummaryResult{
+			{Severity: "critical", Total: 0, Open: 0},
+			{Severity: "high", Total: 3, Open: 2, Ignored: 1},
+		}},
+		"c.spdx": {Type: "sbom", Artifacts: 1, Results: []json_schemas.TestSummaryResult{
+			{Severity: "low", Total: 4, Open: 4},
+		}},
+	}
+	mockEngine.EXPECT().
+		InvokeWithConfig(sbomtest.OsFlowsTestWorkflowID, gomock.Any()).
+		DoAndReturn(func(_ workflow.Identifier, cfg configuration.Configuration) ([]workflow.Data, error) {
+			// Converted documents are tested from a temporary file.
+			name := filepath.Base(cfg.GetString(flags.FlagSBOM))
+			if _, ok := summaries[name]; !ok {
+				name = "c.spdx"
+			}
+			summary, err := json.Marshal(summaries[name])
+			return []workflow.Data{
+				workflow.NewData(sbomtest.OsFlowsTestWorkflowID, "text/plain", []byte(name)),
+				workflow.NewData(sbomtest.OsFlowsTestWorkflowID, content_type.TEST_SUMMARY, summary),
+			}, err
+		}).
+		Times(3)
+
+	result, err := sbomtest.TestWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, result, 4)
+	for i, name := range []string{"a.json", "b.json", "c.spdx"} {
+		assert.Equal(t, filepath.Join(dir, name), result[i].GetContentLocation())
+		assert.Equal(t, []byte(name), result[i].GetPayload())
+	}
+	require.Equal(t, content_type.TEST_SUMMARY, result[3].GetContentType())
+	var summary json_schemas.TestSummary
+	require.NoError(t, json.Unmarshal(result[3].GetPayload().([]byte), &summary))
+	assert.Equal(t, json_schemas.TestSummary{
+		Type:      "sbom",
+		Artifacts: 4,
+		Path:      dir,
+		Results: []json_schemas.TestSummaryResult{
+			{Severity: "critical", Total: 1, Open: 1},
+			{Severity: "high", Total: 5, Open: 4, Ignored: 1},
+			{Severity: "low", Total: 4, Open: 4},
+		},
+	}, summary)
+}
+
+func TestSBOMTestWorkflow_Glob_TestsMatchingFiles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEngine := mocks.NewMockEngine(ctrl)
+
+	dir := t.TempDir()
+	copyTestFile(t, "testdata/bom.json", filepath.Join(dir, "a.json"))
+	copyTestFile(t, "testdata/bom.json", filepath.Join(dir, "b.json"))
+	copyTestFile(t, "testdata/bom.spdx", filepath.Join(dir, "c.spdx"))
+
+	mockICTX := mockInvocationContext(t, ctrl, mockEngine)
+	mockICTX.GetConfiguration().Set("file", filepath.Join(dir, "*.json"))
+
+	var mu sync.Mutex
+	var forwarded []string
+	mockEngine.EXPECT().
+		InvokeWithConfig(sbomtest.OsFlowsTestWorkflowID, gomock.Any()).
+		DoAndReturn(func(_ workflow.Identifier, cfg configuration.Configuration) ([]workflow.Data, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			forwarded = append(forwarded, cfg.GetString(flags.FlagSBOM))
+			return []workflow.Data{}, nil
+		}).
+		Times(2)
+
+	_, err := sbomtest.TestWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")}, forwarded)
+}
+
+func TestSBOMTestWorkflow_Glob_NoMatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEngine := mocks.NewMockEngine(ctrl)
+
+	mockICTX := mockInvocationContext(t, ctrl, mockEngine)
+	mockICTX.GetConfiguration().Set("file", filepath.Join(t.TempDir(), "*.json"))
+
+	mockEngine.EXPECT().InvokeWithConfig(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := sbomtest.TestWorkflow(mockICTX, []workflow.Data{})
+
+	var snykErr snyk_errors.Error
+	require.True(t, errors.As(err, &snykErr))
+	assert.Contains(t, snykErr.Detail, "No SBOM files were found")
+}
+
+func TestSBOMTestWorkflow_Directory_ReportsFailingDocuments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEngine := mocks.NewMockEngine(ctrl)
+
+	dir := t.TempDir()
+	for _, name := range []string{"a.json", "b.json", "d.json"} {
+		copyTestFile(t, "testdata/bom.json", filepath.Join(dir, name))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c.json"), []byte(`{"bomFormat":`), 0o600))
+
+	mockICTX := mockInvocationContext(t, ctrl, mockEngine)
+	mockICTX.GetConfiguration().Set("file", dir)
+
+	mockEngine.EXPECT().
+		InvokeWithConfig(sbomtest.OsFlowsTestWorkflowID, gomock.Any()).
+		DoAndReturn(func(_ workflow.Identifier, cfg configuration.Configuration) ([]workflow.Data, error) {
+			name := filepath.Base(cfg.GetString(flags.FlagSBOM))
+			if name == "b.json" {
+				return nil, errors.New("failed to test " + name)
+			}
+			return []workflow.Data{workflow.NewData(sbomtest.OsFlowsTestWorkflowID, "text/plain", []byte(name))}, nil
+		}).
+		Times(3)
+
+	result, err := sbomtest.TestWorkflow(mockICTX, []workflow.Data{})
+	require.NoError(t, err)
+
+	require.Len(t, result, 5)
+	for i, name := range []string{"a.json", "b.json", "c.json", "d.json"} {
+		assert.Equal(t, filepath.Join(dir, name), result[i].GetContentLocation())
+	}
+	assert.Equal(t, []byte("a.json"), result[0].GetPayload())
+	assert.Equal(t, []byte(fmt.Sprintf("Failed to test %s: failed to test b.json\n", filepath.Join(dir, "b.json"))), result[1].GetPayload())
+	assert.Contains(t, string(result[2].GetPayload().([]byte)), "is not valid JSON")
+	require.Len(t, result[2].GetErrorList(), 1)
+	assert.Equal(t, []byte("d.json"), result[3].GetPayload())
+	assert.Equal(t, content_type.TEST_SUMMARY, result[4].GetContentType())
+	payload, ok := result[4].GetPayload().([]byte)
+	require.True(t, ok)
+	var summary json_schemas.TestSummary
+	require.NoError(t, json.Unmarshal(payload, &summary))
+	assert.Equal(t, []json_schemas.TestSummaryResult{{Severity: "critical", Total: 2, Open: 2}}, summary.Results)
+}
+
+func TestSBOMTestWorkflow_Stdin_ForwardsWorkingDirectoryPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEngine := mocks.NewMockEngine(ctrl)
+
+	b, err := os.ReadFile("testdata/bom.json")
+	require.NoError(t, err)
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "stdin.json"), b, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".snyk"), []byte("ignore: {}\n"), 0o600))
+	stdin, err := os.Open(filepath.Join(dir, "stdin.json"))
+	require.NoError(t, err)
+	defer stdin.Close() //nolint:errcheck // Read-only file
+	origStdin := os.Stdin
+	os.Stdin = stdin
+	defer func() { os.Stdin = origStdin }()
+	t.Chdir(dir)
+
+	mockICTX := mockInvocationContext(t, ctrl, mockEngine)
+	mockICTX.GetConfiguration().Set("file", "-")
+
+	var forwardedConfig configuration.Configuration
+	var forwarded []byte
+	mockEngine.EXPECT().
+		InvokeWithConfig(sbomtest.OsFlowsTestWorkflowID, gomock.Any()).
+		DoAndReturn(func(_ workflow.Identifier, cfg configuration.Configuration) ([]workflow.Data, error) {
+			forwardedConfig = cfg
+			forwarded, err = os.ReadFile(cfg.GetString(flags.FlagSBOM))
+			return []workflow.Data{}, err
+		}).
+		Times(1)
+
+	_, err = sbomtest.TestWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	assert.Equal(t, b, forwarded)
+	assert.Equal(t, ".snyk", forwardedConfig.GetString(flags.FlagPolicyPath))
+}
+
+func TestSBOMTestWorkflow_UploadSizeExceedsLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEngine := mocks.NewMockEngine(ctrl)
+
+	// Random hex digits compress to about half their size, so the document
+	// exceeds the limit of 1 MB once compressed.
+	description := make([]byte, 1_500_000)
+	_, err := rand.Read(description)
+	require.NoError(t, err)
+	filename := filepath.Join(t.TempDir(), "large.json")
+	require.NoError(t, os.WriteFile(filename, []byte(`{"bomFormat":"CycloneDX","specVersion":"1.5","version":1,`+
+		`"components":[{"type":"library","name":"large","description":"`+hex.EncodeToString(description)+`"}]}`), 0o600))
+
+	mockICTX := mockInvocationContext(t, ctrl, mockEngine)
+	mockICTX.GetConfiguration().Set("file", filename)
+	mockICTX.GetConfiguration().Set(flags.FlagMaxUploadSize, 1)
+
+	mockEngine.EXPECT().InvokeWithConfig(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err = sbomtest.TestWorkflow(mockICTX, []workflow.Data{})
+
+	var snykErr snyk_errors.Error
+	require.True(t, errors.As(err, &snykErr))
+	assert.Contains(t, snykErr.Detail, "Its compressed size exceeds the maximum of 1 MB")
+}
+
+func TestSBOMTestWorkflow_LargeFile_StreamedFromDisk(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEngine := mocks.NewMockEngine(ctrl)
+
+	// The file exceeds the size limit of documents read into memory, but
+	// compresses well below the upload size limit.
+	filename := filepath.Join(t.TempDir(), "large.json")
+	f, err := os.Create(filename)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"bomFormat":"CycloneDX","specVersion":"1.5","version":1,"components":[]}`)
+	require.NoError(t, err)
+	require.NoError(t, f.Truncate(sbom.FileSizeLimit+1))
+	require.NoError(t, f.Close())
+
+	mockICTX := mockInvocationContext(t, ctrl, mockEngine)
+	mockICTX.GetConfiguration().Set("file", filename)
+
+	mockEngine.EXPECT().
+		InvokeWithConfig(sbomtest.OsFlowsTestWorkflowID, gomock.Any()).
+		DoAndReturn(func(_ workflow.Identifier, cfg configuration.Configuration) ([]workflow.Data, error) {
+			// The file is tested as it is, rather than from a temporary copy.
+			assert.Equal(t, filename, cfg.GetString(flags.FlagSBOM))
+			return []workflow.Data{}, nil
+		}).
+		Times(1)
+
+	_, err = sbomtest.TestWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+}
+
 func TestSBOMTestWorkflow_ReportFlag_FFEnabled_DelegatesToOSF(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -249,6 +633,33 @@ func TestSBOMTestWorkflow_ExperimentalFlagIgnored(t *testing.T) {
 
 // Helpers
 
+func copyTestFile(t *testing.T, src, dst string) {
+	t.Helper()
+
+	b, err := os.ReadFile(src)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(dst, b, 0o600))
+}
+
+func writeZip(t *testing.T, archive string, filenames ...string) {
+	t.Helper()
+
+	f, err := os.Create(archive)
+	require.NoError(t, err)
+
+	zw := zip.NewWriter(f)
+	for _, filename := range filenames {
+		b, err := os.ReadFile(filename)
+		require.NoError(t, err)
+		w, err := zw.Create(filepath.Base(filename))
+		require.NoError(t, err)
+		_, err = w.Write(b)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+}
+
 func mockInvocationContext(
 	t *testing.T,
 	ctrl *gomock.Controller,
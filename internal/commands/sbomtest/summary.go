@@ -2,9 +2,14 @@ package sbomtest
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 
+	"github.com/rs/zerolog"
+	snyk_errors "github.com/snyk/error-catalog-golang-public/snyk_errors"
 	"github.com/snyk/go-application-framework/pkg/local_workflows/content_type"
 	"github.com/snyk/go-application-framework/pkg/local_workflows/json_schemas"
+	"github.com/snyk/go-application-framework/pkg/workflow"
 
 	"github.com/snyk/cli-extension-sbom/internal/snykclient"
 )
@@ -41,3 +46,103 @@ func BuildTestSummary(resultsSummary *snykclient.SBOMTestSummary) (data []byte,
 	}
 	return data, content_type.TEST_SUMMARY, nil
 }
+
+// aggregateResults returns the results of testing a batch of documents, each
+// located at the name of its document, with their test summaries replaced by
+// a single one summing them up. The CLI's exit code is taken from it, so that
+// it reflects the worst result of the batch. Documents that failed to be
+// tested are reported in the results, and counted in the summary as open
+// critical findings, so that the CLI exits with a non-zero exit code whatever
+// the severity threshold.
+func aggregateResults(docs []*document, path string, logger *zerolog.Logger) []workflow.Data {
+	summaries := make([]*json_schemas.TestSummary, 0, len(docs))
+	var out []workflow.Data
+	var failed int
+	for _, doc := range docs {
+		if doc.err != nil {
+			failed++
+			out = append(out, failureData(doc))
+			continue
+		}
+		for _, d := range doc.results {
+			if summary := testSummary(d); summary != nil {
+				summaries = append(summaries, summary)
+				continue
+			}
+			d.SetContentLocation(doc.name)
+			out = append(out, d)
+		}
+	}
+	if failed > 0 {
+		summaries = append(summaries, &json_schemas.TestSummary{
+			Results: []json_schemas.TestSummaryResult{{Severity: "critical", Total: failed, Open: failed}},
+		})
+	}
+
+	data, err := json.Marshal(sumTestSummaries(summaries, path))
+	if err != nil {
+		logger.Printf("Failed to marshal the test summary: %v\n", err)
+		return out
+	}
+	return append(out, workflow.NewData(WorkflowDataID, content_type.TEST_SUMMARY, data))
+}
+
+// failureData returns the result reporting that a document failed to be
+// tested, carrying the error if it is a Snyk error.
+func failureData(doc *document) workflow.Data {
+	var snykErr snyk_errors.Error
+	isSnykErr := errors.As(doc.err, &snykErr)
+	detail := doc.err.Error()
+	if isSnykErr {
+		detail = snykErr.Detail
+	}
+
+	d := workflow.NewData(WorkflowDataID, "text/plain", []byte(fmt.Sprintf("Failed to test %s: %s\n", doc.name, detail)))
+	d.SetContentLocation(doc.name)
+	if isSnykErr {
+		d.AddError(snykErr)
+	}
+	return d
+}
+
+// testSummary returns the test summary the data holds, or nil if it does not
+// hold one.
+func testSummary(d workflow.Data) *json_schemas.TestSummary {
+	if d.GetContentType() != content_type.TEST_SUMMARY {
+		return nil
+	}
+	b, ok := d.GetPayload().([]byte)
+	if !ok {
+		return nil
+	}
+	var summary json_schemas.TestSummary
+	if err := json.Unmarshal(b, &summary); err != nil {
+		return nil
+	}
+	return &summary
+}
+
+// sumTestSummaries sums the issue counts of the summaries by severity, in the
+// order severities first appear in.
+func sumTestSummaries(summaries []*json_schemas.TestSummary, path string) *json_schemas.TestSummary {
+	sum := &json_schemas.TestSummary{Type: "sbom", Path: path, Results: []json_schemas.TestSummaryResult{}}
+	bySeverity := make(map[string]int)
+	for _, s := range summaries {
+		if s.Type != "" {
+			sum.Type = s.Type
+		}
+		sum.Artifacts += s.Artifacts
+		for _, r := range s.Results {
+			i, ok := bySeverity[r.Severity]
+			if !ok {
+				i = len(sum.Results)
+				bySeverity[r.Severity] = i
+				sum.Results = append(sum.Results, json_schemas.TestSummaryResult{Severity: r.Severity})
+			}
+			sum.Results[i].Total += r.Total
+			sum.Results[i].Open += r.Open
+			sum.Results[i].Ignored += r.Ignored
+		}
+	}
+	return sum
+}
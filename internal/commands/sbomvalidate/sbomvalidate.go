@@ -0,0 +1,91 @@
+package sbomvalidate
+
+import (
+	stderr "errors"
+	"fmt"
+
+	"github.com/snyk/go-application-framework/pkg/workflow"
+
+	"github.com/snyk/cli-extension-sbom/internal/errors"
+	"github.com/snyk/cli-extension-sbom/internal/flags"
+	"github.com/snyk/cli-extension-sbom/internal/sbom"
+)
+
+var (
+	WorkflowID     = workflow.NewWorkflowIdentifier("sbom.validate")
+	WorkflowDataID = workflow.NewTypeIdentifier(WorkflowID, "sbom.validate")
+)
+
+func RegisterWorkflows(e workflow.Engine) error {
+	sbomFlagset := flags.GetSBOMValidateFlagSet()
+
+	c := workflow.ConfigurationOptionsFromFlagset(sbomFlagset)
+
+	if _, err := e.Register(WorkflowID, c, ValidateWorkflow); err != nil {
+		return fmt.Errorf("error while registering %s workflow: %w", WorkflowID, err)
+	}
+
+	return nil
+}
+
+func ValidateWorkflow(
+	ictx workflow.InvocationContext,
+	_ []workflow.Data,
+) ([]workflow.Data, error) {
+	config := ictx.GetConfiguration()
+	logger := ictx.GetEnhancedLogger()
+	filename := config.GetString(flags.FlagFile)
+	errFactory := errors.NewErrorFactory(logger)
+
+	logger.Println("SBOM Validate workflow start")
+
+	if filename == "" {
+		return nil, errFactory.NewMissingFilenameFlagError()
+	}
+
+	logger.Println("Target SBOM document:", filename)
+
+	b, encoding, err := sbom.ReadSBOMFileWithEncoding(filename, errFactory)
+	if err != nil {
+		return nil, err
+	}
+	// Converted documents are not validated, as the schemas are those of
+	// the JSON documents.
+	if encoding != sbom.EncodingJSON {
+		return nil, errFactory.NewUnsupportedValidationEncodingError(encoding)
+	}
+
+	res, err := sbom.Validate(b)
+	switch {
+	case stderr.Is(err, sbom.ErrUnknownFormat):
+		return nil, errFactory.NewUnknownSBOMFormatError()
+	case stderr.Is(err, sbom.ErrUnsupportedSpecVersion):
+		format, _ := sbom.DetectFormat(b) //nolint:errcheck // Validate already detected the format
+		return nil, errFactory.NewUnsupportedSpecVersionError(format.String(), supportedFormats())
+	case err != nil:
+		return nil, errFactory.NewFailedToValidateSBOMError(err)
+	}
+
+	logger.Printf("Validated %s document, found %d violation(s)\n", res.Format, len(res.Violations))
+
+	if !res.Valid() {
+		violations := make([]string, 0, len(res.Violations))
+		for _, v := range res.Violations {
+			violations = append(violations, v.String())
+		}
+		return nil, errFactory.NewInvalidSBOMError(res.Format.String(), violations)
+	}
+
+	out := fmt.Sprintf("%s: valid %s document\n", filename, res.Format)
+
+	return []workflow.Data{workflow.NewData(WorkflowDataID, "text/plain", []byte(out))}, nil
+}
+
+func supportedFormats() []string {
+	formats := sbom.SupportedFormats()
+	names := make([]string, 0, len(formats))
+	for _, f := range formats {
+		names = append(names, f.String())
+	}
+	return names
+}
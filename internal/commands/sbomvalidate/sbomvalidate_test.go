@@ -0,0 +1,111 @@
+package sbomvalidate_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	snyk_errors "github.com/snyk/error-catalog-golang-public/snyk_errors"
+	"github.com/snyk/go-application-framework/pkg/configuration"
+	"github.com/snyk/go-application-framework/pkg/mocks"
+	"github.com/snyk/go-application-framework/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/commands/sbomvalidate"
+	"github.com/snyk/cli-extension-sbom/internal/flags"
+)
+
+func TestSBOMValidateWorkflow_NoFileFlag(t *testing.T) {
+	mockICTX := mockInvocationContext(t, "")
+
+	_, err := sbomvalidate.ValidateWorkflow(mockICTX, []workflow.Data{})
+
+	assert.ErrorContains(t, err, "Flag `--file` is required to execute this command. Value should point to a valid SBOM document.")
+}
+
+func TestSBOMValidateWorkflow_Valid(t *testing.T) {
+	mockICTX := mockInvocationContext(t, "testdata/bom.json")
+
+	result, err := sbomvalidate.ValidateWorkflow(mockICTX, []workflow.Data{})
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "text/plain", result[0].GetContentType())
+	payload, ok := result[0].GetPayload().([]byte)
+	require.True(t, ok)
+	assert.Equal(t, "testdata/bom.json: valid CycloneDX 1.4 document\n", string(payload))
+}
+
+func TestSBOMValidateWorkflow_Errors(t *testing.T) {
+	tc := []struct {
+		name     string
+		file     string
+		expected string
+	}{
+		{
+			name: "schema violations",
+			file: "testdata/invalid-bom.json",
+			expected: "The file provided by the `--file` flag is not a valid CycloneDX 1.5 document:\n" +
+				"/components/0: missing property 'name'\n" +
+				"/components/0/type: value must be one of 'application', 'framework', 'library', 'container', " +
+				"'operating-system', 'device', 'firmware', 'file', 'platform', 'device-driver', 'machine-learning-model', 'data'",
+		},
+		{
+			name:     "unknown format",
+			file:     "testdata/unknown.json",
+			expected: "The file provided by the `--file` flag is neither a CycloneDX nor an SPDX JSON document.",
+		},
+		{
+			name: "CycloneDX XML",
+			file: "testdata/bom.xml",
+			expected: "The file provided by the `--file` flag is a CycloneDX XML document, which can not be validated. " +
+				"Only CycloneDX and SPDX JSON documents are validated against their schema.",
+		},
+		{
+			name: "SPDX tag-value",
+			file: "testdata/bom.spdx",
+			expected: "The file provided by the `--file` flag is a SPDX tag-value document, which can not be validated. " +
+				"Only CycloneDX and SPDX JSON documents are validated against their schema.",
+		},
+		{
+			name: "unsupported spec version",
+			file: "testdata/spdx-2.2.json",
+			expected: "The file provided by the `--file` flag is a SPDX 2.2 document, which can not be validated. " +
+				"Supported formats are: CycloneDX 1.4, CycloneDX 1.5, CycloneDX 1.6, SPDX 2.3",
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			mockICTX := mockInvocationContext(t, tt.file)
+
+			_, err := sbomvalidate.ValidateWorkflow(mockICTX, []workflow.Data{})
+
+			var snykErr snyk_errors.Error
+			require.True(t, errors.As(err, &snykErr))
+			assert.Equal(t, "Invalid flag option", snykErr.Title)
+			assert.Equal(t, tt.expected, snykErr.Detail)
+		})
+	}
+}
+
+// Helpers
+
+func mockInvocationContext(t *testing.T, file string) workflow.InvocationContext {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	mockLogger := zerolog.New(io.Discard)
+
+	mockConfig := configuration.New()
+	mockConfig.Set(flags.FlagFile, file)
+
+	ictx := mocks.NewMockInvocationContext(ctrl)
+	ictx.EXPECT().GetConfiguration().Return(mockConfig).AnyTimes()
+	ictx.EXPECT().GetEnhancedLogger().Return(&mockLogger).AnyTimes()
+
+	return ictx
+}
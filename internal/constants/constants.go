@@ -26,3 +26,12 @@ const FeatureFlagSbomIncludeComponentMetadata = "internal_snyk_sbom_include_comp
 
 // SbomIncludeComponentMetadata is the feature-flag-service name for the include-component-metadata feature.
 const SbomIncludeComponentMetadata = "sbom-include-component-metadata"
+
+// Offline is the analytics key for the offline CLI flag.
+const Offline = "offline"
+
+// SplitProjects is the analytics key for the split-projects CLI flag.
+const SplitProjects = "split-projects"
+
+// Reproducible is the analytics key for the reproducible CLI flag.
+const Reproducible = "reproducible"
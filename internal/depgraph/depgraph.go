@@ -0,0 +1,127 @@
+package depgraph
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type (
+	DepGraph struct {
+		SchemaVersion string     `json:"schemaVersion"`
+		PkgManager    PkgManager `json:"pkgManager"`
+		Pkgs          []Pkg      `json:"pkgs"`
+		Graph         Graph      `json:"graph"`
+	}
+
+	PkgManager struct {
+		Name string `json:"name"`
+	}
+
+	Pkg struct {
+		ID   string  `json:"id"`
+		Info PkgInfo `json:"info"`
+	}
+
+	PkgInfo struct {
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+		PURL    string `json:"purl,omitempty"`
+	}
+
+	Graph struct {
+		RootNodeID string `json:"rootNodeId"`
+		Nodes      []Node `json:"nodes"`
+	}
+
+	Node struct {
+		NodeID string    `json:"nodeId"`
+		PkgID  string    `json:"pkgId"`
+		Deps   []NodeRef `json:"deps"`
+	}
+
+	NodeRef struct {
+		NodeID string `json:"nodeId"`
+	}
+)
+
+// Parse decodes a depgraph as returned by the depgraph workflow. Both the
+// bare depgraph and the `{"depGraph": {...}}` envelope are accepted.
+func Parse(b []byte) (*DepGraph, error) {
+	var envelope struct {
+		DepGraph *DepGraph `json:"depGraph"`
+	}
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse depgraph: %w", err)
+	}
+	if envelope.DepGraph != nil {
+		return envelope.DepGraph, nil
+	}
+
+	var dg DepGraph
+	if err := json.Unmarshal(b, &dg); err != nil {
+		return nil, fmt.Errorf("failed to parse depgraph: %w", err)
+	}
+	return &dg, nil
+}
+
+// Pkg returns the package with the given ID, or nil if the depgraph does not
+// contain it.
+func (dg *DepGraph) Pkg(id string) *Pkg {
+	for i := range dg.Pkgs {
+		if dg.Pkgs[i].ID == id {
+			return &dg.Pkgs[i]
+		}
+	}
+	return nil
+}
+
+// PkgsByID maps the IDs of the packages of the depgraph to the packages, for
+// looking up many of them.
+func (dg *DepGraph) PkgsByID() map[string]*Pkg {
+	pkgs := make(map[string]*Pkg, len(dg.Pkgs))
+	for i := range dg.Pkgs {
+		if _, ok := pkgs[dg.Pkgs[i].ID]; !ok {
+			pkgs[dg.Pkgs[i].ID] = &dg.Pkgs[i]
+		}
+	}
+	return pkgs
+}
+
+// RootPkg returns the package the root node of the graph points to.
+func (dg *DepGraph) RootPkg() *Pkg {
+	for i := range dg.Graph.Nodes {
+		if dg.Graph.Nodes[i].NodeID == dg.Graph.RootNodeID {
+			return dg.Pkg(dg.Graph.Nodes[i].PkgID)
+		}
+	}
+	return nil
+}
+
+// PkgDependencies resolves the node-level edges of the graph to package IDs.
+// The result maps each package ID to the (deduplicated) IDs of the packages
+// it depends on, and preserves the order in which packages first appear.
+func (dg *DepGraph) PkgDependencies() (pkgIDs []string, deps map[string][]string) {
+	nodePkgs := make(map[string]string, len(dg.Graph.Nodes))
+	for _, n := range dg.Graph.Nodes {
+		nodePkgs[n.NodeID] = n.PkgID
+	}
+
+	deps = make(map[string][]string, len(dg.Pkgs))
+	seen := make(map[string]map[string]bool, len(dg.Pkgs))
+	for _, n := range dg.Graph.Nodes {
+		if _, ok := seen[n.PkgID]; !ok {
+			seen[n.PkgID] = make(map[string]bool)
+			pkgIDs = append(pkgIDs, n.PkgID)
+		}
+		for _, d := range n.Deps {
+			depPkgID, ok := nodePkgs[d.NodeID]
+			if !ok || seen[n.PkgID][depPkgID] {
+				continue
+			}
+			seen[n.PkgID][depPkgID] = true
+			deps[n.PkgID] = append(deps[n.PkgID], depPkgID)
+		}
+	}
+
+	return pkgIDs, deps
+}
@@ -0,0 +1,77 @@
+package depgraph_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/depgraph"
+)
+
+//go:embed testdata/depgraph.json
+var depGraphData []byte
+
+func TestParse(t *testing.T) {
+	tc := map[string][]byte{
+		"wrapped depgraph": depGraphData,
+		"bare depgraph": []byte(`{"pkgManager":{"name":"npm"},"pkgs":[{"id":"app@1.0.0","info":{"name":"app","version":"1.0.0"}}],` +
+			`"graph":{"rootNodeId":"root-node","nodes":[{"nodeId":"root-node","pkgId":"app@1.0.0","deps":[]}]}}`),
+	}
+
+	for name, b := range tc {
+		t.Run(name, func(t *testing.T) {
+			dg, err := depgraph.Parse(b)
+
+			require.NoError(t, err)
+			assert.Equal(t, "npm", dg.PkgManager.Name)
+			require.NotNil(t, dg.RootPkg())
+		})
+	}
+}
+
+func TestParse_InvalidJSON(t *testing.T) {
+	_, err := depgraph.Parse([]byte(`not json`))
+
+	assert.ErrorContains(t, err, "failed to parse depgraph")
+}
+
+func TestDepGraph_RootPkg(t *testing.T) {
+	dg, err := depgraph.Parse(depGraphData)
+	require.NoError(t, err)
+
+	root := dg.RootPkg()
+
+	require.NotNil(t, root)
+	assert.Equal(t, "demo-app-for-test@1.1.1", root.ID)
+}
+
+func TestDepGraph_PkgDependencies(t *testing.T) {
+	dg, err := depgraph.Parse([]byte(`{"pkgManager":{"name":"npm"},"graph":{"rootNodeId":"root","nodes":[` +
+		`{"nodeId":"root","pkgId":"app@1.0.0","deps":[{"nodeId":"a"},{"nodeId":"b|1"}]},` +
+		`{"nodeId":"a","pkgId":"a@1.0.0","deps":[{"nodeId":"b|2"}]},` +
+		`{"nodeId":"b|1","pkgId":"b@1.0.0","deps":[]},` +
+		`{"nodeId":"b|2","pkgId":"b@1.0.0","deps":[]}]}}`))
+	require.NoError(t, err)
+
+	pkgIDs, deps := dg.PkgDependencies()
+
+	assert.Equal(t, []string{"app@1.0.0", "a@1.0.0", "b@1.0.0"}, pkgIDs)
+	assert.Equal(t, map[string][]string{
+		"app@1.0.0": {"a@1.0.0", "b@1.0.0"},
+		"a@1.0.0":   {"b@1.0.0"},
+	}, deps)
+}
+
+func TestDepGraph_PkgsByID(t *testing.T) {
+	dg, err := depgraph.Parse(depGraphData)
+	require.NoError(t, err)
+
+	pkgs := dg.PkgsByID()
+
+	assert.Len(t, pkgs, len(dg.Pkgs))
+	for _, id := range []string{"demo-app-for-test@1.1.1", dg.Pkgs[len(dg.Pkgs)-1].ID} {
+		assert.Same(t, dg.Pkg(id), pkgs[id])
+	}
+}
@@ -0,0 +1,94 @@
+package depgraph
+
+import (
+	"net/url"
+	"strings"
+)
+
+// purlTypes maps the package manager names used in depgraphs to package URL types.
+var purlTypes = map[string]string{
+	"npm":       "npm",
+	"yarn":      "npm",
+	"pnpm":      "npm",
+	"maven":     "maven",
+	"gradle":    "maven",
+	"sbt":       "maven",
+	"pip":       "pypi",
+	"pipenv":    "pypi",
+	"poetry":    "pypi",
+	"uv":        "pypi",
+	"gomodules": "golang",
+	"golangdep": "golang",
+	"govendor":  "golang",
+	"nuget":     "nuget",
+	"paket":     "nuget",
+	"rubygems":  "gem",
+	"composer":  "composer",
+	"cocoapods": "cocoapods",
+	"hex":       "hex",
+	"swift":     "swift",
+	"cargo":     "cargo",
+	"cpp":       "generic",
+	"unmanaged": "generic",
+	"deb":       "deb",
+	"apk":       "apk",
+	"rpm":       "rpm",
+}
+
+// PackageURL returns the package URL of a package. An explicit purl on the
+// package wins; otherwise one is derived from the package manager, name and
+// version. An empty string is returned for unknown package managers.
+func PackageURL(pkgManager string, info PkgInfo) string {
+	if info.PURL != "" {
+		return info.PURL
+	}
+
+	purlType, ok := purlTypes[pkgManager]
+	if !ok || info.Name == "" {
+		return ""
+	}
+
+	var namespace, name string
+	switch purlType {
+	case "maven":
+		// Maven coordinates are encoded as `groupId:artifactId`.
+		namespace, name = splitLast(info.Name, ":")
+	case "pypi":
+		name = strings.ReplaceAll(strings.ToLower(info.Name), "_", "-")
+	default:
+		namespace, name = splitLast(info.Name, "/")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("pkg:")
+	sb.WriteString(purlType)
+	sb.WriteString("/")
+	if namespace != "" {
+		segments := strings.Split(namespace, "/")
+		for i, s := range segments {
+			segments[i] = escapePURLSegment(s)
+		}
+		sb.WriteString(strings.Join(segments, "/"))
+		sb.WriteString("/")
+	}
+	sb.WriteString(escapePURLSegment(name))
+	if info.Version != "" {
+		sb.WriteString("@")
+		sb.WriteString(escapePURLSegment(info.Version))
+	}
+
+	return sb.String()
+}
+
+func splitLast(s, sep string) (head, tail string) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return "", s
+	}
+	return s[:i], s[i+len(sep):]
+}
+
+func escapePURLSegment(s string) string {
+	// url.PathEscape leaves `@` and `:` untouched, which are significant in purls.
+	return strings.NewReplacer("@", "%40", ":", "%3A").Replace(url.PathEscape(s))
+}
@@ -0,0 +1,73 @@
+package depgraph_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snyk/cli-extension-sbom/internal/depgraph"
+)
+
+func TestPackageURL(t *testing.T) {
+	tc := []struct {
+		name       string
+		pkgManager string
+		info       depgraph.PkgInfo
+		expected   string
+	}{
+		{
+			name:       "npm",
+			pkgManager: "npm",
+			info:       depgraph.PkgInfo{Name: "express", Version: "4.4.0"},
+			expected:   "pkg:npm/express@4.4.0",
+		},
+		{
+			name:       "scoped npm package",
+			pkgManager: "yarn",
+			info:       depgraph.PkgInfo{Name: "@babel/core", Version: "7.0.0"},
+			expected:   "pkg:npm/%40babel/core@7.0.0",
+		},
+		{
+			name:       "maven",
+			pkgManager: "maven",
+			info:       depgraph.PkgInfo{Name: "org.apache.commons:commons-lang3", Version: "3.12.0"},
+			expected:   "pkg:maven/org.apache.commons/commons-lang3@3.12.0",
+		},
+		{
+			name:       "pypi names are normalized",
+			pkgManager: "pip",
+			info:       depgraph.PkgInfo{Name: "Typing_Extensions", Version: "4.0.0"},
+			expected:   "pkg:pypi/typing-extensions@4.0.0",
+		},
+		{
+			name:       "golang",
+			pkgManager: "gomodules",
+			info:       depgraph.PkgInfo{Name: "github.com/rs/zerolog", Version: "v1.34.0"},
+			expected:   "pkg:golang/github.com/rs/zerolog@v1.34.0",
+		},
+		{
+			name:       "without version",
+			pkgManager: "rubygems",
+			info:       depgraph.PkgInfo{Name: "rails"},
+			expected:   "pkg:gem/rails",
+		},
+		{
+			name:       "explicit purl wins",
+			pkgManager: "npm",
+			info:       depgraph.PkgInfo{Name: "express", Version: "4.4.0", PURL: "pkg:npm/express@4.4.0?foo=bar"},
+			expected:   "pkg:npm/express@4.4.0?foo=bar",
+		},
+		{
+			name:       "unknown package manager",
+			pkgManager: "unknown",
+			info:       depgraph.PkgInfo{Name: "foo", Version: "1.0.0"},
+			expected:   "",
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, depgraph.PackageURL(tt.pkgManager, tt.info))
+		})
+	}
+}
@@ -190,6 +190,12 @@ func (ef *ErrorFactory) NewInvalidJSONError() error {
 	)
 }
 
+func (ef *ErrorFactory) NewUnsupportedSBOMEncodingError() error {
+	return snyk_cli_errors.NewInvalidFlagOptionError(
+		"The file provided by the `--file` flag is neither a JSON, a CycloneDX XML nor an SPDX tag-value document.",
+	)
+}
+
 func (ef *ErrorFactory) NewFailedToTestSBOMError() *SBOMExtensionError {
 	return ef.NewFatalSBOMTestError(fmt.Errorf("failed to test SBOM"))
 }
@@ -209,9 +215,44 @@ func (ef *ErrorFactory) NewInvalidFilePathError(err error, path string) error {
 	)
 }
 
-func (ef *ErrorFactory) NewFileSizeExceedsLimitError() error {
+func (ef *ErrorFactory) NewFileSizeExceedsLimitError(limit int64) error {
+	return snyk_cli_errors.NewInvalidFlagOptionError(
+		fmt.Sprintf("The provided file is too large. The maximum supported file size is %s.", megabytes(limit)),
+	)
+}
+
+func (ef *ErrorFactory) NewDecompressedSizeExceedsLimitError(limit int64) error {
+	return snyk_cli_errors.NewInvalidFlagOptionError(
+		fmt.Sprintf("The content of the provided file is too large once decompressed. The maximum supported size is %s.", megabytes(limit)),
+	)
+}
+
+func (ef *ErrorFactory) NewUploadSizeExceedsLimitError(limit int64) error {
+	return snyk_cli_errors.NewInvalidFlagOptionError(
+		fmt.Sprintf(
+			"The SBOM document is too large to upload. Its compressed size exceeds the maximum of %s, "+
+				"which can be raised with the `--max-upload-size` flag if the server accepts larger uploads.",
+			megabytes(limit),
+		),
+	)
+}
+
+func (ef *ErrorFactory) NewNoSBOMInArchiveError() error {
+	return snyk_cli_errors.NewInvalidFlagOptionError(
+		"The archive provided by the `--file` flag does not contain any JSON, CycloneDX XML or SPDX tag-value document.",
+	)
+}
+
+func (ef *ErrorFactory) NewMultipleSBOMsInArchiveError(count int) error {
 	return snyk_cli_errors.NewInvalidFlagOptionError(
-		"The provided file is too large. The maximum supported file size is 50 MB.",
+		fmt.Sprintf("The archive provided by the `--file` flag contains %d SBOM documents, but this command reads a single one.", count),
+	)
+}
+
+func (ef *ErrorFactory) NewNoSBOMFilesFoundError(pattern string) error {
+	return snyk_cli_errors.NewInvalidFlagOptionError(
+		fmt.Sprintf("No SBOM files were found for %q. "+
+			"Please ensure the `--file` flag value is an SBOM file, a directory of SBOM files or a glob pattern matching them.", pattern),
 	)
 }
 
@@ -251,3 +292,152 @@ func (ef *ErrorFactory) NewDirectoryIsEmptyError(dirPath string) *SBOMExtensionE
 		fmt.Sprintf("The directory %s is empty", dirPath),
 	)
 }
+
+func (ef *ErrorFactory) NewConflictingOutputFlagsError() error {
+	return snyk_cli_errors.NewInvalidFlagOptionError(
+		"The `--output-file` and `--output-dir` flags cannot be used together.",
+	)
+}
+
+func (ef *ErrorFactory) NewOutputFileWithMultipleFormatsError() error {
+	return snyk_cli_errors.NewInvalidFlagOptionError(
+		"The `--output-file` flag can only be used with a single format. Use `--output-dir` to write several formats.",
+	)
+}
+
+func (ef *ErrorFactory) NewOutputFileWithSplitProjectsError() error {
+	return snyk_cli_errors.NewInvalidFlagOptionError(
+		"The `--output-file` flag cannot be used with `--split-projects`. Use `--output-dir` to write one SBOM per project.",
+	)
+}
+
+func (ef *ErrorFactory) NewFailedToWriteOutputError(err error, path string) *SBOMExtensionError {
+	return ef.newErr(
+		err,
+		fmt.Sprintf("Failed to write the SBOM to %s.", path),
+	)
+}
+
+func (ef *ErrorFactory) NewGoModuleLevelWithLocalGenerationError(flag string) error {
+	return snyk_cli_errors.NewInvalidFlagOptionError(
+		fmt.Sprintf(
+			"The `--go-module-level` flag cannot be used with `--%s`, as the SBOM is then generated locally, "+
+				"which emits Go dependencies per package.",
+			flag,
+		),
+	)
+}
+
+func (ef *ErrorFactory) NewInvalidSourceDateEpochError(err error) *SBOMExtensionError {
+	return ef.newErr(
+		err,
+		"The SOURCE_DATE_EPOCH environment variable must be a UNIX timestamp (seconds since 1970-01-01) to generate a reproducible SBOM.",
+	)
+}
+
+func (ef *ErrorFactory) NewInvalidLifecycleError(invalid string, available []string) error {
+	return snyk_cli_errors.NewInvalidFlagOptionError(
+		fmt.Sprintf(
+			"The lifecycle provided (%s) is not one of the available lifecycle phases. "+
+				"Available phases are: %s",
+			invalid,
+			strings.Join(available, ", "),
+		),
+	)
+}
+
+func (ef *ErrorFactory) NewUnknownSBOMFormatError() error {
+	return snyk_cli_errors.NewInvalidFlagOptionError(
+		"The file provided by the `--file` flag is neither a CycloneDX nor an SPDX JSON document.",
+	)
+}
+
+func (ef *ErrorFactory) NewUnsupportedValidationEncodingError(encoding string) error {
+	return snyk_cli_errors.NewInvalidFlagOptionError(
+		fmt.Sprintf("The file provided by the `--file` flag is a %s document, which can not be validated. "+
+			"Only CycloneDX and SPDX JSON documents are validated against their schema.", encoding),
+	)
+}
+
+func (ef *ErrorFactory) NewUnsupportedSpecVersionError(format string, supported []string) error {
+	return snyk_cli_errors.NewInvalidFlagOptionError(
+		fmt.Sprintf(
+			"The file provided by the `--file` flag is a %s document, which can not be validated. "+
+				"Supported formats are: %s",
+			format,
+			strings.Join(supported, ", "),
+		),
+	)
+}
+
+func (ef *ErrorFactory) NewInvalidSBOMError(format string, violations []string) error {
+	return snyk_cli_errors.NewInvalidFlagOptionError(
+		fmt.Sprintf(
+			"The file provided by the `--file` flag is not a valid %s document:\n%s",
+			format,
+			strings.Join(violations, "\n"),
+		),
+	)
+}
+
+func (ef *ErrorFactory) NewFailedToValidateSBOMError(err error) *SBOMExtensionError {
+	return ef.newErr(
+		err,
+		"Failed to validate the SBOM document. Should this issue persist, please reach out to customer support.",
+	)
+}
+
+func (ef *ErrorFactory) NewFailedToDecodeSBOMError(err error, path string) *SBOMExtensionError {
+	return ef.newErr(
+		err,
+		fmt.Sprintf("Failed to read the SBOM document %q. Please check that it is a valid CycloneDX or SPDX JSON document.", path),
+	)
+}
+
+func (ef *ErrorFactory) NewUnknownSBOMInputError(path string) error {
+	return snyk_cli_errors.NewInvalidFlagOptionError(
+		fmt.Sprintf("The file %q is neither a CycloneDX JSON or XML document nor an SPDX JSON document.", path),
+	)
+}
+
+func (ef *ErrorFactory) NewUnsupportedSBOMInputError(path, format string) error {
+	return snyk_cli_errors.NewInvalidFlagOptionError(
+		fmt.Sprintf(
+			"The file %q is a %s document, which is not supported by this command. "+
+				"Supported are CycloneDX JSON and XML documents and SPDX 2.x JSON documents.",
+			path,
+			format,
+		),
+	)
+}
+
+func (ef *ErrorFactory) NewDiffFileCountError(count int) error {
+	return snyk_cli_errors.NewInvalidFlagOptionError(
+		fmt.Sprintf(
+			"Flag `--file` must be set exactly twice, to the SBOM to compare from and the SBOM to compare to (got %d).",
+			count,
+		),
+	)
+}
+
+func (ef *ErrorFactory) NewInvalidThresholdError(threshold int) error {
+	return snyk_cli_errors.NewInvalidFlagOptionError(
+		fmt.Sprintf("Flag `--threshold` must be a score between 0 and 100 (got %d).", threshold),
+	)
+}
+
+func (ef *ErrorFactory) NewFailedToConvertSBOMError(err error, format string) *SBOMExtensionError {
+	return ef.newErr(
+		err,
+		fmt.Sprintf("Failed to convert the SBOM document to %s. Should this issue persist, please reach out to customer support.", format),
+	)
+}
+
+// megabytes formats a size in bytes as a whole number of megabytes, or bytes
+// for sizes below a megabyte.
+func megabytes(size int64) string {
+	if size < 1_000_000 {
+		return fmt.Sprintf("%d bytes", size)
+	}
+	return fmt.Sprintf("%d MB", size/1_000_000)
+}
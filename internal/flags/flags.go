@@ -35,6 +35,25 @@ const (
 	FlagNugetPkgsFolder              = "packages-folder"
 	FlagUnmanagedMaxDepth            = "max-depth"
 	FlagIncludeProvenance            = "include-provenance"
+	FlagOffline                      = "offline"
+	FlagOutputFile                   = "output-file"
+	FlagOutputDir                    = "output-dir"
+	FlagSplitProjects                = "split-projects"
+	FlagSaveDepGraphs                = "save-depgraphs"
+	FlagReproducible                 = "reproducible"
+	FlagSupplier                     = "supplier"
+	FlagAuthor                       = "author"
+	FlagManufacturer                 = "manufacturer"
+	FlagLifecycle                    = "lifecycle"
+	FlagJSON                         = "json"
+	FlagFailOnDiff                   = "fail-on-diff"
+	FlagCheck                        = "check"
+	FlagThreshold                    = "threshold"
+	FlagQuality                      = "quality"
+	FlagQualityFile                  = "quality-file"
+	FlagMaxUploadSize                = "max-upload-size"
+	FlagConcurrency                  = "concurrency"
+	FlagFailOnPartial                = "fail-on-partial"
 
 	// OS Flows flags.
 	FlagReachability               = "reachability"
@@ -59,6 +78,18 @@ const (
 	FlagReport = "report"
 )
 
+// DefaultCheckThreshold is the default of FlagThreshold, requiring all NTIA
+// minimum elements to be present.
+const DefaultCheckThreshold = 100
+
+// DefaultMaxUploadSize is the default of FlagMaxUploadSize in MB, the maximum
+// compressed size of the SBOM documents the upload API accepts.
+const DefaultMaxUploadSize = 50
+
+// DefaultConcurrency is the default of FlagConcurrency, the number of SBOM
+// documents of a batch processed at once.
+const DefaultConcurrency = 4
+
 func GetSBOMCreateFlagSet() *pflag.FlagSet {
 	flagSet := pflag.NewFlagSet("snyk-cli-extension-sbom", pflag.ExitOnError)
 
@@ -74,7 +105,8 @@ func GetSBOMCreateFlagSet() *pflag.FlagSet {
 	flagSet.String(FlagFile, "", "Specify a package file.")
 	flagSet.String(FlagName, "", "Specify a name for the collection of all projects in the working directory.")
 	flagSet.String(FlagVersion, "", "Specify a version for the collection of all projects in the working directory.")
-	flagSet.StringP(FlagFormat, "f", "", "Specify the SBOM output format. (cyclonedx1.4+json, cyclonedx1.4+xml, spdx2.3+json)")
+	flagSet.StringP(FlagFormat, "f", "", "Specify the SBOM output format. (cyclonedx1.4+json, cyclonedx1.4+xml, spdx2.3+json, spdx3.0+json) "+
+		"Separate multiple formats with a comma to generate them from a single dependency resolution.")
 	flagSet.Bool(FlagDev, false, "Include development-only dependencies. Applicable only for some package managers.")
 	flagSet.Bool(FlagMavenAggregateProject, false, "Ensure all modules are resolvable by the Maven reactor.")
 	flagSet.Bool(FlagMavenSkipWrapper, false, "Use system Maven instead of the Maven wrapper.")
@@ -96,7 +128,25 @@ func GetSBOMCreateFlagSet() *pflag.FlagSet {
 	flagSet.String(FlagPythonPackageManager, "", `Add --package-manager=pip to your command if the file name is not "requirements.txt".`)
 	flagSet.Int(FlagUnmanagedMaxDepth, 0, "Specify the maximum level of archive extraction for unmanaged scanning.")
 	flagSet.Bool(FlagIncludeProvenance, false, "Include checksums in purl to support package provenance.")
-	flagSet.Bool(FlagGoModuleLevel, false, "Emit Go dependencies at the module level instead of per package.")
+	flagSet.Bool(FlagGoModuleLevel, false, "Emit Go dependencies at the module level instead of per package. "+
+		"Not available with --offline or --reproducible, which generate the SBOM locally.")
+	flagSet.String(FlagOutputFile, "", "Write the SBOM to the given file instead of printing it.")
+	flagSet.String(FlagOutputDir, "", "Write the SBOM to the given directory instead of printing it. "+
+		"Use with --all-projects to also write one SBOM per project.")
+	flagSet.Bool(FlagSplitProjects, false, "Use with --all-projects to generate a separate SBOM for each project.")
+	flagSet.String(FlagSaveDepGraphs, "", "Also write the dependency graphs the SBOM is generated from, and any scan errors, to the given directory.")
+	flagSet.String(FlagSupplier, "", "Specify the organization that supplies the software described by the SBOM.")
+	flagSet.String(FlagAuthor, "", "Specify the authors of the SBOM. Separate multiple authors with a comma.")
+	flagSet.String(FlagManufacturer, "", "Specify the organization that manufactures the software described by the SBOM.")
+	flagSet.String(FlagLifecycle, "", "Specify the lifecycle phases the SBOM is created in "+
+		"(design, pre-build, build, post-build, operations, discovery, decommission). Separate multiple phases with a comma.")
+	flagSet.Bool(FlagReproducible, false, "Generate the SBOM locally with deterministic output. "+
+		"Timestamps are taken from the SOURCE_DATE_EPOCH environment variable.")
+	flagSet.Bool(FlagOffline, false, "Generate the SBOM locally, without sending the dependency graphs to Snyk.")
+	flagSet.Bool(FlagCheck, false, "Check the generated SBOM for the NTIA minimum elements.")
+	flagSet.Int(FlagThreshold, DefaultCheckThreshold, "Use with --check to set the minimum score (0-100) the SBOM must reach.")
+	flagSet.Bool(FlagQuality, false, "Print a quality score report of each generated SBOM.")
+	flagSet.String(FlagQualityFile, "", "Write the quality score reports of the generated SBOMs as JSON to the given file.")
 
 	return flagSet
 }
@@ -105,7 +155,8 @@ func GetSBOMTestFlagSet() *pflag.FlagSet {
 	flagSet := pflag.NewFlagSet("snyk-cli-extension-sbom-test", pflag.ExitOnError)
 
 	flagSet.Bool(FlagExperimental, false, "Deprecated. Will be ignored.")
-	flagSet.String(FlagFile, "", "Specify an SBOM file.")
+	flagSet.String(FlagFile, "", "Specify an SBOM file, which may be gzip-compressed, or a zip or tar.gz archive of SBOM files. "+
+		"Specify a directory or a glob pattern to test several SBOM files. Use - to read it from stdin.")
 
 	// Flags being forwarded to the os flows test.
 	flagSet.Bool(FlagReachability, false, "Run reachability analysis on source code.")
@@ -115,6 +166,11 @@ func GetSBOMTestFlagSet() *pflag.FlagSet {
 	flagSet.Int(FlagRiskScoreThreshold, -1, "Include findings at or over this risk score threshold.")
 	flagSet.String(FlagSeverityThreshold, "", "Report only findings at the specified level or higher.")
 
+	flagSet.Bool(FlagQuality, false, "Print a quality score report of the SBOM before testing it.")
+	flagSet.String(FlagQualityFile, "", "Write the quality score report of the SBOM as JSON to the given file.")
+	flagSet.Int(FlagMaxUploadSize, DefaultMaxUploadSize, "Specify the maximum size in MB of the gzip-compressed SBOM documents to upload.")
+	flagSet.Int(FlagConcurrency, DefaultConcurrency, "Specify the number of SBOM documents to test at once.")
+
 	// `--report` and the project-attribute flags that accompany it. When `--report` is set,
 	// the test result is persisted as a monitored project (this replaces `snyk sbom monitor`).
 	flagSet.Bool(FlagReport, false, "Share results with the Snyk Web UI, persisting them as a monitored project.")
@@ -136,10 +192,74 @@ func GetSBOMMonitorFlagSet() *pflag.FlagSet {
 	flagSet := pflag.NewFlagSet("snyk-cli-extension-sbom-monitor", pflag.ExitOnError)
 
 	flagSet.Bool(FlagExperimental, false, "Enable experimental sbom monitor command.")
-	flagSet.String(FlagFile, "", "Specify a SBOM file.")
+	flagSet.String(FlagFile, "", "Specify a SBOM file, which may be gzip-compressed, or a zip or tar.gz archive of SBOM files. "+
+		"Specify a directory or a glob pattern to monitor several SBOM files. Use - to read it from stdin.")
 	flagSet.String(FlagPolicyPath, "", "Manually pass a path to a .snyk policy file.")
 	flagSet.String(FlagRemoteRepoURL, "", "Set or override the remote URL for the repository that you would like to monitor.")
 	flagSet.String(FlagTargetReference, "", "Specify a reference that differentiates this project, for example, a branch name or version.")
+	flagSet.Int(FlagMaxUploadSize, DefaultMaxUploadSize, "Specify the maximum size in MB of the gzip-compressed SBOM documents to upload.")
+	flagSet.Int(FlagConcurrency, DefaultConcurrency, "Specify the number of requests to convert and monitor SBOM documents to run at once.")
+	flagSet.Bool(FlagFailOnPartial, false, "Fail if any of the projects of the SBOM documents could not be monitored.")
+	flagSet.Bool(FlagJSON, false, "Print the conversion warnings and the monitored projects as JSON.")
+
+	return flagSet
+}
+
+func GetSBOMValidateFlagSet() *pflag.FlagSet {
+	flagSet := pflag.NewFlagSet("snyk-cli-extension-sbom-validate", pflag.ExitOnError)
+
+	flagSet.String(FlagFile, "", "Specify an SBOM file to validate.")
+
+	return flagSet
+}
+
+func GetSBOMMergeFlagSet() *pflag.FlagSet {
+	flagSet := pflag.NewFlagSet("snyk-cli-extension-sbom-merge", pflag.ExitOnError)
+
+	flagSet.StringSlice(FlagFile, nil, "Specify the SBOM files to merge. Repeat the flag or separate files with a comma.")
+	flagSet.String(FlagName, "", "Specify a name for the product the merged SBOM describes.")
+	flagSet.String(FlagVersion, "", "Specify a version for the product the merged SBOM describes.")
+	flagSet.StringP(FlagFormat, "f", "", "Specify the SBOM output format. (cyclonedx1.4+json, cyclonedx1.4+xml, spdx2.3+json, spdx3.0+json) "+
+		"Separate multiple formats with a comma.")
+
+	return flagSet
+}
+
+func GetSBOMConvertFlagSet() *pflag.FlagSet {
+	flagSet := pflag.NewFlagSet("snyk-cli-extension-sbom-convert", pflag.ExitOnError)
+
+	flagSet.String(FlagFile, "", "Specify the CycloneDX JSON or XML, or SPDX 2.x JSON document to convert.")
+	flagSet.StringP(FlagFormat, "f", "", "Specify the SBOM format to convert to. (cyclonedx1.4+json, cyclonedx1.4+xml, spdx2.3+json, spdx3.0+json) "+
+		"Separate multiple formats with a comma.")
+
+	return flagSet
+}
+
+func GetSBOMCheckFlagSet() *pflag.FlagSet {
+	flagSet := pflag.NewFlagSet("snyk-cli-extension-sbom-check", pflag.ExitOnError)
+
+	flagSet.String(FlagFile, "", "Specify the SBOM file to check for the NTIA minimum elements.")
+	flagSet.Int(FlagThreshold, DefaultCheckThreshold, "Specify the minimum score (0-100) the SBOM must reach.")
+	flagSet.Bool(FlagJSON, false, "Print the check result as JSON.")
+
+	return flagSet
+}
+
+func GetSBOMQualityFlagSet() *pflag.FlagSet {
+	flagSet := pflag.NewFlagSet("snyk-cli-extension-sbom-quality", pflag.ExitOnError)
+
+	flagSet.String(FlagFile, "", "Specify the SBOM file to score.")
+	flagSet.Bool(FlagJSON, false, "Print the quality score report as JSON.")
+
+	return flagSet
+}
+
+func GetSBOMDiffFlagSet() *pflag.FlagSet {
+	flagSet := pflag.NewFlagSet("snyk-cli-extension-sbom-diff", pflag.ExitOnError)
+
+	flagSet.StringSlice(FlagFile, nil, "Specify the two SBOM files to compare, the baseline first. Repeat the flag or separate files with a comma.")
+	flagSet.Bool(FlagJSON, false, "Print the differences as JSON.")
+	flagSet.Bool(FlagFailOnDiff, false, "Exit with a non-zero exit code if components were added, removed or changed.")
 
 	return flagSet
 }
@@ -121,6 +121,71 @@ func TestGetSBOMExportFlagSet(t *testing.T) {
 			isBool:   true,
 			expected: false,
 		},
+		{
+			flagName: FlagOffline,
+			isBool:   true,
+			expected: false,
+		},
+		{
+			flagName: FlagOutputFile,
+			isBool:   false,
+			expected: "",
+		},
+		{
+			flagName: FlagOutputDir,
+			isBool:   false,
+			expected: "",
+		},
+		{
+			flagName: FlagSplitProjects,
+			isBool:   true,
+			expected: false,
+		},
+		{
+			flagName: FlagSaveDepGraphs,
+			isBool:   false,
+			expected: "",
+		},
+		{
+			flagName: FlagReproducible,
+			isBool:   true,
+			expected: false,
+		},
+		{
+			flagName: FlagSupplier,
+			isBool:   false,
+			expected: "",
+		},
+		{
+			flagName: FlagAuthor,
+			isBool:   false,
+			expected: "",
+		},
+		{
+			flagName: FlagManufacturer,
+			isBool:   false,
+			expected: "",
+		},
+		{
+			flagName: FlagLifecycle,
+			isBool:   false,
+			expected: "",
+		},
+		{
+			flagName: FlagCheck,
+			isBool:   true,
+			expected: false,
+		},
+		{
+			flagName: FlagQuality,
+			isBool:   true,
+			expected: false,
+		},
+		{
+			flagName: FlagQualityFile,
+			isBool:   false,
+			expected: "",
+		},
 	}
 
 	for _, tt := range tc {
@@ -184,6 +249,26 @@ func TestGetSBOMTestFlagSet(t *testing.T) {
 			isBool:   false,
 			expected: "",
 		},
+		{
+			flagName: FlagQuality,
+			isBool:   true,
+			expected: false,
+		},
+		{
+			flagName: FlagQualityFile,
+			isBool:   false,
+			expected: "",
+		},
+		{
+			flagName: FlagConcurrency,
+			isInt:    true,
+			expected: DefaultConcurrency,
+		},
+		{
+			flagName: FlagMaxUploadSize,
+			isInt:    true,
+			expected: DefaultMaxUploadSize,
+		},
 		{
 			flagName: FlagReport,
 			isBool:   true,
@@ -260,3 +345,88 @@ func TestGetSBOMTestFlagSet(t *testing.T) {
 		})
 	}
 }
+
+func TestGetSBOMValidateFlagSet(t *testing.T) {
+	flagSet := GetSBOMValidateFlagSet()
+
+	val, err := flagSet.GetString(FlagFile)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", val)
+}
+
+func TestGetSBOMMergeFlagSet(t *testing.T) {
+	flagSet := GetSBOMMergeFlagSet()
+
+	files, err := flagSet.GetStringSlice(FlagFile)
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+
+	for _, flagName := range []string{FlagName, FlagVersion, FlagFormat} {
+		val, err := flagSet.GetString(flagName)
+		assert.NoError(t, err)
+		assert.Equal(t, "", val)
+	}
+
+	assert.NoError(t, flagSet.Parse([]string{"--file", "a.json", "--file", "b.json,c.json"}))
+	files, err = flagSet.GetStringSlice(FlagFile)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.json", "b.json", "c.json"}, files)
+}
+
+func TestGetSBOMConvertFlagSet(t *testing.T) {
+	flagSet := GetSBOMConvertFlagSet()
+
+	for _, flagName := range []string{FlagFile, FlagFormat} {
+		val, err := flagSet.GetString(flagName)
+		assert.NoError(t, err)
+		assert.Equal(t, "", val)
+	}
+
+	assert.NoError(t, flagSet.Parse([]string{"--file", "bom.json", "-f", "spdx2.3+json"}))
+	format, err := flagSet.GetString(FlagFormat)
+	assert.NoError(t, err)
+	assert.Equal(t, "spdx2.3+json", format)
+}
+
+func TestGetSBOMDiffFlagSet(t *testing.T) {
+	flagSet := GetSBOMDiffFlagSet()
+
+	files, err := flagSet.GetStringSlice(FlagFile)
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+
+	for _, flagName := range []string{FlagJSON, FlagFailOnDiff} {
+		val, err := flagSet.GetBool(flagName)
+		assert.NoError(t, err)
+		assert.False(t, val)
+	}
+}
+
+func TestGetSBOMCheckFlagSet(t *testing.T) {
+	flagSet := GetSBOMCheckFlagSet()
+
+	file, err := flagSet.GetString(FlagFile)
+	assert.NoError(t, err)
+	assert.Empty(t, file)
+
+	threshold, err := flagSet.GetInt(FlagThreshold)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, threshold)
+
+	jsonOutput, err := flagSet.GetBool(FlagJSON)
+	assert.NoError(t, err)
+	assert.False(t, jsonOutput)
+}
+
+func TestGetSBOMQualityFlagSet(t *testing.T) {
+	flagSet := GetSBOMQualityFlagSet()
+
+	file, err := flagSet.GetString(FlagFile)
+	assert.NoError(t, err)
+	assert.Empty(t, file)
+
+	jsonOutput, err := flagSet.GetBool(FlagJSON)
+	assert.NoError(t, err)
+	assert.False(t, jsonOutput)
+}
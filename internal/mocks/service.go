@@ -3,6 +3,7 @@ package mocks
 import (
 	"net/http"
 	"net/http/httptest"
+	"sync"
 )
 
 type MockResponse struct {
@@ -20,8 +21,13 @@ func NewMockSBOMService(resp MockResponse, assertions ...func(r *http.Request))
 
 func NewMockSBOMServiceMultiResponse(responses []MockResponse, assertions ...func(r *http.Request)) *httptest.Server {
 	var responseIndex int
+	var mu sync.Mutex
 
+	// Responses are served in the order requests arrive in.
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
 		if responseIndex >= len(responses) {
 			panic("unexpected request")
 		}
@@ -5,16 +5,25 @@ import (
 	"path/filepath"
 )
 
-func LoadPolicyFile(policyPath, sbomFilePath string) []byte {
-	var policy []byte
-	var policyFilePath string
+// stdinFilename is the SBOM file name that stands for the standard input.
+const stdinFilename = "-"
+
+// FilePath returns the path of the policy file that applies to the given SBOM
+// file: the given policy path, or else the .snyk file next to the SBOM file.
+// SBOMs read from the standard input have no location, so the .snyk file in
+// the working directory applies to them.
+func FilePath(policyPath, sbomFilePath string) string {
 	if policyPath != "" {
-		policyFilePath = policyPath
-	} else {
-		policyFilePath = filepath.Join(filepath.Dir(sbomFilePath), ".snyk")
+		return policyPath
+	}
+	if sbomFilePath == stdinFilename {
+		return ".snyk"
 	}
+	return filepath.Join(filepath.Dir(sbomFilePath), ".snyk")
+}
 
-	policy, err := os.ReadFile(policyFilePath) //nolint:gosec // G304 - path is user-provided input, intentional
+func LoadPolicyFile(policyPath, sbomFilePath string) []byte {
+	policy, err := os.ReadFile(FilePath(policyPath, sbomFilePath)) //nolint:gosec // G304 - path is user-provided input, intentional
 	if err != nil {
 		return nil
 	}
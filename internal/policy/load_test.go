@@ -1,6 +1,7 @@
 package policy_test
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -27,3 +28,16 @@ func TestLoadPolicyFile_FromSbomPath_Invalid(t *testing.T) {
 	policyBytes := policy.LoadPolicyFile("", "NOT A PATH")
 	assert.Equal(t, string(policyBytes), "")
 }
+
+func TestLoadPolicyFile_FromStdin_WorkingDirectory(t *testing.T) {
+	t.Chdir("./testdata")
+
+	policyBytes := policy.LoadPolicyFile("", "-")
+	assert.Equal(t, string(policyBytes), "Foo:\n  Bar\n")
+}
+
+func TestFilePath(t *testing.T) {
+	assert.Equal(t, "custom/.snyk", policy.FilePath("custom/.snyk", "sboms/bom.json"))
+	assert.Equal(t, filepath.Join("sboms", ".snyk"), policy.FilePath("", "sboms/bom.json"))
+	assert.Equal(t, ".snyk", policy.FilePath("", "-"))
+}
@@ -0,0 +1,263 @@
+package sbom
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/bom/cyclonedx"
+	errs "github.com/snyk/cli-extension-sbom/internal/errors"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zipMagic  = []byte("PK\x03\x04")
+	// tarMagic is found at tarMagicOffset of a POSIX or GNU tar archive.
+	tarMagic = []byte("ustar")
+)
+
+const tarMagicOffset = 257
+
+// errSizeExceedsLimit is returned by readLimited when the content read exceeds
+// the limit.
+var errSizeExceedsLimit = errors.New("size exceeds limit")
+
+// SBOMFile is an SBOM document read by ReadSBOMFiles, normalised to JSON.
+type SBOMFile struct {
+	// Name is the name of the file, or for documents extracted from an
+	// archive, that of the archive joined with the entry's. Documents read
+	// from the standard input are named "stdin".
+	Name string
+	// Path is the file the document was read from, or StdinFilename.
+	Path string
+	// Content is the JSON representation of the document.
+	Content []byte
+	// Encoding is the encoding the document was read in.
+	Encoding string
+	// Dropped reports the fields of a CycloneDX XML document that are lost
+	// normalising it to JSON.
+	Dropped []bom.Warning
+	// InMemory is set for documents that are not found as such on disk, as
+	// they were decompressed, extracted from an archive or read from the
+	// standard input.
+	InMemory bool
+	// Streamed is set for JSON documents too large to be read into memory,
+	// which are read from Path by Open instead. Content is then nil.
+	Streamed bool
+}
+
+// Open returns a reader of the JSON representation of the document, read
+// from its file if it is streamed.
+func (f *SBOMFile) Open() (io.ReadCloser, error) {
+	if f.Streamed {
+		return os.Open(f.Path) //nolint:gosec // G304 - filename is user-provided input, intentional
+	}
+	return io.NopCloser(bytes.NewReader(f.Content)), nil
+}
+
+// StreamSBOMFiles is like ReadSBOMFiles, except that a JSON document on disk
+// larger than the size limit is not rejected but streamed: it is neither read
+// nor normalised, and is left to the reader of Open to validate.
+func StreamSBOMFiles(filename string, sizeLimit int64, errFactory *errs.ErrorFactory) ([]*SBOMFile, error) {
+	if isLargeJSONFile(filename, sizeLimit) {
+		return []*SBOMFile{{Name: filename, Path: filename, Encoding: EncodingJSON, Streamed: true}}, nil
+	}
+	return ReadSBOMFiles(filename, sizeLimit, errFactory)
+}
+
+// isLargeJSONFile reports whether the file is a JSON document larger than the
+// size limit, judging by its first bytes. Files that cannot be read are left
+// to ReadSBOMFiles to report.
+func isLargeJSONFile(filename string, sizeLimit int64) bool {
+	if filename == StdinFilename {
+		return false
+	}
+	info, err := os.Stat(filename)
+	if err != nil || !info.Mode().IsRegular() || info.Size() <= sizeLimit {
+		return false
+	}
+
+	fd, err := os.Open(filename) //nolint:gosec // G304 - filename is user-provided input, intentional
+	if err != nil {
+		return false
+	}
+	defer fd.Close() //nolint:errcheck // Read-only file, nothing to flush
+
+	head := make([]byte, tarMagicOffset+len(tarMagic))
+	n, err := io.ReadFull(fd, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return false
+	}
+	head = head[:n]
+	return isJSONLike(head) && !isTar(head)
+}
+
+// ReadSBOMFiles reads the SBOM documents in the given file, normalised to JSON
+// as by NormalizeSBOM. Gzip-compressed files hold a single document, or are a
+// tar archive of documents, as are zip archives. Archive entries that are not
+// SBOM documents are skipped. The size limit, usually FileSizeLimit, applies
+// to both the size of the file and the total size of its decompressed content.
+func ReadSBOMFiles(filename string, sizeLimit int64, errFactory *errs.ErrorFactory) ([]*SBOMFile, error) {
+	b, err := readFile(filename, sizeLimit, errFactory)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, archived, err := extract(b, sizeLimit)
+	switch {
+	case errors.Is(err, errSizeExceedsLimit):
+		return nil, errFactory.NewDecompressedSizeExceedsLimitError(sizeLimit)
+	case err != nil:
+		return nil, errFactory.NewFailedToReadFileError(err)
+	}
+
+	name := filename
+	if filename == StdinFilename {
+		name = stdinName
+	}
+	inMemory := archived || filename == StdinFilename || bytes.HasPrefix(b, gzipMagic)
+	files := make([]*SBOMFile, 0, len(entries))
+	for _, e := range entries {
+		f := &SBOMFile{Name: name, Path: filename, InMemory: inMemory}
+		if archived {
+			if detectEncoding(e.content) == "" {
+				continue
+			}
+			f.Name = filepath.Join(name, filepath.FromSlash(e.name))
+		}
+		f.Content, f.Encoding, err = NormalizeSBOM(e.content, errFactory)
+		if err != nil {
+			return nil, err
+		}
+		if f.Encoding == EncodingCycloneDXXML {
+			f.Dropped = cyclonedx.DroppedXMLFields(e.content)
+		}
+		files = append(files, f)
+	}
+
+	if len(files) == 0 {
+		return nil, errFactory.NewNoSBOMInArchiveError()
+	}
+	return files, nil
+}
+
+type archiveEntry struct {
+	name    string
+	content []byte
+}
+
+// extract returns the content of b, decompressed if it is gzip-compressed,
+// or the entries of b if it is an archive, along with whether it is. The
+// decompressed content must fit the size limit.
+func extract(b []byte, sizeLimit int64) (entries []archiveEntry, archived bool, err error) {
+	if bytes.HasPrefix(b, zipMagic) {
+		entries, err = extractZip(b, sizeLimit)
+		return entries, true, err
+	}
+
+	if bytes.HasPrefix(b, gzipMagic) {
+		var zr *gzip.Reader
+		if zr, err = gzip.NewReader(bytes.NewReader(b)); err != nil {
+			return nil, false, err
+		}
+		if b, err = readLimited(zr, sizeLimit); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if isTar(b) {
+		entries, err = extractTar(b)
+		return entries, true, err
+	}
+	return []archiveEntry{{content: b}}, false, nil
+}
+
+func isTar(b []byte) bool {
+	return len(b) >= tarMagicOffset+len(tarMagic) && bytes.Equal(b[tarMagicOffset:tarMagicOffset+len(tarMagic)], tarMagic)
+}
+
+// extractZip returns the regular files of a zip archive. The sizes recorded
+// in the archive are not trusted, the limit is enforced while reading.
+func extractZip(b []byte, sizeLimit int64) ([]archiveEntry, error) {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []archiveEntry
+	remaining := sizeLimit
+	for _, f := range zr.File {
+		if !f.Mode().IsRegular() || isHidden(f.Name) {
+			continue
+		}
+		content, err := readZipEntry(f, remaining)
+		if err != nil {
+			return nil, err
+		}
+		remaining -= int64(len(content))
+		entries = append(entries, archiveEntry{name: f.Name, content: content})
+	}
+	return entries, nil
+}
+
+func readZipEntry(f *zip.File, limit int64) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	defer rc.Close() //nolint:errcheck // Read-only entry, nothing to flush
+
+	return readLimited(rc, limit)
+}
+
+// extractTar returns the regular files of a tar archive, which has already
+// been decompressed within the limit.
+func extractTar(b []byte) ([]archiveEntry, error) {
+	tr := tar.NewReader(bytes.NewReader(b))
+
+	var entries []archiveEntry
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if h.Typeflag != tar.TypeReg || isHidden(h.Name) {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", h.Name, err)
+		}
+		entries = append(entries, archiveEntry{name: h.Name, content: content})
+	}
+}
+
+// isHidden reports whether an archive entry is a hidden file, such as the
+// resource forks macOS adds to zip archives.
+func isHidden(name string) bool {
+	return strings.HasPrefix(path.Base(name), ".") || strings.HasPrefix(name, "__MACOSX/")
+}
+
+// readLimited reads r, failing once more than limit bytes were read.
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	b, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > limit {
+		return nil, errSizeExceedsLimit
+	}
+	return b, nil
+}
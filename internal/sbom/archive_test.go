@@ -0,0 +1,262 @@
+package sbom_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	snyk_errors "github.com/snyk/error-catalog-golang-public/snyk_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/sbom"
+)
+
+func TestReadSBOMFiles_Plain(t *testing.T) {
+	files, err := sbom.ReadSBOMFiles("testdata/bom.json", sbom.FileSizeLimit, errFactory)
+
+	require.NoError(t, err)
+	assert.Equal(t, []*sbom.SBOMFile{{
+		Name:     "testdata/bom.json",
+		Path:     "testdata/bom.json",
+		Content:  []byte(sbomJson),
+		Encoding: sbom.EncodingJSON,
+	}}, files)
+}
+
+func TestReadSBOMFiles_Gzip(t *testing.T) {
+	filename := writeTestFile(t, "bom.xml.gz", gzipBytes(t, readTestFile(t, "testdata/bom.xml")))
+
+	files, err := sbom.ReadSBOMFiles(filename, sbom.FileSizeLimit, errFactory)
+
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, filename, files[0].Name)
+	assert.Equal(t, sbom.EncodingCycloneDXXML, files[0].Encoding)
+	assert.True(t, files[0].InMemory)
+	assert.True(t, sbom.IsSBOMJSON(files[0].Content))
+}
+
+func TestReadSBOMFiles_XMLDroppedFields(t *testing.T) {
+	filename := writeTestFile(t, "bom.xml", []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.5" version="1">
+  <components>
+    <component type="library"><group>org.example</group><name>express</name><version>4.18.2</version></component>
+  </components>
+</bom>`))
+
+	files, err := sbom.ReadSBOMFiles(filename, sbom.FileSizeLimit, errFactory)
+
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, []bom.Warning{{Msg: "field components[].group is not supported and was dropped"}}, files[0].Dropped)
+}
+
+func TestReadSBOMFiles_TarGzip(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range []struct{ name, content string }{
+		{"sboms/bom.json", sbomJson},
+		{"sboms/README.md", "# SBOMs\n"},
+		{"sboms/bom.spdx", string(readTestFile(t, "testdata/bom.spdx"))},
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: e.name, Mode: 0o644, Size: int64(len(e.content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(e.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	filename := writeTestFile(t, "sboms.tar.gz", gzipBytes(t, buf.Bytes()))
+
+	files, err := sbom.ReadSBOMFiles(filename, sbom.FileSizeLimit, errFactory)
+
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, filepath.Join(filename, "sboms", "bom.json"), files[0].Name)
+	assert.Equal(t, sbom.EncodingJSON, files[0].Encoding)
+	assert.Equal(t, sbomJson, string(files[0].Content))
+	assert.Equal(t, filepath.Join(filename, "sboms", "bom.spdx"), files[1].Name)
+	assert.Equal(t, sbom.EncodingSPDXTagValue, files[1].Encoding)
+	assert.True(t, files[1].InMemory)
+}
+
+func TestReadSBOMFiles_Zip(t *testing.T) {
+	filename := writeTestFile(t, "sboms.zip", zipBytes(t, map[string]string{
+		"bom.json":            sbomJson,
+		"bom.xml":             string(readTestFile(t, "testdata/bom.xml")),
+		"__MACOSX/._bom.json": "\x00\x05\x16\x07",
+	}))
+
+	files, err := sbom.ReadSBOMFiles(filename, sbom.FileSizeLimit, errFactory)
+
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, filepath.Join(filename, "bom.json"), files[0].Name)
+	assert.Equal(t, filepath.Join(filename, "bom.xml"), files[1].Name)
+	assert.Equal(t, sbom.EncodingCycloneDXXML, files[1].Encoding)
+}
+
+func TestReadSBOMFiles_NoSBOMInArchive(t *testing.T) {
+	filename := writeTestFile(t, "docs.zip", zipBytes(t, map[string]string{"README.md": "# SBOMs\n"}))
+
+	_, err := sbom.ReadSBOMFiles(filename, sbom.FileSizeLimit, errFactory)
+
+	var snykErr snyk_errors.Error
+	require.True(t, errors.As(err, &snykErr))
+	assert.Equal(t, "The archive provided by the `--file` flag does not contain any JSON, CycloneDX XML or SPDX tag-value document.", snykErr.Detail)
+}
+
+func TestReadSBOMFiles_InvalidEntry(t *testing.T) {
+	filename := writeTestFile(t, "sboms.zip", zipBytes(t, map[string]string{"bom.json": `{"bomFormat":`}))
+
+	_, err := sbom.ReadSBOMFiles(filename, sbom.FileSizeLimit, errFactory)
+
+	var snykErr snyk_errors.Error
+	require.True(t, errors.As(err, &snykErr))
+	assert.Equal(t, "The file provided by the `--file` flag is not valid JSON.", snykErr.Detail)
+}
+
+func TestReadSBOMFiles_DecompressedSizeExceedsLimit(t *testing.T) {
+	const limit = 1000
+	large := make([]byte, limit+1)
+
+	for name, content := range map[string][]byte{
+		"bom.json.gz": gzipBytes(t, large),
+		"sboms.zip":   zipBytes(t, map[string]string{"a.json": string(large[:limit/2+1]), "b.json": string(large[:limit/2])}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			filename := writeTestFile(t, name, content)
+			require.Less(t, len(content), limit)
+
+			_, err := sbom.ReadSBOMFiles(filename, limit, errFactory)
+
+			var snykErr snyk_errors.Error
+			require.True(t, errors.As(err, &snykErr))
+			assert.Equal(t, "The content of the provided file is too large once decompressed. The maximum supported size is 1000 bytes.", snykErr.Detail)
+		})
+	}
+}
+
+func TestStreamSBOMFiles_LargeJSONFile(t *testing.T) {
+	filename := writeTestFile(t, "bom.json", []byte(sbomJson))
+
+	files, err := sbom.StreamSBOMFiles(filename, int64(len(sbomJson)-1), errFactory)
+
+	require.NoError(t, err)
+	assert.Equal(t, []*sbom.SBOMFile{{
+		Name:     filename,
+		Path:     filename,
+		Encoding: sbom.EncodingJSON,
+		Streamed: true,
+	}}, files)
+
+	r, err := files[0].Open()
+	require.NoError(t, err)
+	defer r.Close() //nolint:errcheck // Read-only file, nothing to flush
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, sbomJson, string(b))
+}
+
+func TestStreamSBOMFiles_LargeCompressedFile(t *testing.T) {
+	content := gzipBytes(t, []byte(sbomJson))
+	filename := writeTestFile(t, "bom.json.gz", content)
+
+	_, err := sbom.StreamSBOMFiles(filename, int64(len(content)-1), errFactory)
+
+	var snykErr snyk_errors.Error
+	require.True(t, errors.As(err, &snykErr))
+	assert.Contains(t, snykErr.Detail, "The provided file is too large.")
+}
+
+func TestReadSBOMFile_MultipleSBOMsInArchive(t *testing.T) {
+	filename := writeTestFile(t, "sboms.zip", zipBytes(t, map[string]string{"a.json": sbomJson, "b.json": sbomJson}))
+
+	_, err := sbom.ReadSBOMFile(filename, errFactory)
+
+	var snykErr snyk_errors.Error
+	require.True(t, errors.As(err, &snykErr))
+	assert.Equal(t, "The archive provided by the `--file` flag contains 2 SBOM documents, but this command reads a single one.", snykErr.Detail)
+}
+
+func readTestFile(t *testing.T, filename string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	return b
+}
+
+func writeTestFile(t *testing.T, name string, b []byte) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(filename, b, 0o600))
+	return filename
+}
+
+func gzipBytes(t *testing.T, b []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write(b)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+// zipBytes returns a zip archive of the given entries, in the order of their
+// names.
+func zipBytes(t *testing.T, entries map[string]string) []byte {
+	t.Helper()
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range names {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(entries[name]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestReadSBOMFiles_Stdin(t *testing.T) {
+	setStdin(t, gzipBytes(t, []byte(sbomJson)))
+
+	files, err := sbom.ReadSBOMFiles(sbom.StdinFilename, sbom.FileSizeLimit, errFactory)
+
+	require.NoError(t, err)
+	assert.Equal(t, []*sbom.SBOMFile{{
+		Name:     "stdin",
+		Path:     sbom.StdinFilename,
+		Content:  []byte(sbomJson),
+		Encoding: sbom.EncodingJSON,
+		InMemory: true,
+	}}, files)
+}
+
+// setStdin replaces the standard input with the given content for the
+// duration of the test.
+func setStdin(t *testing.T, b []byte) {
+	t.Helper()
+	f, err := os.Open(writeTestFile(t, "stdin", b))
+	require.NoError(t, err)
+	stdin := os.Stdin
+	os.Stdin = f
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		f.Close() //nolint:errcheck // Read-only file
+	})
+}
@@ -0,0 +1,45 @@
+package sbom
+
+import (
+	"encoding/json"
+
+	"github.com/snyk/go-application-framework/pkg/local_workflows/content_type"
+	"github.com/snyk/go-application-framework/pkg/local_workflows/json_schemas"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+// CheckSummary reports the failed NTIA element checks of the documents at
+// path. The checks of documents whose score is below threshold are reported
+// as open findings, so that the CLI exits with a non-zero exit code.
+func CheckSummary(path string, threshold int, results ...*bom.NTIAResult) (data []byte, contentType string, err error) {
+	var failed, open int
+	for _, r := range results {
+		var n int
+		for _, e := range r.Elements {
+			n += e.Total - e.Present
+		}
+		failed += n
+		if r.Score() < threshold {
+			open += n
+		}
+	}
+
+	summary := json_schemas.TestSummary{
+		Type:      "sbom-check",
+		Path:      path,
+		Artifacts: len(results),
+		Results: []json_schemas.TestSummaryResult{
+			{
+				Severity: "low",
+				Total:    failed,
+				Open:     open,
+			},
+		},
+	}
+	data, err = json.Marshal(summary)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, content_type.TEST_SUMMARY, nil
+}
@@ -0,0 +1,113 @@
+package sbom
+
+import (
+	stderr "errors"
+	"fmt"
+	"strings"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/bom/cyclonedx"
+	"github.com/snyk/cli-extension-sbom/internal/bom/spdx"
+	"github.com/snyk/cli-extension-sbom/internal/errors"
+)
+
+// Decode detects the format of an SBOM document and reads it into a bom
+// document. CycloneDX JSON and XML documents of any spec version and SPDX 2.x
+// JSON documents can be read.
+func Decode(b []byte) (*bom.Document, Format, error) {
+	if specVersion, ok := cyclonedx.XMLSpecVersion(b); ok {
+		format := Format{Standard: StandardCycloneDX, SpecVersion: specVersion}
+		doc, err := cyclonedx.Decode(b)
+		return doc, format, err
+	}
+
+	format, err := DetectFormat(b)
+	if err != nil {
+		return nil, format, err
+	}
+
+	var doc *bom.Document
+	switch {
+	case format.Standard == StandardCycloneDX:
+		doc, err = cyclonedx.Decode(b)
+	case strings.HasPrefix(format.SpecVersion, "2."):
+		doc, err = spdx.Decode(b)
+	default:
+		return nil, format, fmt.Errorf("%w: %s", ErrUnsupportedSpecVersion, format)
+	}
+	if err != nil {
+		return nil, format, err
+	}
+
+	return doc, format, nil
+}
+
+// ReadDocument reads and decodes the SBOM document in the given file, which may
+// be compressed or an archive of a single document, as read by ReadDocuments.
+func ReadDocument(filename string, errFactory *errors.ErrorFactory) (*bom.Document, error) {
+	docs, err := ReadDocuments(filename, errFactory)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) > 1 {
+		return nil, errFactory.NewMultipleSBOMsInArchiveError(len(docs))
+	}
+	return docs[0], nil
+}
+
+// ReadDocumentWithWarnings reads and decodes the SBOM document in the given
+// file, and also reports the fields of the document that are not decoded and
+// therefore dropped. Unlike ReadDocument, it only reads plain CycloneDX and
+// SPDX JSON and CycloneDX XML files, as the fields are told from the file as
+// it is.
+func ReadDocumentWithWarnings(filename string, errFactory *errors.ErrorFactory) (*bom.Document, []bom.Warning, error) {
+	b, err := readFile(filename, FileSizeLimit, errFactory)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	doc, format, err := Decode(b)
+	if err != nil {
+		return nil, nil, decodeError(err, filename, format, errFactory)
+	}
+
+	var warnings []bom.Warning
+	if format.Standard == StandardCycloneDX {
+		warnings = cyclonedx.DroppedFields(b)
+	}
+
+	return doc, warnings, nil
+}
+
+// ReadDocuments reads and decodes the SBOM documents in the given file, which
+// may be compressed or an archive of documents, as read by ReadSBOMFiles.
+func ReadDocuments(filename string, errFactory *errors.ErrorFactory) ([]*bom.Document, error) {
+	files, err := ReadSBOMFiles(filename, FileSizeLimit, errFactory)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]*bom.Document, 0, len(files))
+	for _, f := range files {
+		doc, format, err := Decode(f.Content)
+		if err != nil {
+			return nil, decodeError(err, f.Name, format, errFactory)
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
+
+// decodeError returns the error reported to the user for a document in the
+// given file that Decode failed to read.
+func decodeError(err error, filename string, format Format, errFactory *errors.ErrorFactory) error {
+	switch {
+	case stderr.Is(err, ErrUnknownFormat):
+		return errFactory.NewUnknownSBOMInputError(filename)
+	case stderr.Is(err, ErrUnsupportedSpecVersion):
+		return errFactory.NewUnsupportedSBOMInputError(filename, format.String())
+	default:
+		return errFactory.NewFailedToDecodeSBOMError(err, filename)
+	}
+}
@@ -0,0 +1,72 @@
+package sbom_test
+
+import (
+	"errors"
+	"testing"
+
+	snyk_errors "github.com/snyk/error-catalog-golang-public/snyk_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom/cyclonedx"
+	"github.com/snyk/cli-extension-sbom/internal/bom/spdx"
+	"github.com/snyk/cli-extension-sbom/internal/sbom"
+)
+
+func TestDecode(t *testing.T) {
+	doc, format, err := sbom.Decode([]byte(sbomJson))
+
+	require.NoError(t, err)
+	assert.Equal(t, sbom.Format{Standard: "CycloneDX", SpecVersion: "1.4"}, format)
+	assert.Equal(t, "gopkg.in/yaml.v2", doc.Metadata.Component.Name)
+
+	b, _, err := spdx.Encode(newTestDocument(), "2.3", "json")
+	require.NoError(t, err)
+	doc, format, err = sbom.Decode(b)
+
+	require.NoError(t, err)
+	assert.Equal(t, sbom.Format{Standard: "SPDX", SpecVersion: "2.3"}, format)
+	assert.Equal(t, "goof", doc.Metadata.Component.Name)
+}
+
+func TestDecode_XML(t *testing.T) {
+	b, _, err := cyclonedx.Encode(newTestDocument(), "1.5", "xml")
+	require.NoError(t, err)
+
+	doc, format, err := sbom.Decode(b)
+
+	require.NoError(t, err)
+	assert.Equal(t, sbom.Format{Standard: "CycloneDX", SpecVersion: "1.5"}, format)
+	assert.Equal(t, "goof", doc.Metadata.Component.Name)
+}
+
+func TestDecode_Unsupported(t *testing.T) {
+	_, _, err := sbom.Decode([]byte(`{"foo":"bar"}`))
+	assert.ErrorIs(t, err, sbom.ErrUnknownFormat)
+
+	_, format, err := sbom.Decode([]byte(`{"spdxVersion":"SPDX-3.0"}`))
+	assert.ErrorIs(t, err, sbom.ErrUnsupportedSpecVersion)
+	assert.Equal(t, sbom.Format{Standard: "SPDX", SpecVersion: "3.0"}, format)
+}
+
+func TestReadDocument_GzipTagValue(t *testing.T) {
+	filename := writeTestFile(t, "bom.spdx.gz", gzipBytes(t, readTestFile(t, "testdata/bom.spdx")))
+
+	doc, err := sbom.ReadDocument(filename, errFactory)
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, doc.Components)
+}
+
+func TestReadDocument_MultipleDocuments(t *testing.T) {
+	filename := writeTestFile(t, "sboms.zip", zipBytes(t, map[string]string{
+		"a.json": sbomJson,
+		"b.json": sbomJson,
+	}))
+
+	_, err := sbom.ReadDocument(filename, errFactory)
+
+	var snykErr snyk_errors.Error
+	require.True(t, errors.As(err, &snykErr))
+	assert.Contains(t, snykErr.Detail, "contains 2 SBOM documents, but this command reads a single one")
+}
@@ -0,0 +1,80 @@
+package sbom
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/snyk/cli-extension-sbom/internal/errors"
+)
+
+// sbomFileExtensions are the extensions of the files ExpandFiles picks from
+// directories: those of SBOM documents, and of compressed files and archives.
+var sbomFileExtensions = []string{".json", ".xml", ".spdx", ".gz", ".tgz", ".zip"}
+
+// ExpandFiles returns the SBOM files a `--file` flag value designates: the
+// file itself, the SBOM files in a directory, or the files matching a glob
+// pattern, in lexical order. The SBOM files in a directory are told by their
+// extension, and subdirectories are not searched.
+func ExpandFiles(pattern string, errFactory *errors.ErrorFactory) ([]string, error) {
+	if pattern == StdinFilename {
+		return []string{pattern}, nil
+	}
+
+	info, err := os.Stat(pattern)
+	switch {
+	case err == nil && info.IsDir():
+		return expandDir(pattern, errFactory)
+	case err != nil && isGlob(pattern):
+		return expandGlob(pattern, errFactory)
+	default:
+		// Errors are left to reading the file.
+		return []string{pattern}, nil
+	}
+}
+
+func expandDir(dir string, errFactory *errors.ErrorFactory) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errFactory.NewFailedToReadFileError(err)
+	}
+
+	var files []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && isSBOMFile(e.Name()) {
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+	if len(files) == 0 {
+		return nil, errFactory.NewNoSBOMFilesFoundError(dir)
+	}
+	return files, nil
+}
+
+func expandGlob(pattern string, errFactory *errors.ErrorFactory) ([]string, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, errFactory.NewInvalidFilePathError(err, pattern)
+	}
+
+	var files []string
+	for _, m := range matches {
+		if info, err := os.Stat(m); err == nil && info.Mode().IsRegular() {
+			files = append(files, m)
+		}
+	}
+	if len(files) == 0 {
+		return nil, errFactory.NewNoSBOMFilesFoundError(pattern)
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+func isSBOMFile(name string) bool {
+	return !strings.HasPrefix(name, ".") && slices.Contains(sbomFileExtensions, strings.ToLower(filepath.Ext(name)))
+}
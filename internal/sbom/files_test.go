@@ -0,0 +1,89 @@
+package sbom_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	snyk_errors "github.com/snyk/error-catalog-golang-public/snyk_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/sbom"
+)
+
+func TestExpandFiles_File(t *testing.T) {
+	files, err := sbom.ExpandFiles("testdata/bom.json", errFactory)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"testdata/bom.json"}, files)
+}
+
+func TestExpandFiles_Stdin(t *testing.T) {
+	files, err := sbom.ExpandFiles(sbom.StdinFilename, errFactory)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{sbom.StdinFilename}, files)
+}
+
+func TestExpandFiles_MissingFile(t *testing.T) {
+	files, err := sbom.ExpandFiles("testdata/missing.json", errFactory)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"testdata/missing.json"}, files)
+}
+
+func TestExpandFiles_Directory(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "b.json", "a.cdx.xml", "c.spdx", "d.tgz", "e.ZIP", "notes.txt", ".hidden.json")
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "nested.json"), 0o700))
+
+	files, err := sbom.ExpandFiles(dir, errFactory)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "a.cdx.xml"),
+		filepath.Join(dir, "b.json"),
+		filepath.Join(dir, "c.spdx"),
+		filepath.Join(dir, "d.tgz"),
+		filepath.Join(dir, "e.ZIP"),
+	}, files)
+}
+
+func TestExpandFiles_EmptyDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "notes.txt")
+
+	_, err := sbom.ExpandFiles(dir, errFactory)
+
+	var snykErr snyk_errors.Error
+	require.True(t, errors.As(err, &snykErr))
+	assert.Contains(t, snykErr.Detail, "No SBOM files were found")
+}
+
+func TestExpandFiles_Glob(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "b.json", "a.json", "c.spdx")
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "nested.json"), 0o700))
+
+	files, err := sbom.ExpandFiles(filepath.Join(dir, "*.json"), errFactory)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")}, files)
+}
+
+func TestExpandFiles_GlobNoMatch(t *testing.T) {
+	_, err := sbom.ExpandFiles(filepath.Join(t.TempDir(), "*.json"), errFactory)
+
+	var snykErr snyk_errors.Error
+	require.True(t, errors.As(err, &snykErr))
+	assert.Contains(t, snykErr.Detail, "No SBOM files were found")
+}
+
+func writeFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o600))
+	}
+}
@@ -1,15 +1,34 @@
 package sbom
 
 import (
+	"bytes"
 	"encoding/json"
+	stderr "errors"
 	"os"
 
+	"github.com/snyk/cli-extension-sbom/internal/bom/cyclonedx"
+	"github.com/snyk/cli-extension-sbom/internal/bom/spdx"
 	"github.com/snyk/cli-extension-sbom/internal/errors"
 )
 
 const (
-	// FileSizeLimit is the maximum supported file size (50 MB) for the file upload API in bytes.
+	// FileSizeLimit is the maximum size (50 MB) of the SBOM files that are read
+	// into memory, and of their decompressed content. Larger JSON documents can
+	// only be streamed, see StreamSBOMFiles, in which case the upload API limits
+	// their compressed size instead, see snykclient.DefaultUploadSizeLimit.
 	FileSizeLimit = 50_000_000
+
+	// StdinFilename is the file name that stands for the standard input.
+	StdinFilename = "-"
+	// stdinName names the documents read from the standard input.
+	stdinName = "stdin"
+)
+
+// Encodings of the SBOM documents ReadSBOMFile reads.
+const (
+	EncodingJSON         = "JSON"
+	EncodingCycloneDXXML = "CycloneDX XML"
+	EncodingSPDXTagValue = "SPDX tag-value"
 )
 
 func IsSBOMJSON(b []byte) bool {
@@ -18,7 +37,86 @@ func IsSBOMJSON(b []byte) bool {
 	return err == nil
 }
 
+// ReadSBOMFile reads the SBOM document in the given file, normalised to JSON
+// as by NormalizeSBOM.
 func ReadSBOMFile(filename string, errFactory *errors.ErrorFactory) ([]byte, error) {
+	b, _, err := ReadSBOMFileWithEncoding(filename, errFactory)
+	return b, err
+}
+
+// ReadSBOMFileWithEncoding is like ReadSBOMFile, and also returns the
+// encoding of the file.
+func ReadSBOMFileWithEncoding(filename string, errFactory *errors.ErrorFactory) (b []byte, encoding string, err error) {
+	files, err := ReadSBOMFiles(filename, FileSizeLimit, errFactory)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(files) > 1 {
+		return nil, "", errFactory.NewMultipleSBOMsInArchiveError(len(files))
+	}
+	return files[0].Content, files[0].Encoding, nil
+}
+
+// NormalizeSBOM returns the JSON representation of an SBOM document, along
+// with the encoding of the document. JSON documents are returned as they
+// are, CycloneDX XML documents are converted to CycloneDX JSON and SPDX
+// tag-value documents to SPDX JSON, of the same spec version.
+func NormalizeSBOM(b []byte, errFactory *errors.ErrorFactory) (normalized []byte, encoding string, err error) {
+	encoding = detectEncoding(b)
+	switch encoding {
+	case EncodingJSON:
+		if !IsSBOMJSON(b) {
+			return nil, "", errFactory.NewInvalidJSONError()
+		}
+		return b, EncodingJSON, nil
+	case EncodingCycloneDXXML:
+		normalized, err = cyclonedx.XMLToJSON(b)
+	case EncodingSPDXTagValue:
+		normalized, err = spdx.TagValueToJSON(b)
+	default:
+		return nil, "", errFactory.NewUnsupportedSBOMEncodingError()
+	}
+	if err != nil {
+		return nil, "", errFactory.NewInvalidSBOMError(encoding, []string{err.Error()})
+	}
+	return normalized, encoding, nil
+}
+
+// detectEncoding returns the encoding of an SBOM document, or an empty string
+// if it is none of the supported encodings. Documents that look like JSON are
+// reported as such, even if they are not valid JSON.
+func detectEncoding(b []byte) string {
+	switch {
+	case IsSBOMJSON(b) || isJSONLike(b):
+		return EncodingJSON
+	case isCycloneDXXML(b):
+		return EncodingCycloneDXXML
+	case spdx.IsTagValue(b):
+		return EncodingSPDXTagValue
+	default:
+		return ""
+	}
+}
+
+func isCycloneDXXML(b []byte) bool {
+	_, ok := cyclonedx.XMLSpecVersion(b)
+	return ok
+}
+
+// isJSONLike reports whether b looks like a JSON rather than an XML or
+// tag-value document.
+func isJSONLike(b []byte) bool {
+	b = bytes.TrimSpace(b)
+	return bytes.HasPrefix(b, []byte("{")) || bytes.HasPrefix(b, []byte("["))
+}
+
+// readFile reads the file, checking that it is a regular file within the
+// size limit. StdinFilename reads the standard input.
+func readFile(filename string, sizeLimit int64, errFactory *errors.ErrorFactory) ([]byte, error) {
+	if filename == StdinFilename {
+		return readStdin(sizeLimit, errFactory)
+	}
+
 	// Check if file exists
 	info, err := os.Stat(filename)
 	if err != nil {
@@ -34,8 +132,8 @@ func ReadSBOMFile(filename string, errFactory *errors.ErrorFactory) ([]byte, err
 	}
 
 	// Check if file size exceeds limit
-	if info.Size() > FileSizeLimit {
-		return nil, errFactory.NewFileSizeExceedsLimitError()
+	if info.Size() > sizeLimit {
+		return nil, errFactory.NewFileSizeExceedsLimitError(sizeLimit)
 	}
 
 	// Open file and read it
@@ -44,11 +142,16 @@ func ReadSBOMFile(filename string, errFactory *errors.ErrorFactory) ([]byte, err
 		return nil, errFactory.NewFailedToReadFileError(err)
 	}
 
-	isValidSBOM := IsSBOMJSON(b)
+	return b, nil
+}
 
-	if !isValidSBOM {
-		return nil, errFactory.NewInvalidJSONError()
+func readStdin(sizeLimit int64, errFactory *errors.ErrorFactory) ([]byte, error) {
+	b, err := readLimited(os.Stdin, sizeLimit)
+	switch {
+	case stderr.Is(err, errSizeExceedsLimit):
+		return nil, errFactory.NewFileSizeExceedsLimitError(sizeLimit)
+	case err != nil:
+		return nil, errFactory.NewFailedToReadFileError(err)
 	}
-
 	return b, nil
 }
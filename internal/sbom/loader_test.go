@@ -114,10 +114,8 @@ func TestReadSBOMFile_FileSizeExceedsLimit(t *testing.T) {
 	tmpFile, err := os.CreateTemp(t.TempDir(), "large-sbom-*.json")
 	require.NoError(t, err)
 
-	// Write data slightly larger than the limit
-	data := make([]byte, sbom.FileSizeLimit+1)
-	_, err = tmpFile.Write(data)
-	require.NoError(t, err)
+	// Grow the file slightly beyond the limit, without writing the data
+	require.NoError(t, tmpFile.Truncate(sbom.FileSizeLimit+1))
 	require.NoError(t, tmpFile.Close())
 
 	sbomContent, err := sbom.ReadSBOMFile(tmpFile.Name(), errFactory)
@@ -136,7 +134,7 @@ func TestReadSBOMFile_InvalidJSON(t *testing.T) {
 	require.NoError(t, err)
 
 	// Write invalid JSON content
-	_, err = tmpFile.WriteString("this is not valid json")
+	_, err = tmpFile.WriteString(`{"this is not": "valid json"`)
 	require.NoError(t, err)
 	require.NoError(t, tmpFile.Close())
 
@@ -156,3 +154,62 @@ func TestReadSBOMSuccessfully(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, sbomJson, string(sbomContent))
 }
+
+func TestReadSBOMFileWithEncoding_JSON(t *testing.T) {
+	sbomContent, encoding, err := sbom.ReadSBOMFileWithEncoding("testdata/bom.json", errFactory)
+
+	require.NoError(t, err)
+	require.Equal(t, sbom.EncodingJSON, encoding)
+	require.Equal(t, sbomJson, string(sbomContent))
+}
+
+func TestReadSBOMFile_UnsupportedEncoding(t *testing.T) {
+	tmpFile, err := os.CreateTemp(t.TempDir(), "unsupported-*.txt")
+	require.NoError(t, err)
+	_, err = tmpFile.WriteString("this is not an sbom")
+	require.NoError(t, err)
+	require.NoError(t, tmpFile.Close())
+
+	sbomContent, err := sbom.ReadSBOMFile(tmpFile.Name(), errFactory)
+
+	var snykErr snyk_errors.Error
+	require.True(t, errors.As(err, &snykErr))
+	require.Equal(t, "Invalid flag option", snykErr.Title)
+	require.Equal(t, "The file provided by the `--file` flag is neither a JSON, a CycloneDX XML nor an SPDX tag-value document.", snykErr.Detail)
+	require.Nil(t, sbomContent)
+}
+
+func TestReadSBOMFileWithEncoding_Converted(t *testing.T) {
+	testCases := []struct {
+		filename         string
+		expectedEncoding string
+		expectedFormat   sbom.Format
+	}{
+		{
+			filename:         "testdata/bom.xml",
+			expectedEncoding: sbom.EncodingCycloneDXXML,
+			expectedFormat:   sbom.Format{Standard: "CycloneDX", SpecVersion: "1.5"},
+		},
+		{
+			filename:         "testdata/bom.spdx",
+			expectedEncoding: sbom.EncodingSPDXTagValue,
+			expectedFormat:   sbom.Format{Standard: "SPDX", SpecVersion: "2.3"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.filename, func(t *testing.T) {
+			b, encoding, err := sbom.ReadSBOMFileWithEncoding(tc.filename, errFactory)
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedEncoding, encoding)
+			require.True(t, sbom.IsSBOMJSON(b))
+			doc, format, err := sbom.Decode(b)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedFormat, format)
+			require.Len(t, doc.Components, 1)
+			require.Equal(t, "gopkg.in/yaml.v2", doc.Components[0].Name)
+			require.NoError(t, sbom.CheckSchema(b, errFactory))
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package sbom
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	errs "github.com/snyk/cli-extension-sbom/internal/errors"
+)
+
+// QualitySpecCompliance is the quality category of documents that satisfy
+// the schema of their format.
+const QualitySpecCompliance = "spec compliance"
+
+const qualityReportPerm = 0o644
+
+type (
+	// QualityReport scores the quality of an SBOM document in each category,
+	// from 0 to 100.
+	QualityReport struct {
+		// Name identifies the document, e.g. by its file name.
+		Name   string `json:"name"`
+		Format Format `json:"format"`
+		// Score is the mean of the category scores, rounded down.
+		Score      int                `json:"score"`
+		Categories []*QualityCategory `json:"categories"`
+		// Violations counts the schema violations of the document.
+		Violations int `json:"violations"`
+	}
+
+	// QualityCategory counts the components, or for spec compliance the
+	// document, that satisfy a quality category.
+	QualityCategory struct {
+		Name    string `json:"name"`
+		Score   int    `json:"score"`
+		Present int    `json:"present"`
+		Total   int    `json:"total"`
+	}
+)
+
+// Quality scores the quality of an SBOM document. Spec compliance is scored
+// for the formats Validate can check, and left out of the report for others.
+func Quality(name string, b []byte) (*QualityReport, error) {
+	doc, format, err := Decode(b)
+	if err != nil {
+		return nil, err
+	}
+	return score(name, b, doc, format)
+}
+
+// ReadQualityReport reads the SBOM document in the given file and scores its
+// quality.
+func ReadQualityReport(filename string, errFactory *errs.ErrorFactory) (*QualityReport, error) {
+	b, err := readFile(filename, FileSizeLimit, errFactory)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, format, err := Decode(b)
+	if err != nil {
+		return nil, decodeError(err, filename, format, errFactory)
+	}
+
+	r, err := score(filename, b, doc, format)
+	if err != nil {
+		return nil, errFactory.NewFailedToValidateSBOMError(err)
+	}
+	return r, nil
+}
+
+func score(name string, b []byte, doc *bom.Document, format Format) (*QualityReport, error) {
+	scores := bom.ScoreComponents(doc)
+	r := &QualityReport{Name: name, Format: format, Categories: make([]*QualityCategory, 0, len(scores)+1)}
+	for _, s := range scores {
+		r.Categories = append(r.Categories, &QualityCategory{Name: s.Category, Score: s.Score(), Present: s.Present, Total: s.Total})
+	}
+
+	res, err := Validate(b)
+	switch {
+	case errors.Is(err, ErrUnknownFormat) || errors.Is(err, ErrUnsupportedSpecVersion):
+	case err != nil:
+		return nil, err
+	default:
+		c := &QualityCategory{Name: QualitySpecCompliance, Total: 1}
+		if res.Valid() {
+			c.Score, c.Present = 100, 1
+		}
+		r.Categories = append(r.Categories, c)
+		r.Violations = len(res.Violations)
+	}
+
+	var sum int
+	for _, c := range r.Categories {
+		sum += c.Score
+	}
+	r.Score = sum / len(r.Categories)
+
+	return r, nil
+}
+
+// WriteQualityReports writes the reports to the given file as a JSON array.
+func WriteQualityReports(filename string, reports []*QualityReport) error {
+	b, err := json.MarshalIndent(reports, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, b, qualityReportPerm)
+}
@@ -0,0 +1,75 @@
+package sbom_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/bom/cyclonedx"
+	"github.com/snyk/cli-extension-sbom/internal/sbom"
+)
+
+func TestQuality(t *testing.T) {
+	b, _, err := cyclonedx.Encode(newTestDocument(), "1.6", "json")
+	require.NoError(t, err)
+
+	r, err := sbom.Quality("bom.json", b)
+
+	require.NoError(t, err)
+	assert.Equal(t, &sbom.QualityReport{
+		Name:   "bom.json",
+		Format: sbom.Format{Standard: "CycloneDX", SpecVersion: "1.6"},
+		Score:  66,
+		Categories: []*sbom.QualityCategory{
+			{Name: bom.QualityIdentifiers, Score: 100, Present: 1, Total: 1},
+			{Name: bom.QualityLicenses, Score: 0, Present: 0, Total: 1},
+			{Name: bom.QualityHashes, Score: 0, Present: 0, Total: 1},
+			{Name: bom.QualityDependencies, Score: 100, Present: 1, Total: 1},
+			{Name: bom.QualityVersions, Score: 100, Present: 1, Total: 1},
+			{Name: sbom.QualitySpecCompliance, Score: 100, Present: 1, Total: 1},
+		},
+	}, r)
+}
+
+func TestQuality_SchemaViolations(t *testing.T) {
+	b, _, err := cyclonedx.Encode(newTestDocument(), "1.6", "json")
+	require.NoError(t, err)
+	b = bytes.ReplaceAll(b, []byte(`"library"`), []byte(`"bogus"`))
+
+	r, err := sbom.Quality("bom.json", b)
+
+	require.NoError(t, err)
+	assert.Equal(t, &sbom.QualityCategory{Name: sbom.QualitySpecCompliance, Score: 0, Present: 0, Total: 1}, r.Categories[5])
+	assert.Equal(t, 1, r.Violations)
+	assert.Equal(t, 50, r.Score)
+}
+
+func TestQuality_NoSchema(t *testing.T) {
+	b, _, err := cyclonedx.Encode(newTestDocument(), "1.6", "xml")
+	require.NoError(t, err)
+
+	r, err := sbom.Quality("bom.xml", b)
+
+	require.NoError(t, err)
+	assert.Len(t, r.Categories, len(bom.ComponentQualityCategories))
+	assert.Equal(t, 60, r.Score)
+}
+
+func TestWriteQualityReports(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "quality.json")
+	reports := []*sbom.QualityReport{{Name: "bom.json", Score: 83, Categories: []*sbom.QualityCategory{}}}
+
+	require.NoError(t, sbom.WriteQualityReports(filename, reports))
+
+	b, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	var written []*sbom.QualityReport
+	require.NoError(t, json.Unmarshal(b, &written))
+	assert.Equal(t, reports, written)
+}
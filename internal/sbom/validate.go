@@ -0,0 +1,344 @@
+package sbom
+
+import (
+	"bytes"
+	"cmp"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	errs "github.com/snyk/cli-extension-sbom/internal/errors"
+)
+
+const (
+	StandardCycloneDX = "CycloneDX"
+	StandardSPDX      = "SPDX"
+)
+
+//go:embed schemas/*.json
+var schemaFS embed.FS
+
+// schemaFiles maps the document formats that can be validated to their
+// schema.
+var schemaFiles = map[Format]string{
+	{Standard: StandardCycloneDX, SpecVersion: "1.4"}: "schemas/bom-1.4.schema.json",
+	{Standard: StandardCycloneDX, SpecVersion: "1.5"}: "schemas/bom-1.5.schema.json",
+	{Standard: StandardCycloneDX, SpecVersion: "1.6"}: "schemas/bom-1.6.schema.json",
+	{Standard: StandardSPDX, SpecVersion: "2.3"}:      "schemas/spdx-2.3.schema.json",
+}
+
+// maxReportedViolations caps the violations listed when a document is
+// rejected before it is tested or monitored.
+const maxReportedViolations = 10
+
+var (
+	// ErrUnknownFormat is returned for documents that are neither CycloneDX
+	// nor SPDX.
+	ErrUnknownFormat = errors.New("document is neither CycloneDX nor SPDX")
+	// ErrUnsupportedSpecVersion is returned for documents whose spec version
+	// there is no schema for.
+	ErrUnsupportedSpecVersion = errors.New("unsupported spec version")
+)
+
+var (
+	schemasMu sync.Mutex
+	schemas   = map[Format]*jsonschema.Schema{}
+	// compiler holds the embedded schemas, registered under schemaIDs.
+	compiler  *jsonschema.Compiler
+	schemaIDs map[string]string
+	printer   = message.NewPrinter(language.English)
+)
+
+// Format identifies the standard and spec version of an SBOM document.
+type Format struct {
+	Standard    string `json:"standard"`
+	SpecVersion string `json:"specVersion"`
+}
+
+func (f Format) String() string {
+	return f.Standard + " " + f.SpecVersion
+}
+
+// Violation is a schema constraint the document does not satisfy.
+type Violation struct {
+	// Pointer is the JSON pointer (RFC 6901) to the offending value. It is
+	// empty for the document root.
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	if v.Pointer == "" {
+		return "/: " + v.Message
+	}
+	return v.Pointer + ": " + v.Message
+}
+
+// ValidationResult lists the schema violations of a document.
+type ValidationResult struct {
+	Format     Format      `json:"format"`
+	Violations []Violation `json:"violations"`
+}
+
+func (r *ValidationResult) Valid() bool {
+	return len(r.Violations) == 0
+}
+
+// SupportedFormats returns the formats Validate can check.
+func SupportedFormats() []Format {
+	formats := make([]Format, 0, len(schemaFiles))
+	for f := range schemaFiles {
+		formats = append(formats, f)
+	}
+	slices.SortFunc(formats, func(a, b Format) int {
+		return cmp.Or(cmp.Compare(a.Standard, b.Standard), cmp.Compare(a.SpecVersion, b.SpecVersion))
+	})
+	return formats
+}
+
+// DetectFormat returns the standard and spec version of a JSON SBOM document.
+func DetectFormat(b []byte) (Format, error) {
+	inst, err := jsonschema.UnmarshalJSON(bytes.NewReader(b))
+	if err != nil {
+		return Format{}, ErrUnknownFormat
+	}
+	return instanceFormat(inst)
+}
+
+// instanceFormat returns the standard and spec version of a parsed JSON SBOM
+// document.
+func instanceFormat(inst any) (Format, error) {
+	doc, ok := inst.(map[string]any)
+	if !ok {
+		return Format{}, ErrUnknownFormat
+	}
+
+	spdxVersion := stringField(doc, "spdxVersion")
+	switch {
+	case stringField(doc, "bomFormat") == StandardCycloneDX:
+		return Format{Standard: StandardCycloneDX, SpecVersion: stringField(doc, "specVersion")}, nil
+	case strings.HasPrefix(spdxVersion, "SPDX-"):
+		return Format{Standard: StandardSPDX, SpecVersion: strings.TrimPrefix(spdxVersion, "SPDX-")}, nil
+	default:
+		return Format{}, ErrUnknownFormat
+	}
+}
+
+// stringField returns the string value of a field of a JSON object, or an
+// empty string if it has none.
+func stringField(obj map[string]any, name string) string {
+	s, ok := obj[name].(string)
+	if !ok {
+		return ""
+	}
+	return s
+}
+
+// Validate detects the format of a JSON SBOM document and checks it against
+// the schema of its spec version. An error is returned if the format can not
+// be validated; schema violations are reported in the result.
+func Validate(b []byte) (*ValidationResult, error) {
+	return ValidateReader(bytes.NewReader(b))
+}
+
+// ValidateReader is like Validate, for a document read from r, which is parsed
+// as it is read rather than read into memory first.
+func ValidateReader(r io.Reader) (*ValidationResult, error) {
+	inst, err := jsonschema.UnmarshalJSON(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnknownFormat, err)
+	}
+
+	format, err := instanceFormat(inst)
+	if err != nil {
+		return nil, err
+	}
+
+	sch, err := schemaFor(format)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ValidationResult{Format: format, Violations: []Violation{}}
+	var verr *jsonschema.ValidationError
+	if err := sch.Validate(inst); errors.As(err, &verr) {
+		res.Violations = collectViolations(verr, res.Violations)
+		slices.SortStableFunc(res.Violations, func(a, b Violation) int {
+			return comparePointers(a.Pointer, b.Pointer)
+		})
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to validate document: %w", err)
+	}
+
+	return res, nil
+}
+
+// CheckSchema rejects documents that violate the schema of their format,
+// listing the first violations. Documents in formats without a schema are
+// accepted, leaving it to the Snyk API to decide whether they are supported.
+func CheckSchema(b []byte, errFactory *errs.ErrorFactory) error {
+	res, err := Validate(b)
+	return checkValidation(res, err, errFactory)
+}
+
+// CheckFileSchema is like CheckSchema, for the document of an SBOM file, which
+// is read from disk if it is streamed.
+func CheckFileSchema(f *SBOMFile, errFactory *errs.ErrorFactory) error {
+	r, err := f.Open()
+	if err != nil {
+		return errFactory.NewFailedToOpenFileError(err)
+	}
+	defer r.Close() //nolint:errcheck // Read-only, nothing to flush
+
+	res, err := ValidateReader(r)
+	return checkValidation(res, err, errFactory)
+}
+
+func checkValidation(res *ValidationResult, err error, errFactory *errs.ErrorFactory) error {
+	if errors.Is(err, ErrUnknownFormat) || errors.Is(err, ErrUnsupportedSpecVersion) {
+		return nil
+	}
+	if err != nil {
+		return errFactory.NewFailedToValidateSBOMError(err)
+	}
+	if res.Valid() {
+		return nil
+	}
+
+	lines := make([]string, 0, maxReportedViolations+1)
+	for i, v := range res.Violations {
+		if i == maxReportedViolations {
+			lines = append(lines, fmt.Sprintf("... and %d more, run `snyk sbom validate` to list all violations",
+				len(res.Violations)-maxReportedViolations))
+			break
+		}
+		lines = append(lines, v.String())
+	}
+	return errFactory.NewInvalidSBOMError(res.Format.String(), lines)
+}
+
+func schemaFor(format Format) (*jsonschema.Schema, error) {
+	file, ok := schemaFiles[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSpecVersion, format)
+	}
+
+	schemasMu.Lock()
+	defer schemasMu.Unlock()
+
+	if sch, ok := schemas[format]; ok {
+		return sch, nil
+	}
+
+	if compiler == nil {
+		c, ids, err := newSchemaCompiler(schemaFS)
+		if err != nil {
+			return nil, err
+		}
+		compiler, schemaIDs = c, ids
+	}
+	sch, err := compiler.Compile(schemaIDs[file])
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile schema %s: %w", file, err)
+	}
+
+	schemas[format] = sch
+	return sch, nil
+}
+
+// newSchemaCompiler returns a compiler that the schemas in the schemas
+// directory of fsys are registered with under their `$id`, so that the
+// references between them resolve without fetching anything, along with the
+// ID of each file. The CycloneDX schemas refer to spdx.schema.json and
+// jsf-0.82.schema.json next to them, for instance.
+func newSchemaCompiler(fsys fs.FS) (*jsonschema.Compiler, map[string]string, error) {
+	files, err := fs.Glob(fsys, "schemas/*.json")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := jsonschema.NewCompiler()
+	c.AssertFormat()
+	ids := make(map[string]string, len(files))
+	for _, file := range files {
+		b, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, nil, err
+		}
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(b))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse schema %s: %w", file, err)
+		}
+		id := file
+		if m, ok := doc.(map[string]any); ok {
+			if s, ok := m["$id"].(string); ok && s != "" {
+				id = s
+			}
+		}
+		if err := c.AddResource(id, doc); err != nil {
+			return nil, nil, fmt.Errorf("failed to load schema %s: %w", file, err)
+		}
+		ids[file] = id
+	}
+
+	return c, ids, nil
+}
+
+// collectViolations flattens the validation error tree into its leaves, which
+// name the constraints that failed.
+func collectViolations(verr *jsonschema.ValidationError, out []Violation) []Violation {
+	if len(verr.Causes) == 0 {
+		v := Violation{
+			Pointer: jsonPointer(verr.InstanceLocation),
+			Message: verr.ErrorKind.LocalizedString(printer),
+		}
+		for _, seen := range out {
+			if seen == v {
+				return out
+			}
+		}
+		return append(out, v)
+	}
+
+	for _, cause := range verr.Causes {
+		out = collectViolations(cause, out)
+	}
+	return out
+}
+
+// comparePointers orders JSON pointers in document order, comparing array
+// indices numerically.
+func comparePointers(a, b string) int {
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		ai, aerr := strconv.Atoi(as[i])
+		bi, berr := strconv.Atoi(bs[i])
+		c := cmp.Compare(as[i], bs[i])
+		if aerr == nil && berr == nil {
+			c = cmp.Compare(ai, bi)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(as), len(bs))
+}
+
+func jsonPointer(tokens []string) string {
+	var sb strings.Builder
+	for _, tok := range tokens {
+		sb.WriteByte('/')
+		sb.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(tok))
+	}
+	return sb.String()
+}
@@ -0,0 +1,68 @@
+package sbom
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	bomSchema = `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"$id": "http://cyclonedx.org/schema/bom-1.6.schema.json",
+		"type": "object",
+		"properties": {
+			"signature": {"$ref": "jsf-0.82.schema.json#/definitions/signature"}
+		}
+	}`
+	jsfSchema = `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"$id": "http://cyclonedx.org/schema/jsf-0.82.schema.json",
+		"definitions": {
+			"signature": {"type": "object", "required": ["algorithm"]}
+		}
+	}`
+)
+
+func TestNewSchemaCompiler_ResolvesReferencesBetweenSchemas(t *testing.T) {
+	fsys := fstest.MapFS{
+		"schemas/bom-1.6.schema.json":  {Data: []byte(bomSchema)},
+		"schemas/jsf-0.82.schema.json": {Data: []byte(jsfSchema)},
+	}
+
+	c, ids, err := newSchemaCompiler(fsys)
+	require.NoError(t, err)
+	assert.Equal(t, "http://cyclonedx.org/schema/bom-1.6.schema.json", ids["schemas/bom-1.6.schema.json"])
+
+	sch, err := c.Compile(ids["schemas/bom-1.6.schema.json"])
+	require.NoError(t, err)
+
+	assert.NoError(t, sch.Validate(map[string]any{"signature": map[string]any{"algorithm": "ES256"}}))
+	var verr *jsonschema.ValidationError
+	assert.ErrorAs(t, sch.Validate(map[string]any{"signature": map[string]any{}}), &verr)
+}
+
+func TestNewSchemaCompiler_MissingReferencedSchema(t *testing.T) {
+	fsys := fstest.MapFS{
+		"schemas/bom-1.6.schema.json": {Data: []byte(bomSchema)},
+	}
+
+	c, ids, err := newSchemaCompiler(fsys)
+	require.NoError(t, err)
+
+	_, err = c.Compile(ids["schemas/bom-1.6.schema.json"])
+	assert.ErrorContains(t, err, "jsf-0.82.schema.json")
+}
+
+func TestNewSchemaCompiler_EmbeddedSchemasCompile(t *testing.T) {
+	c, ids, err := newSchemaCompiler(schemaFS)
+	require.NoError(t, err)
+
+	for _, file := range schemaFiles {
+		_, err := c.Compile(ids[file])
+		assert.NoError(t, err, file)
+	}
+}
@@ -0,0 +1,227 @@
+package sbom_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	snyk_errors "github.com/snyk/error-catalog-golang-public/snyk_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/bom/cyclonedx"
+	"github.com/snyk/cli-extension-sbom/internal/bom/spdx"
+	"github.com/snyk/cli-extension-sbom/internal/sbom"
+)
+
+func newTestDocument() *bom.Document {
+	return &bom.Document{
+		SerialNumber: "urn:uuid:1b4e28ba-2fa1-4d3b-a3f5-ef19b5a7633b",
+		Timestamp:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Metadata: bom.Metadata{
+			Tools:      []*bom.Tool{{Vendor: "Snyk", Name: "snyk-cli", Version: "1.2.3"}},
+			Authors:    []string{"Jane Doe"},
+			Supplier:   "ACME",
+			Lifecycles: []string{"build"},
+			VCS:        &bom.VCS{URL: "https://github.com/snyk/goof", Commit: "4b825dc642cb6eb9a060e54bf8d69288fbee4904"},
+			Component:  &bom.Component{BOMRef: "goof@1.0.0", Type: bom.ComponentTypeApplication, Name: "goof", Version: "1.0.0"},
+		},
+		Components: []*bom.Component{
+			{BOMRef: "express@4.4.0", Type: bom.ComponentTypeLibrary, Name: "express", Version: "4.4.0", PURL: "pkg:npm/express@4.4.0"},
+		},
+		Dependencies: []*bom.Dependency{
+			{Ref: "goof@1.0.0", DependsOn: []string{"express@4.4.0"}},
+			{Ref: "express@4.4.0"},
+		},
+	}
+}
+
+func TestValidate_ValidDocuments(t *testing.T) {
+	tc := []struct {
+		name     string
+		encode   func() ([]byte, string, error)
+		expected sbom.Format
+	}{
+		{
+			name:     "CycloneDX 1.4",
+			encode:   func() ([]byte, string, error) { return cyclonedx.Encode(newTestDocument(), "1.4", "json") },
+			expected: sbom.Format{Standard: "CycloneDX", SpecVersion: "1.4"},
+		},
+		{
+			name:     "CycloneDX 1.5",
+			encode:   func() ([]byte, string, error) { return cyclonedx.Encode(newTestDocument(), "1.5", "json") },
+			expected: sbom.Format{Standard: "CycloneDX", SpecVersion: "1.5"},
+		},
+		{
+			name:     "CycloneDX 1.6",
+			encode:   func() ([]byte, string, error) { return cyclonedx.Encode(newTestDocument(), "1.6", "json") },
+			expected: sbom.Format{Standard: "CycloneDX", SpecVersion: "1.6"},
+		},
+		{
+			name:     "SPDX 2.3",
+			encode:   func() ([]byte, string, error) { return spdx.Encode(newTestDocument(), "2.3", "json") },
+			expected: sbom.Format{Standard: "SPDX", SpecVersion: "2.3"},
+		},
+		{
+			name:     "testdata/bom.json",
+			encode:   func() ([]byte, string, error) { return []byte(sbomJson), "", nil },
+			expected: sbom.Format{Standard: "CycloneDX", SpecVersion: "1.4"},
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _, err := tt.encode()
+			require.NoError(t, err)
+
+			res, err := sbom.Validate(b)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, res.Format)
+			assert.Empty(t, res.Violations)
+			assert.True(t, res.Valid())
+		})
+	}
+}
+
+func TestValidate_Violations(t *testing.T) {
+	tc := []struct {
+		name     string
+		doc      string
+		expected []sbom.Violation
+	}{
+		{
+			name: "CycloneDX component without name",
+			doc:  `{"bomFormat":"CycloneDX","specVersion":"1.5","components":[{"type":"library","version":"1.0.0"}]}`,
+			expected: []sbom.Violation{
+				{Pointer: "/components/0", Message: "missing property 'name'"},
+			},
+		},
+		{
+			name: "CycloneDX invalid component type and serial number",
+			doc: `{"bomFormat":"CycloneDX","specVersion":"1.4","serialNumber":"1234",` +
+				`"components":[{"type":"lib","name":"ws"}]}`,
+			expected: []sbom.Violation{
+				{Pointer: "/serialNumber", Message: "'1234' does not match pattern " +
+					"'^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-[1-5][0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$'"},
+				{Pointer: "/components/0/type", Message: "value must be one of 'application', 'framework', 'library', 'container', " +
+					"'operating-system', 'device', 'firmware', 'file'"},
+			},
+		},
+		{
+			name: "CycloneDX unknown top-level property",
+			doc:  `{"bomFormat":"CycloneDX","specVersion":"1.6","component":[]}`,
+			expected: []sbom.Violation{
+				{Pointer: "", Message: "additional properties 'component' not allowed"},
+			},
+		},
+		{
+			name: "CycloneDX lifecycles are validated from 1.5",
+			doc:  `{"bomFormat":"CycloneDX","specVersion":"1.5","metadata":{"timestamp":"yesterday","lifecycles":[{"phase":"shipping"}]}}`,
+			expected: []sbom.Violation{
+				{Pointer: "/metadata/timestamp", Message: "'yesterday' is not valid date-time: less than 20 characters long"},
+				{Pointer: "/metadata/lifecycles/0/phase", Message: "value must be one of 'design', 'pre-build', 'build', 'post-build', " +
+					"'operations', 'discovery', 'decommission'"},
+				{Pointer: "/metadata/lifecycles/0", Message: "missing property 'name'"},
+			},
+		},
+		{
+			name: "SPDX missing creators and invalid relationship",
+			doc: `{"spdxVersion":"SPDX-2.3","SPDXID":"SPDXRef-DOCUMENT","dataLicense":"CC0-1.0","name":"goof",` +
+				`"creationInfo":{"created":"2024-01-02T03:04:05Z"},` +
+				`"relationships":[{"spdxElementId":"SPDXRef-DOCUMENT","relatedSpdxElement":"SPDXRef-1","relationshipType":"USES"}]}`,
+			expected: []sbom.Violation{
+				{Pointer: "/creationInfo", Message: "missing property 'creators'"},
+				{Pointer: "/relationships/0/relationshipType", Message: "value must be one of 'VARIANT_OF', 'COPY_OF', 'PATCH_FOR', " +
+					"'TEST_DEPENDENCY_OF', 'CONTAINED_BY', 'DATA_FILE_OF', 'OPTIONAL_COMPONENT_OF', 'ANCESTOR_OF', 'GENERATES', 'CONTAINS', " +
+					"'OPTIONAL_DEPENDENCY_OF', 'FILE_ADDED', 'REQUIREMENT_DESCRIPTION_FOR', 'DEV_DEPENDENCY_OF', 'DEPENDENCY_OF', " +
+					"'BUILD_DEPENDENCY_OF', 'DESCRIBES', 'PREREQUISITE_FOR', 'HAS_PREREQUISITE', 'PROVIDED_DEPENDENCY_OF', 'DYNAMIC_LINK', " +
+					"'DESCRIBED_BY', 'METAFILE_OF', 'DEPENDENCY_MANIFEST_OF', 'PATCH_APPLIED', 'RUNTIME_DEPENDENCY_OF', 'TEST_OF', " +
+					"'TEST_TOOL_OF', 'DEPENDS_ON', 'SPECIFICATION_FOR', 'FILE_MODIFIED', 'DISTRIBUTION_ARTIFACT', 'AMENDS', " +
+					"'DOCUMENTATION_OF', 'GENERATED_FROM', 'STATIC_LINK', 'OTHER', 'BUILD_TOOL_OF', 'TEST_CASE_OF', 'PACKAGE_OF', " +
+					"'DESCENDANT_OF', 'FILE_DELETED', 'EXPANDED_FROM_ARCHIVE', 'DEV_TOOL_OF', 'EXAMPLE_OF'"},
+			},
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := sbom.Validate([]byte(tt.doc))
+
+			require.NoError(t, err)
+			assert.False(t, res.Valid())
+			assert.ElementsMatch(t, tt.expected, res.Violations)
+		})
+	}
+}
+
+func TestValidate_UnknownFormat(t *testing.T) {
+	_, err := sbom.Validate([]byte(`{"foo":"bar"}`))
+
+	assert.ErrorIs(t, err, sbom.ErrUnknownFormat)
+}
+
+func TestValidate_UnsupportedSpecVersion(t *testing.T) {
+	_, err := sbom.Validate([]byte(`{"spdxVersion":"SPDX-2.2"}`))
+
+	assert.ErrorIs(t, err, sbom.ErrUnsupportedSpecVersion)
+	assert.ErrorContains(t, err, "SPDX 2.2")
+}
+
+func TestSupportedFormats(t *testing.T) {
+	assert.Equal(t, []sbom.Format{
+		{Standard: "CycloneDX", SpecVersion: "1.4"},
+		{Standard: "CycloneDX", SpecVersion: "1.5"},
+		{Standard: "CycloneDX", SpecVersion: "1.6"},
+		{Standard: "SPDX", SpecVersion: "2.3"},
+	}, sbom.SupportedFormats())
+}
+
+func TestCheckSchema(t *testing.T) {
+	assert.NoError(t, sbom.CheckSchema([]byte(sbomJson), errFactory))
+	assert.NoError(t, sbom.CheckSchema([]byte(`{"bomFormat":"CycloneDX","specVersion":"1.3"}`), errFactory),
+		"formats without a schema are accepted")
+
+	err := sbom.CheckSchema([]byte(`{"bomFormat":"CycloneDX","specVersion":"1.6","components":[{"type":"library"}]}`), errFactory)
+
+	var snykErr snyk_errors.Error
+	require.True(t, errors.As(err, &snykErr))
+	assert.Equal(t, "The file provided by the `--file` flag is not a valid CycloneDX 1.6 document:\n"+
+		"/components/0: missing property 'name'", snykErr.Detail)
+}
+
+func TestCheckSchema_TruncatesViolations(t *testing.T) {
+	doc := `{"bomFormat":"CycloneDX","specVersion":"1.6","components":[`
+	for i := range 12 {
+		if i > 0 {
+			doc += ","
+		}
+		doc += fmt.Sprintf(`{"type":"library","version":"%d"}`, i)
+	}
+	doc += `]}`
+
+	err := sbom.CheckSchema([]byte(doc), errFactory)
+
+	var snykErr snyk_errors.Error
+	require.True(t, errors.As(err, &snykErr))
+	assert.Contains(t, snykErr.Detail, "/components/9: missing property 'name'\n"+
+		"... and 2 more, run `snyk sbom validate` to list all violations")
+	assert.NotContains(t, snykErr.Detail, "/components/10")
+}
+
+func TestCheckFileSchema_Streamed(t *testing.T) {
+	doc := `{"bomFormat":"CycloneDX","specVersion":"1.6","components":[{"type":"library"}]}`
+	filename := writeTestFile(t, "bom.json", []byte(doc))
+	files, err := sbom.StreamSBOMFiles(filename, int64(len(doc)-1), errFactory)
+	require.NoError(t, err)
+	require.True(t, files[0].Streamed)
+
+	err = sbom.CheckFileSchema(files[0], errFactory)
+
+	var snykErr snyk_errors.Error
+	require.True(t, errors.As(err, &snykErr))
+	assert.Equal(t, "The file provided by the `--file` flag is not a valid CycloneDX 1.6 document:\n"+
+		"/components/0: missing property 'name'", snykErr.Detail)
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"encoding/json"
+	stderr "errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/bom/cyclonedx"
+	"github.com/snyk/cli-extension-sbom/internal/bom/spdx"
+	"github.com/snyk/cli-extension-sbom/internal/depgraph"
+	"github.com/snyk/cli-extension-sbom/internal/errors"
+)
+
+// PropertyScanError is the name of the document property that records a
+// project which could not be scanned.
+const PropertyScanError = "snyk:scan_error"
+
+// OfflineOptions tune the documents generated by DepGraphsToSBOMOffline.
+type OfflineOptions struct {
+	// Reproducible makes the output deterministic: the document is sorted
+	// canonically, its serial number is derived from its content and its
+	// timestamp is set to Timestamp.
+	Reproducible bool
+	Timestamp    time.Time
+}
+
+// DepGraphsToSBOMOffline converts the given depgraphs to an SBOM document
+// locally, without sending them to the Snyk API. The resulting document is
+// equivalent to the one produced by DepGraphsToSBOM for the same inputs.
+func DepGraphsToSBOMOffline(
+	depGraphs []json.RawMessage,
+	scanErrors []ScanError,
+	subject *Subject,
+	t *Tool,
+	metadata *Metadata,
+	format string,
+	opts *OfflineOptions,
+	logger *zerolog.Logger,
+	errFactory *errors.ErrorFactory,
+) (*SBOMResult, error) {
+	logger.Println("Converting depgraphs locally")
+
+	doc, err := BuildDocument(depGraphs, scanErrors, subject, t, metadata)
+	if err != nil {
+		return nil, errFactory.NewFatalSBOMGenerationError(err)
+	}
+
+	if opts != nil && opts.Reproducible {
+		if err := MakeReproducible(doc, opts.Timestamp); err != nil {
+			return nil, errFactory.NewFatalSBOMGenerationError(err)
+		}
+	}
+
+	result, err := EncodeDocument(doc, format)
+	if err != nil {
+		return nil, errFactory.NewFatalSBOMGenerationError(err)
+	}
+
+	logger.Println("Successfully converted depGraph to SBOM")
+
+	return result, nil
+}
+
+// EncodeDocument renders doc in the given SBOM format.
+func EncodeDocument(doc *bom.Document, format string) (*SBOMResult, error) {
+	f, err := bom.ParseFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		b        []byte
+		mimeType string
+	)
+	switch f.Standard {
+	case bom.StandardCycloneDX:
+		b, mimeType, err = cyclonedx.Encode(doc, f.SpecVersion, f.Encoding)
+	case bom.StandardSPDX:
+		b, mimeType, err = spdx.Encode(doc, f.SpecVersion, f.Encoding)
+	default:
+		err = fmt.Errorf("format %q is not supported for local conversion", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &SBOMResult{Doc: b, MIMEType: mimeType}, nil
+}
+
+// ConvertDocument renders doc in the given SBOM format like EncodeDocument,
+// and reports the information the format cannot represent.
+func ConvertDocument(doc *bom.Document, format string) (*SBOMResult, []bom.Warning, error) {
+	f, err := bom.ParseFormat(format)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var warnings []bom.Warning
+	switch f.Standard {
+	case bom.StandardCycloneDX:
+		warnings = cyclonedx.Unrepresentable(doc, f.SpecVersion)
+	case bom.StandardSPDX:
+		warnings = spdx.Unrepresentable(doc, f.SpecVersion)
+	}
+
+	result, err := EncodeDocument(doc, format)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result, warnings, nil
+}
+
+// MakeReproducible sorts doc canonically and replaces its timestamp with ts
+// and its serial number with one derived from its content.
+func MakeReproducible(doc *bom.Document, ts time.Time) error {
+	doc.Sort()
+	doc.Timestamp = ts.UTC()
+
+	serial, err := doc.ContentSerialNumber()
+	if err != nil {
+		return fmt.Errorf("failed to derive serial number: %w", err)
+	}
+	doc.SerialNumber = serial
+
+	return nil
+}
+
+// BuildDocument assembles a bom document from depgraphs. Like the remote
+// conversion, a single depgraph without a subject becomes the document's
+// root component; otherwise the subject is the root and each depgraph's root
+// package becomes one of its dependencies.
+func BuildDocument(depGraphs []json.RawMessage, scanErrors []ScanError, subject *Subject, t *Tool, metadata *Metadata) (*bom.Document, error) {
+	graphs := make([]*depgraph.DepGraph, 0, len(depGraphs))
+	for _, b := range depGraphs {
+		dg, err := depgraph.Parse(b)
+		if err != nil {
+			return nil, err
+		}
+		graphs = append(graphs, dg)
+	}
+
+	doc := &bom.Document{
+		SerialNumber: "urn:uuid:" + uuid.NewString(),
+		Timestamp:    time.Now().UTC(),
+	}
+
+	if t != nil {
+		doc.Metadata.Tools = []*bom.Tool{{Vendor: t.Vendor, Name: t.Name, Version: t.Version}}
+	}
+
+	if metadata != nil {
+		doc.Metadata.Supplier = metadata.Supplier
+		doc.Metadata.Authors = metadata.Authors
+		doc.Metadata.Manufacturer = metadata.Manufacturer
+		doc.Metadata.Lifecycles = metadata.Lifecycles
+		if v := metadata.VCS; v != nil {
+			doc.Metadata.VCS = &bom.VCS{URL: v.URL, Commit: v.Commit, Branch: v.Branch, Dirty: v.Dirty}
+		}
+	}
+
+	if subject == nil || subject.Name == "" {
+		if len(graphs) != 1 {
+			return nil, stderr.New("no subject defined for multiple depgraphs")
+		}
+		root := graphs[0].RootPkg()
+		if root == nil {
+			return nil, stderr.New("depgraph has no root package")
+		}
+		doc.Metadata.Component = newComponent(graphs[0], root, bom.ComponentTypeApplication)
+		addDepGraph(doc, graphs[0])
+	} else {
+		doc.Metadata.Component = SubjectComponent(subject.Name, subject.Version)
+		doc.AddDependencies(doc.Metadata.Component.BOMRef)
+		for _, dg := range graphs {
+			root := dg.RootPkg()
+			if root == nil {
+				return nil, stderr.New("depgraph has no root package")
+			}
+			if root.ID == doc.Metadata.Component.BOMRef {
+				// The subject is the depgraph's own root package.
+				doc.Metadata.Component = newComponent(dg, root, bom.ComponentTypeApplication)
+			} else {
+				doc.AddComponent(newComponent(dg, root, bom.ComponentTypeApplication))
+				doc.AddDependencies(doc.Metadata.Component.BOMRef, root.ID)
+			}
+			addDepGraph(doc, dg)
+		}
+	}
+
+	for _, se := range scanErrors {
+		doc.Metadata.Properties = append(doc.Metadata.Properties, bom.Property{
+			Name:  PropertyScanError,
+			Value: se.String(),
+		})
+	}
+
+	return doc, nil
+}
+
+func addDepGraph(doc *bom.Document, dg *depgraph.DepGraph) {
+	pkgIDs, deps := dg.PkgDependencies()
+	pkgs := dg.PkgsByID()
+	for _, id := range pkgIDs {
+		if pkg := pkgs[id]; pkg != nil {
+			doc.AddComponent(newComponent(dg, pkg, bom.ComponentTypeLibrary))
+		}
+		doc.AddDependencies(id, deps[id]...)
+	}
+}
+
+func newComponent(dg *depgraph.DepGraph, pkg *depgraph.Pkg, typ bom.ComponentType) *bom.Component {
+	return &bom.Component{
+		BOMRef:  pkg.ID,
+		Type:    typ,
+		Name:    pkg.Info.Name,
+		Version: pkg.Info.Version,
+		PURL:    depgraph.PackageURL(dg.PkgManager.Name, pkg.Info),
+	}
+}
+
+// SubjectComponent returns the component of the product a document
+// describes, identified by the name and version of its subject.
+func SubjectComponent(name, version string) *bom.Component {
+	ref := name
+	if version != "" {
+		ref = name + "@" + version
+	}
+	return &bom.Component{
+		BOMRef:  ref,
+		Type:    bom.ComponentTypeApplication,
+		Name:    name,
+		Version: version,
+	}
+}
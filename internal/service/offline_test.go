@@ -0,0 +1,243 @@
+package service_test
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/errors"
+	. "github.com/snyk/cli-extension-sbom/internal/service"
+)
+
+//go:embed testdata/depgraph.json
+var depGraphData []byte
+
+func TestDepGraphsToSBOMOffline(t *testing.T) {
+	tc := map[string]struct {
+		format              string
+		expectedContentType string
+	}{
+		"CycloneDX 1.4 JSON": {format: "cyclonedx1.4+json", expectedContentType: "application/vnd.cyclonedx+json"},
+		"CycloneDX 1.4 XML":  {format: "cyclonedx1.4+xml", expectedContentType: "application/vnd.cyclonedx+xml"},
+		"CycloneDX 1.5 JSON": {format: "cyclonedx1.5+json", expectedContentType: "application/vnd.cyclonedx+json"},
+		"CycloneDX 1.5 XML":  {format: "cyclonedx1.5+xml", expectedContentType: "application/vnd.cyclonedx+xml"},
+		"CycloneDX 1.6 JSON": {format: "cyclonedx1.6+json", expectedContentType: "application/vnd.cyclonedx+json"},
+		"CycloneDX 1.6 XML":  {format: "cyclonedx1.6+xml", expectedContentType: "application/vnd.cyclonedx+xml"},
+		"SPDX 2.3 JSON":      {format: "spdx2.3+json", expectedContentType: "application/spdx+json"},
+		"SPDX 3.0 JSON":      {format: "spdx3.0+json", expectedContentType: "application/spdx+json"},
+	}
+
+	for name, tt := range tc {
+		t.Run(name, func(t *testing.T) {
+			logger := zerolog.New(&bytes.Buffer{})
+			errFactory := errors.NewErrorFactory(&logger)
+
+			res, err := DepGraphsToSBOMOffline(
+				[]json.RawMessage{depGraphData},
+				nil, // scanErrors
+				nil, // subject
+				&Tool{Vendor: "Snyk", Name: "snyk-cli", Version: "1.2.3"},
+				nil, // metadata
+				tt.format,
+				nil, // opts
+				&logger,
+				errFactory,
+			)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedContentType, res.MIMEType)
+			assert.Contains(t, string(res.Doc), "pkg:npm/express@4.4.0")
+		})
+	}
+}
+
+func TestDepGraphsToSBOMOffline_InvalidDepGraph(t *testing.T) {
+	logger := zerolog.New(&bytes.Buffer{})
+	errFactory := errors.NewErrorFactory(&logger)
+
+	_, err := DepGraphsToSBOMOffline(
+		[]json.RawMessage{[]byte("not a depgraph")},
+		nil, // scanErrors
+		nil, // subject
+		nil, // tool
+		nil, // metadata
+		"cyclonedx1.4+json",
+		nil, // opts
+		&logger,
+		errFactory,
+	)
+
+	assert.ErrorContains(t, err, "An error occurred while running the underlying analysis which is required to generate the SBOM.")
+}
+
+func TestBuildDocument_SingleDepGraph(t *testing.T) {
+	doc, err := BuildDocument([]json.RawMessage{depGraphData}, nil, nil, nil, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, &bom.Component{
+		BOMRef:  "demo-app-for-test@1.1.1",
+		Type:    bom.ComponentTypeApplication,
+		Name:    "demo-app-for-test",
+		Version: "1.1.1",
+		PURL:    "pkg:npm/demo-app-for-test@1.1.1",
+	}, doc.Metadata.Component)
+	assert.Empty(t, doc.Metadata.Tools)
+	assert.Len(t, doc.Components, 2)
+	assert.Equal(t, []*bom.Dependency{
+		{Ref: "demo-app-for-test@1.1.1", DependsOn: []string{"express@4.4.0", "ws@1.0.0"}},
+		{Ref: "express@4.4.0"},
+		{Ref: "ws@1.0.0"},
+	}, doc.Dependencies)
+	assert.Regexp(t, "^urn:uuid:", doc.SerialNumber)
+	assert.False(t, doc.Timestamp.IsZero())
+}
+
+func TestBuildDocument_MultipleDepGraphs_WithSubject(t *testing.T) {
+	otherDepGraph := []byte(`{"pkgManager":{"name":"pip"},"pkgs":[` +
+		`{"id":"api@0.1.0","info":{"name":"api","version":"0.1.0"}},` +
+		`{"id":"requests@2.31.0","info":{"name":"requests","version":"2.31.0"}}],` +
+		`"graph":{"rootNodeId":"root-node","nodes":[` +
+		`{"nodeId":"root-node","pkgId":"api@0.1.0","deps":[{"nodeId":"requests@2.31.0"}]},` +
+		`{"nodeId":"requests@2.31.0","pkgId":"requests@2.31.0","deps":[]}]}}`)
+	scanErrors := []ScanError{{Subject: "project2/pom.xml", Text: "missing lockfile"}}
+	tool := &Tool{Vendor: "Snyk", Name: "snyk-cli", Version: "1.2.3"}
+
+	doc, err := BuildDocument(
+		[]json.RawMessage{depGraphData, otherDepGraph},
+		scanErrors,
+		NewSubject("my-repo", "1.0.0"),
+		tool,
+		nil, // metadata
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, "my-repo@1.0.0", doc.Metadata.Component.BOMRef)
+	assert.Equal(t, []*bom.Tool{{Vendor: "Snyk", Name: "snyk-cli", Version: "1.2.3"}}, doc.Metadata.Tools)
+	assert.Equal(t, []bom.Property{{Name: "snyk:scan_error", Value: "project2/pom.xml: missing lockfile"}}, doc.Metadata.Properties)
+	assert.Equal(t, []string{"demo-app-for-test@1.1.1", "api@0.1.0"}, doc.Dependencies[0].DependsOn)
+
+	purls := make([]string, 0, len(doc.Components))
+	for _, c := range doc.Components {
+		purls = append(purls, c.PURL)
+	}
+	assert.Equal(t, []string{
+		"pkg:npm/demo-app-for-test@1.1.1",
+		"pkg:npm/express@4.4.0",
+		"pkg:npm/ws@1.0.0",
+		"pkg:pypi/api@0.1.0",
+		"pkg:pypi/requests@2.31.0",
+	}, purls)
+}
+
+func TestBuildDocument_MultipleDepGraphs_NoSubject(t *testing.T) {
+	_, err := BuildDocument([]json.RawMessage{depGraphData, depGraphData}, nil, nil, nil, nil)
+
+	assert.ErrorContains(t, err, "no subject defined for multiple depgraphs")
+}
+
+func TestBuildDocument_SubjectIsRootPackage(t *testing.T) {
+	scanErrors := []ScanError{{Subject: "package-lock.json", Text: "out of sync"}}
+
+	doc, err := BuildDocument([]json.RawMessage{depGraphData}, scanErrors, NewSubject("demo-app-for-test", "1.1.1"), nil, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "pkg:npm/demo-app-for-test@1.1.1", doc.Metadata.Component.PURL)
+	assert.Len(t, doc.Components, 2)
+	assert.Equal(t, []string{"express@4.4.0", "ws@1.0.0"}, doc.Dependencies[0].DependsOn)
+	assert.Equal(t, []bom.Property{{Name: "snyk:scan_error", Value: "package-lock.json: out of sync"}}, doc.Metadata.Properties)
+}
+
+func TestDepGraphsToSBOMOffline_Reproducible(t *testing.T) {
+	logger := zerolog.New(&bytes.Buffer{})
+	errFactory := errors.NewErrorFactory(&logger)
+	opts := &OfflineOptions{Reproducible: true, Timestamp: time.Unix(1700000000, 0)}
+
+	for _, format := range []string{"cyclonedx1.6+json", "cyclonedx1.6+xml", "spdx2.3+json", "spdx3.0+json"} {
+		t.Run(format, func(t *testing.T) {
+			generate := func() []byte {
+				res, err := DepGraphsToSBOMOffline(
+					[]json.RawMessage{depGraphData},
+					[]ScanError{{Subject: "b/pom.xml", Text: "failed"}, {Subject: "a/pom.xml", Text: "failed"}},
+					NewSubject("my-repo", "1.0.0"),
+					&Tool{Vendor: "Snyk", Name: "snyk-cli", Version: "1.2.3"},
+					nil, // metadata
+					format,
+					opts,
+					&logger,
+					errFactory,
+				)
+				require.NoError(t, err)
+				return res.Doc
+			}
+
+			first := generate()
+			assert.Equal(t, string(first), string(generate()))
+			assert.Contains(t, string(first), "2023-11-14T22:13:20Z")
+		})
+	}
+}
+
+func TestMakeReproducible(t *testing.T) {
+	doc, err := BuildDocument([]json.RawMessage{depGraphData}, nil, nil, nil, nil)
+	require.NoError(t, err)
+	ts := time.Unix(1700000000, 0)
+
+	require.NoError(t, MakeReproducible(doc, ts))
+
+	assert.Equal(t, ts.UTC(), doc.Timestamp)
+	assert.Equal(t, "urn:uuid:", doc.SerialNumber[:9])
+	other, err := BuildDocument([]json.RawMessage{depGraphData}, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, MakeReproducible(other, ts))
+	assert.Equal(t, doc.SerialNumber, other.SerialNumber)
+}
+
+func TestConvertDocument(t *testing.T) {
+	doc := &bom.Document{
+		SerialNumber: "urn:uuid:1b4e28ba-2fa1-4d3b-a3f5-ef19b5a7633b",
+		Components: []*bom.Component{{
+			BOMRef:     "express@4.4.0",
+			Type:       "platform",
+			Name:       "express",
+			Version:    "4.4.0",
+			Properties: []bom.Property{{Name: "cdx:npm:package:development", Value: "true"}},
+		}},
+	}
+
+	tc := map[string]struct {
+		format           string
+		expectedWarnings []bom.Warning
+	}{
+		"CycloneDX 1.4": {
+			format: "cyclonedx1.4+xml",
+			expectedWarnings: []bom.Warning{
+				{BOMRef: "express@4.4.0", Msg: `component type "platform" is not supported by CycloneDX 1.4 and was replaced by "library"`},
+			},
+		},
+		"CycloneDX 1.6": {format: "cyclonedx1.6+json"},
+		"SPDX 2.3": {
+			format: "spdx2.3+json",
+			expectedWarnings: []bom.Warning{
+				{BOMRef: "express@4.4.0", Msg: "SPDX has no component properties, 1 properties were dropped"},
+				{BOMRef: "express@4.4.0", Msg: `component type "platform" is not supported by SPDX 2.3 and was replaced by "OTHER"`},
+			},
+		},
+	}
+
+	for name, tt := range tc {
+		t.Run(name, func(t *testing.T) {
+			res, warnings, err := ConvertDocument(doc, tt.format)
+
+			require.NoError(t, err)
+			assert.NotEmpty(t, res.Doc)
+			assert.Equal(t, tt.expectedWarnings, warnings)
+		})
+	}
+}
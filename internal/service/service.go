@@ -9,6 +9,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
+	"strings"
 
 	"github.com/rs/zerolog"
 
@@ -40,6 +42,13 @@ var sbomFormats = [...]string{
 	"cyclonedx1.6+json",
 	"cyclonedx1.6+xml",
 	"spdx2.3+json",
+	"spdx3.0+json",
+}
+
+// localOnlyFormats are not supported by the SBOM conversion API and are
+// always generated locally.
+var localOnlyFormats = [...]string{
+	"spdx3.0+json",
 }
 
 func NewSubject(name, version string) *Subject {
@@ -57,12 +66,13 @@ func DepGraphsToSBOM(
 	scanErrors []ScanError,
 	subject *Subject,
 	t *Tool,
+	metadata *Metadata,
 	format string,
 	goModuleLevel bool,
 	logger *zerolog.Logger,
 	errFactory *errors.ErrorFactory,
 ) (result *SBOMResult, err error) {
-	payload, err := preparePayload(depGraphs, scanErrors, subject, t)
+	payload, err := preparePayload(depGraphs, scanErrors, subject, t, metadata)
 	if err != nil {
 		return nil, errFactory.NewFatalSBOMGenerationError(err)
 	}
@@ -142,7 +152,35 @@ func ValidateSBOMFormat(errFactory *errors.ErrorFactory, candidate string) error
 	return errFactory.NewInvalidFormatError(candidate, sbomFormats[:])
 }
 
-func preparePayload(depGraphs []json.RawMessage, scanErrors []ScanError, subject *Subject, t *Tool) ([]byte, error) {
+// ParseSBOMFormats splits a comma-separated list of SBOM formats, validates
+// each of them and drops duplicates.
+func ParseSBOMFormats(errFactory *errors.ErrorFactory, candidates string) ([]string, error) {
+	var formats []string
+	for _, f := range strings.Split(candidates, ",") {
+		f = strings.TrimSpace(f)
+		if err := ValidateSBOMFormat(errFactory, f); err != nil {
+			return nil, err
+		}
+		if !slices.Contains(formats, f) {
+			formats = append(formats, f)
+		}
+	}
+
+	return formats, nil
+}
+
+// RequiresLocalConversion reports whether format can only be generated
+// locally, using DepGraphsToSBOMOffline.
+func RequiresLocalConversion(format string) bool {
+	for _, f := range localOnlyFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+func preparePayload(depGraphs []json.RawMessage, scanErrors []ScanError, subject *Subject, t *Tool, metadata *Metadata) ([]byte, error) {
 	// by using json.RawMessage everywhere we expect a json-encoded []byte, we can embed this
 	// directly in Go types and call `json.Marshal` on it to embed the JSON directly.
 
@@ -156,12 +194,14 @@ func preparePayload(depGraphs []json.RawMessage, scanErrors []ScanError, subject
 
 		return json.Marshal(&payloadSingleDepGraph{
 			Tools:    []*Tool{t},
+			Metadata: metadata,
 			DepGraph: depGraphs[0],
 		})
 	}
 
 	return json.Marshal(&payloadMultipleDepGraphs{
 		Tools:      []*Tool{t},
+		Metadata:   metadata,
 		DepGraphs:  depGraphs,
 		ScanErrors: scanErrors,
 		Subject:    subject,
@@ -65,6 +65,7 @@ func TestDepGraphsToSBOM(t *testing.T) {
 				nil, // scanErrors
 				nil, // subject
 				nil, // tool
+				nil, // metadata
 				tt.format,
 				false,
 				&logger,
@@ -101,6 +102,7 @@ func TestDepGraphsToSBOM_GoModuleLevel(t *testing.T) {
 		nil, // scanErrors
 		nil, // subject
 		nil, // tool
+		nil, // metadata
 		format,
 		true,
 		&logger,
@@ -138,6 +140,7 @@ func TestDepGraphsToSBOM_MultipleDepGraphs(t *testing.T) {
 		nil, // scanErrors
 		subject,
 		tool,
+		nil, // metadata
 		format,
 		false,
 		&logger,
@@ -173,6 +176,45 @@ func TestDepGraphsToSBOM_SingleDepGraph_WithSubject(t *testing.T) {
 		nil, // scanErrors
 		subject,
 		tool,
+		nil, // metadata
+		format,
+		false,
+		&logger,
+		errFactory,
+	)
+	assert.NoError(t, err)
+}
+
+func TestDepGraphsToSBOM_WithMetadata(t *testing.T) {
+	format := "cyclonedx1.6+json"
+	expectedContentType := "application/vnd.cyclonedx+json"
+	mockBody := []byte("{}")
+	response := mocks.NewMockResponse(expectedContentType, mockBody, http.StatusOK)
+	mockSBOMService := mocks.NewMockSBOMService(response, func(r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		defer r.Body.Close() //nolint:errcheck // Test cleanup, error can be safely ignored
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"depGraphs":[{}],"subject":{"name":"goof","version":"0.0.0"},`+
+			`"tools":[{"name":"snyk-cli","vendor":"Snyk","version":"1.2.3"}],`+
+			`"metadata":{"supplier":"ACME","authors":["Jane Doe"],"lifecycles":["build"]}}`,
+			string(body))
+	})
+	logger := zerolog.New(&bytes.Buffer{})
+	errFactory := errors.NewErrorFactory(&logger)
+	depGraphs := []json.RawMessage{[]byte("{}")}
+	subject := NewSubject("goof", "0.0.0")
+	tool := &Tool{Vendor: "Snyk", Name: "snyk-cli", Version: "1.2.3"}
+	metadata := &Metadata{Supplier: "ACME", Authors: []string{"Jane Doe"}, Lifecycles: []string{"build"}}
+
+	_, err := DepGraphsToSBOM(
+		http.DefaultClient,
+		mockSBOMService.URL,
+		orgID,
+		depGraphs,
+		nil, // scanErrors
+		subject,
+		tool,
+		metadata,
 		format,
 		false,
 		&logger,
@@ -200,6 +242,7 @@ func TestDepGraphsToSBOM_FailedRequest(t *testing.T) {
 		nil, // scanErrors
 		nil, // subject
 		nil, // tool
+		nil, // metadata
 		"cyclonedx1.4+json",
 		false,
 		&logger,
@@ -254,6 +297,7 @@ func TestDepGraphsToSBOM_UnsuccessfulResponse(t *testing.T) {
 				nil, // scanErrors
 				nil, // subject
 				nil, // tool
+				nil, // metadata
 				"cyclonedx1.4+json",
 				false,
 				&logger,
@@ -296,6 +340,7 @@ func TestDepGraphsToSBOM_WithScanErrors_PartialSuccess(t *testing.T) {
 		scanErrors,
 		subject,
 		tool,
+		nil, // metadata
 		format,
 		false,
 		&logger,
@@ -343,6 +388,7 @@ func TestDepGraphsToSBOM_WithScanErrors_AllErrors(t *testing.T) {
 		scanErrors,
 		subject,
 		tool,
+		nil, // metadata
 		format,
 		false,
 		&logger,
@@ -358,7 +404,8 @@ func TestValidateSBOMFormat_EmptyFormat(t *testing.T) {
 	errFactory := errors.NewErrorFactory(&logger)
 	err := ValidateSBOMFormat(errFactory, "")
 	assert.ErrorContains(t, err, "Must set `--format` flag to specify an SBOM format. "+
-		"Available formats are: cyclonedx1.4+json, cyclonedx1.4+xml, cyclonedx1.5+json, cyclonedx1.5+xml, cyclonedx1.6+json, cyclonedx1.6+xml, spdx2.3+json")
+		"Available formats are: cyclonedx1.4+json, cyclonedx1.4+xml, cyclonedx1.5+json, cyclonedx1.5+xml, "+
+		"cyclonedx1.6+json, cyclonedx1.6+xml, spdx2.3+json, spdx3.0+json")
 }
 
 func TestValidateSBOMFormat_InvalidFormat(t *testing.T) {
@@ -366,7 +413,8 @@ func TestValidateSBOMFormat_InvalidFormat(t *testing.T) {
 	errFactory := errors.NewErrorFactory(&logger)
 	err := ValidateSBOMFormat(errFactory, "not+a+format")
 	assert.ErrorContains(t, err, "The format provided (not+a+format) is not one of the available formats. "+
-		"Available formats are: cyclonedx1.4+json, cyclonedx1.4+xml, cyclonedx1.5+json, cyclonedx1.5+xml, cyclonedx1.6+json, cyclonedx1.6+xml, spdx2.3+json")
+		"Available formats are: cyclonedx1.4+json, cyclonedx1.4+xml, cyclonedx1.5+json, cyclonedx1.5+xml, "+
+		"cyclonedx1.6+json, cyclonedx1.6+xml, spdx2.3+json, spdx3.0+json")
 }
 
 func TestValidateSBOMFormat_ValidFormats(t *testing.T) {
@@ -381,6 +429,7 @@ func TestValidateSBOMFormat_ValidFormats(t *testing.T) {
 		"cyclonedx1.6+json",
 		"cyclonedx1.6+xml",
 		"spdx2.3+json",
+		"spdx3.0+json",
 	}
 
 	for _, tt := range tc {
@@ -390,3 +439,37 @@ func TestValidateSBOMFormat_ValidFormats(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSBOMFormats(t *testing.T) {
+	logger := zerolog.New(&bytes.Buffer{})
+	errFactory := errors.NewErrorFactory(&logger)
+
+	formats, err := ParseSBOMFormats(errFactory, "cyclonedx1.6+json, cyclonedx1.6+xml,spdx2.3+json,cyclonedx1.6+json")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cyclonedx1.6+json", "cyclonedx1.6+xml", "spdx2.3+json"}, formats)
+}
+
+func TestParseSBOMFormats_InvalidFormat(t *testing.T) {
+	logger := zerolog.New(&bytes.Buffer{})
+	errFactory := errors.NewErrorFactory(&logger)
+
+	_, err := ParseSBOMFormats(errFactory, "cyclonedx1.6+json,not+a+format")
+
+	assert.ErrorContains(t, err, "The format provided (not+a+format) is not one of the available formats.")
+}
+
+func TestParseSBOMFormats_EmptyFormat(t *testing.T) {
+	logger := zerolog.New(&bytes.Buffer{})
+	errFactory := errors.NewErrorFactory(&logger)
+
+	_, err := ParseSBOMFormats(errFactory, "")
+
+	assert.ErrorContains(t, err, "Must set `--format` flag to specify an SBOM format.")
+}
+
+func TestRequiresLocalConversion(t *testing.T) {
+	assert.True(t, RequiresLocalConversion("spdx3.0+json"))
+	assert.False(t, RequiresLocalConversion("spdx2.3+json"))
+	assert.False(t, RequiresLocalConversion("cyclonedx1.6+json"))
+}
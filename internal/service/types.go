@@ -13,14 +13,40 @@ type ScanError struct {
 	Text    string `json:"text"`
 }
 
+// Metadata describes who produced the SBOM and for which lifecycle phases.
+type Metadata struct {
+	Supplier     string   `json:"supplier,omitempty"`
+	Authors      []string `json:"authors,omitempty"`
+	Manufacturer string   `json:"manufacturer,omitempty"`
+	Lifecycles   []string `json:"lifecycles,omitempty"`
+	VCS          *VCS     `json:"vcs,omitempty"`
+}
+
+// VCS is the source revision the SBOM is generated from.
+type VCS struct {
+	URL    string `json:"url,omitempty"`
+	Commit string `json:"commit"`
+	Branch string `json:"branch,omitempty"`
+	Dirty  bool   `json:"dirty"`
+}
+
 type payloadSingleDepGraph struct {
 	Tools    []*Tool         `json:"tools,omitempty"`
+	Metadata *Metadata       `json:"metadata,omitempty"`
 	DepGraph json.RawMessage `json:"depGraph"`
 }
 
 type payloadMultipleDepGraphs struct {
 	Tools      []*Tool           `json:"tools,omitempty"`
+	Metadata   *Metadata         `json:"metadata,omitempty"`
 	DepGraphs  []json.RawMessage `json:"depGraphs"`
 	Subject    *Subject          `json:"subject"`
 	ScanErrors []ScanError       `json:"scanErrors,omitempty"`
 }
+
+func (e ScanError) String() string {
+	if e.Subject == "" {
+		return e.Text
+	}
+	return e.Subject + ": " + e.Text
+}
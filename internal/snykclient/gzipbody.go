@@ -0,0 +1,74 @@
+package snykclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+)
+
+// DefaultUploadSizeLimit is the maximum size (50 MB) of a gzip-compressed SBOM
+// document the upload API accepts.
+const DefaultUploadSizeLimit = 50_000_000
+
+// UploadSizeLimit returns the upload size limit in bytes for a limit in MB,
+// which defaults to DefaultUploadSizeLimit if not positive.
+func UploadSizeLimit(megabytes int) int64 {
+	if megabytes <= 0 {
+		return DefaultUploadSizeLimit
+	}
+	return int64(megabytes) * 1_000_000
+}
+
+// ErrUploadSizeExceedsLimit is returned when reading a gzip body whose
+// compressed content exceeds its size limit.
+var ErrUploadSizeExceedsLimit = errors.New("compressed size exceeds upload size limit")
+
+// DocumentOpener opens an SBOM document to upload. As the upload is streamed,
+// it is opened again if the request is retried.
+type DocumentOpener func() (io.ReadCloser, error)
+
+// OpenBytes returns a DocumentOpener of an SBOM document held in memory.
+func OpenBytes(b []byte) DocumentOpener {
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(b)), nil
+	}
+}
+
+// NewGzipBody returns a reader of the gzip-compressed content of r, which is
+// compressed on the fly as it is read, through a pipe. Reading fails with
+// ErrUploadSizeExceedsLimit once the compressed content exceeds the limit.
+// Closing the reader stops the compression. If r is an io.Closer, it is
+// closed once compressed.
+func NewGzipBody(r io.Reader, limit int64) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		if c, ok := r.(io.Closer); ok {
+			defer c.Close() //nolint:errcheck // Read-only, nothing to flush
+		}
+		zw := gzip.NewWriter(&limitedWriter{w: pw, remaining: limit})
+		_, err := io.Copy(zw, r)
+		if err == nil {
+			err = zw.Close()
+		}
+		// A nil error closes the pipe with io.EOF.
+		pw.CloseWithError(err) //nolint:errcheck // Always returns nil
+	}()
+	return pr
+}
+
+// limitedWriter writes to w, failing once more than the remaining bytes
+// would be written.
+type limitedWriter struct {
+	w         io.Writer
+	remaining int64
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining {
+		return 0, ErrUploadSizeExceedsLimit
+	}
+	n, err := l.w.Write(p)
+	l.remaining -= int64(n)
+	return n, err
+}
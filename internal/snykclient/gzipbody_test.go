@@ -0,0 +1,52 @@
+package snykclient_test
+
+import (
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/snykclient"
+)
+
+func TestNewGzipBody(t *testing.T) {
+	content := strings.Repeat(`{"foo":"bar"}`, 1000)
+	body := snykclient.NewGzipBody(strings.NewReader(content), 1000)
+	defer body.Close()
+
+	zr, err := gzip.NewReader(body)
+	require.NoError(t, err)
+	b, err := io.ReadAll(zr)
+
+	require.NoError(t, err)
+	assert.Equal(t, content, string(b))
+}
+
+func TestNewGzipBody_ExceedsLimit(t *testing.T) {
+	body := snykclient.NewGzipBody(strings.NewReader(randomHex(t, 10_000)), 1000)
+	defer body.Close()
+
+	_, err := io.Copy(io.Discard, body)
+
+	assert.ErrorIs(t, err, snykclient.ErrUploadSizeExceedsLimit)
+}
+
+func TestUploadSizeLimit(t *testing.T) {
+	assert.Equal(t, int64(100_000_000), snykclient.UploadSizeLimit(100))
+	assert.Equal(t, int64(snykclient.DefaultUploadSizeLimit), snykclient.UploadSizeLimit(0))
+}
+
+// randomHex returns a string of n random hex digits, which compresses to
+// about half its size.
+func randomHex(t *testing.T, n int) string {
+	t.Helper()
+	b := make([]byte, n/2)
+	_, err := rand.Read(b)
+	require.NoError(t, err)
+	return hex.EncodeToString(b)
+}
@@ -1,10 +1,9 @@
 package snykclient
 
 import (
-	"bytes"
-	"compress/gzip"
 	"context"
 	"encoding/json"
+	stderr "errors"
 	"io"
 	"net/http"
 	"net/url"
@@ -20,7 +19,7 @@ const (
 func (t *SnykClient) SBOMConvert(
 	ctx context.Context,
 	errFactory *errors.ErrorFactory,
-	sbom io.Reader,
+	open DocumentOpener,
 	remoteRepoURL string,
 ) ([]*ScanResult, []*ConversionWarning, error) {
 	u, err := buildSBOMConvertAPIURL(t.apiBaseURL, sbomConvertAPIVersion, t.orgID, remoteRepoURL)
@@ -28,15 +27,21 @@ func (t *SnykClient) SBOMConvert(
 		return nil, nil, errFactory.NewSCAError(err)
 	}
 
-	body := bytes.NewBuffer(nil)
-	writer := gzip.NewWriter(body)
-	_, err = io.Copy(writer, sbom)
-	if err != nil {
-		return nil, nil, errFactory.NewSCAError(err)
+	// The document is compressed while it is sent, rather than buffered. The
+	// compressed length is not known in advance, so the body is sent chunked,
+	// and it is compressed anew from the document if the request is retried.
+	newBody := func() (io.ReadCloser, error) {
+		sbom, err := open()
+		if err != nil {
+			return nil, err
+		}
+		return NewGzipBody(sbom, t.uploadSizeLimit), nil
 	}
-	if err = writer.Close(); err != nil {
-		return nil, nil, errFactory.NewSCAError(err)
+	body, err := newBody()
+	if err != nil {
+		return nil, nil, errFactory.NewFailedToOpenFileError(err)
 	}
+	defer body.Close() //nolint:errcheck // Pipe reader, always returns nil
 
 	req, err := http.NewRequestWithContext(
 		ctx,
@@ -47,11 +52,15 @@ func (t *SnykClient) SBOMConvert(
 	if err != nil {
 		return nil, nil, errFactory.NewSCAError(err)
 	}
+	req.GetBody = newBody
 
 	req.Header.Set(ContentTypeHeader, MIMETypeOctetStream)
 	req.Header.Set(ContentEncodingHeader, "gzip")
 
 	resp, err := t.client.Do(req)
+	if stderr.Is(err, ErrUploadSizeExceedsLimit) {
+		return nil, nil, errFactory.NewUploadSizeExceedsLimitError(t.uploadSizeLimit)
+	}
 	if err != nil {
 		return nil, nil, errFactory.NewSCAError(err)
 	}
@@ -1,14 +1,18 @@
 package snykclient_test
 
 import (
-	"bytes"
+	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"testing"
 
+	snyk_errors "github.com/snyk/error-catalog-golang-public/snyk_errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/snyk/cli-extension-sbom/internal/mocks"
 	"github.com/snyk/cli-extension-sbom/internal/snykclient"
@@ -29,13 +33,18 @@ func Test_SBOMConvert(t *testing.T) {
 		assert.Equal(t, "/hidden/orgs/org1/sboms/convert?remote_repo_url=github.com%2Fsnyk%2Fcli-extension-sbom&version=2025-03-06", r.RequestURI)
 		assert.Equal(t, "application/octet-stream", r.Header.Get("Content-Type"))
 		assert.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+		zr, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		assert.Equal(t, sbomContent, string(body))
 	})
 
 	client := snykclient.NewSnykClient(mockHTTPClient.Client(), mockHTTPClient.URL, "org1")
 	depsResp, warnResp, err := client.SBOMConvert(
 		context.Background(),
 		errFactory,
-		bytes.NewBuffer([]byte(sbomContent)),
+		snykclient.OpenBytes([]byte(sbomContent)),
 		"github.com/snyk/cli-extension-sbom")
 
 	assert.NoError(t, err)
@@ -49,6 +58,70 @@ func Test_SBOMConvert(t *testing.T) {
 	assert.Equal(t, "This is a warning", warnResp[0].Msg)
 }
 
+func Test_SBOMConvert_RetriedUploadIsCompressedAnew(t *testing.T) {
+	response := mocks.NewMockResponse("application/json; charset=utf-8", []byte(`{"scanResults":[]}`), http.StatusOK)
+	sbomContent := strings.Repeat(`{"foo":"bar"}`, 1000)
+
+	mockHTTPClient := mocks.NewMockSBOMService(response, func(r *http.Request) {
+		zr, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		assert.Equal(t, sbomContent, string(body))
+	})
+
+	// The transport consumes the body of the first attempt, then retries the
+	// request with the body it gets anew.
+	retrying := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if _, err := io.Copy(io.Discard, req.Body); err != nil {
+			return nil, err
+		}
+		require.NotNil(t, req.GetBody)
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		retry := req.Clone(req.Context())
+		retry.Body = body
+		return mockHTTPClient.Client().Transport.RoundTrip(retry)
+	})}
+
+	client := snykclient.NewSnykClient(retrying, mockHTTPClient.URL, "org1")
+	_, _, err := client.SBOMConvert(
+		context.Background(),
+		errFactory,
+		snykclient.OpenBytes([]byte(sbomContent)),
+		"github.com/snyk/cli-extension-sbom")
+
+	assert.NoError(t, err)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func Test_SBOMConvert_UploadSizeExceedsLimit(t *testing.T) {
+	response := mocks.NewMockResponse("application/json; charset=utf-8", []byte(`{"scanResults":[]}`), http.StatusOK)
+
+	mockHTTPClient := mocks.NewMockSBOMService(response, func(r *http.Request) {
+		io.Copy(io.Discard, r.Body) //nolint:errcheck // The upload is cut short
+	})
+
+	client := snykclient.NewSnykClient(mockHTTPClient.Client(), mockHTTPClient.URL, "org1").WithUploadSizeLimit(1000)
+	_, _, err := client.SBOMConvert(
+		context.Background(),
+		errFactory,
+		snykclient.OpenBytes([]byte(randomHex(t, 10_000))),
+		"github.com/snyk/cli-extension-sbom")
+
+	var snykErr snyk_errors.Error
+	require.True(t, errors.As(err, &snykErr))
+	assert.Equal(t, "The SBOM document is too large to upload. Its compressed size exceeds the maximum of 1000 bytes, "+
+		"which can be raised with the `--max-upload-size` flag if the server accepts larger uploads.", snykErr.Detail)
+}
+
 func Test_SBOMConvert_InvalidJSONReturned(t *testing.T) {
 	response := mocks.NewMockResponse(
 		"application/json; charset=utf-8",
@@ -62,7 +135,7 @@ func Test_SBOMConvert_InvalidJSONReturned(t *testing.T) {
 	_, _, err := client.SBOMConvert(
 		context.Background(),
 		errFactory,
-		strings.NewReader(`{"foo":"bar"}`),
+		snykclient.OpenBytes([]byte(`{"foo":"bar"}`)),
 		"github.com/snyk/cli-extension-sbom")
 
 	assert.ErrorContains(t, err, "unexpected EOF")
@@ -107,7 +180,7 @@ func Test_SBOMConvert_ServerErrors(t *testing.T) {
 			_, _, err := client.SBOMConvert(
 				context.Background(),
 				errFactory,
-				bytes.NewBufferString(sbomContent),
+				snykclient.OpenBytes([]byte(sbomContent)),
 				"github.com/snyk/cli-extension-sbom")
 
 			assert.ErrorContainsf(
@@ -7,9 +7,10 @@ import (
 
 type (
 	SnykClient struct {
-		client     *http.Client
-		apiBaseURL string
-		orgID      string
+		client          *http.Client
+		apiBaseURL      string
+		orgID           string
+		uploadSizeLimit int64
 	}
 
 	backoffFn func()
@@ -22,12 +23,20 @@ var DefaultBackoff backoffFn = func() {
 
 func NewSnykClient(c *http.Client, apiBaseURL, orgID string) *SnykClient {
 	return &SnykClient{
-		client:     createNonRedirectingHTTPClient(c),
-		apiBaseURL: apiBaseURL,
-		orgID:      orgID,
+		client:          createNonRedirectingHTTPClient(c),
+		apiBaseURL:      apiBaseURL,
+		orgID:           orgID,
+		uploadSizeLimit: DefaultUploadSizeLimit,
 	}
 }
 
+// WithUploadSizeLimit sets the maximum size of the gzip-compressed SBOM
+// documents the client uploads.
+func (t *SnykClient) WithUploadSizeLimit(limit int64) *SnykClient {
+	t.uploadSizeLimit = limit
+	return t
+}
+
 func createNonRedirectingHTTPClient(c *http.Client) *http.Client {
 	newClient := http.Client{
 		Transport: c.Transport,
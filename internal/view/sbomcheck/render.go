@@ -0,0 +1,85 @@
+package sbomcheck
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+func NewRenderer(w io.Writer) *Renderer {
+	return &Renderer{
+		w: w,
+	}
+}
+
+type Renderer struct {
+	w io.Writer
+}
+
+// RenderResult renders the NTIA minimum elements check of the document in
+// the given file: the coverage of each element, the non-conforming
+// components and whether the score meets the threshold.
+func (r *Renderer) RenderResult(filename string, res *bom.NTIAResult, threshold int) error {
+	elements := make([]string, 0, len(res.Elements))
+	for _, e := range res.Elements {
+		line := fmt.Sprintf("%-26s %d/%d", capitalize(e.Element), e.Present, e.Total)
+		if e.Present < e.Total {
+			line = failStyle.Render(line)
+		}
+		elements = append(elements, line)
+	}
+
+	components := make([]string, 0, len(res.Components))
+	for _, c := range res.Components {
+		components = append(components, fmt.Sprintf("%s: missing %s", componentName(c.Component), strings.Join(c.Missing, ", ")))
+	}
+
+	score := res.Score()
+	summary := passStyle.Render(fmt.Sprintf("✓ Score %d meets the threshold of %d", score, threshold))
+	if score < threshold {
+		summary = failStyle.Render(fmt.Sprintf("✗ Score %d is below the threshold of %d", score, threshold))
+	}
+
+	err := checkTemplate.Execute(r.w, struct {
+		Title           string
+		Elements        []string
+		Missing         bool
+		MissingTitle    string
+		ComponentsTitle string
+		Components      []string
+		Summary         string
+	}{
+		Title:           bold.Render(fmt.Sprintf("NTIA minimum elements of %s", filename)),
+		Elements:        elements,
+		Missing:         len(res.Missing) > 0,
+		MissingTitle:    bold.Render("Missing document elements: ") + strings.Join(res.Missing, ", "),
+		ComponentsTitle: bold.Render(fmt.Sprintf("Non-conforming components (%d):", len(components))),
+		Components:      components,
+		Summary:         summary,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render check result: %w", err)
+	}
+
+	return nil
+}
+
+func componentName(c *bom.Component) string {
+	name := c.Name
+	if name == "" {
+		name = c.BOMRef
+	}
+	if c.Version == "" {
+		return name
+	}
+	return name + "@" + c.Version
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,42 @@
+package sbomcheck
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+func TestRenderer_RenderResult(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+
+	require.NoError(t, r.RenderResult("bom.json", bom.CheckNTIA(&bom.Document{
+		Metadata: bom.Metadata{
+			Supplier:  "ACME",
+			Component: &bom.Component{BOMRef: "app", Name: "app", Version: "1.0.0", PURL: "pkg:npm/app@1.0.0"},
+		},
+		Components: []*bom.Component{
+			{BOMRef: "express", Name: "express", Version: "4.4.0", PURL: "pkg:npm/express@4.4.0"},
+			{BOMRef: "util", Name: "util"},
+		},
+		Dependencies: []*bom.Dependency{{Ref: "app", DependsOn: []string{"express"}}},
+	}), 100))
+
+	snapshotter.SnapshotT(t, buf.String())
+}
+
+func TestRenderer_RenderResult_Conforms(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+
+	res := &bom.NTIAResult{Elements: []*bom.ElementCoverage{
+		{Element: bom.NTIASupplier, Present: 1, Total: 1},
+		{Element: bom.NTIAAuthor, Present: 1, Total: 1},
+	}}
+	require.NoError(t, r.RenderResult("bom.json", res, 80))
+
+	snapshotter.SnapshotT(t, buf.String())
+}
@@ -0,0 +1,9 @@
+package sbomcheck
+
+import "github.com/charmbracelet/lipgloss"
+
+var (
+	bold      = lipgloss.NewStyle().Bold(true)
+	passStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	failStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
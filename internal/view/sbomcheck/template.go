@@ -0,0 +1,23 @@
+package sbomcheck
+
+import (
+	"text/template"
+)
+
+var checkTemplate *template.Template = template.Must(
+	template.New("sbomCheck").Parse(
+		`{{ .Title }}
+
+{{ range .Elements }}  {{ . }}
+{{ end }}
+{{ if .Missing -}}
+{{ .MissingTitle }}
+
+{{ end -}}
+{{ if .Components -}}
+{{ .ComponentsTitle }}
+{{ range .Components }}  {{ . }}
+{{ end }}
+{{ end -}}
+{{ .Summary }}
+`))
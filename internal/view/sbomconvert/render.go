@@ -0,0 +1,45 @@
+package sbomconvert
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+func NewRenderer(w io.Writer) *Renderer {
+	return &Renderer{
+		w: w,
+	}
+}
+
+type Renderer struct {
+	w io.Writer
+}
+
+// RenderWarnings renders the information lost when converting a document to
+// the given format. Nothing is rendered if there are no warnings.
+func (r *Renderer) RenderWarnings(format string, warnings []bom.Warning) error {
+	return r.render(fmt.Sprintf("Some information cannot be represented in %s:", format), warnings)
+}
+
+// RenderDroppedFields renders the fields of the input document that are not
+// supported and therefore dropped. Nothing is rendered if there are none.
+func (r *Renderer) RenderDroppedFields(filename string, warnings []bom.Warning) error {
+	return r.render(fmt.Sprintf("Some information in %s is not supported and was dropped:", filename), warnings)
+}
+
+func (r *Renderer) render(title string, warnings []bom.Warning) error {
+	err := warningsTemplate.Execute(r.w, struct {
+		Title    string
+		Warnings []bom.Warning
+	}{
+		Title:    bold.Render(title),
+		Warnings: warnings,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render warnings: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package sbomconvert
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+func TestRenderer_RenderWarnings(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+
+	require.NoError(t, r.RenderWarnings("spdx2.3+json", []bom.Warning{
+		{BOMRef: "express@4.4.0", Msg: "SPDX has no component properties, 2 properties were dropped"},
+		{Msg: `SPDX records a single tool vendor, the vendor "Acme" of scanner-1.0.0 was dropped`},
+	}))
+
+	snapshotter.SnapshotT(t, buf.String())
+}
+
+func TestRenderer_RenderWarnings_NoWarnings(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+
+	require.NoError(t, r.RenderWarnings("spdx2.3+json", nil))
+
+	assert.Empty(t, buf.String())
+}
+
+func TestRenderer_RenderDroppedFields(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+
+	require.NoError(t, r.RenderDroppedFields("bom.cdx.xml", []bom.Warning{
+		{Msg: "field components[].scope is not supported and was dropped (2 occurrences)"},
+		{Msg: "field services is not supported and was dropped"},
+	}))
+
+	snapshotter.SnapshotT(t, buf.String())
+}
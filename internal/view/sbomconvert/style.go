@@ -0,0 +1,5 @@
+package sbomconvert
+
+import "github.com/charmbracelet/lipgloss"
+
+var bold = lipgloss.NewStyle().Bold(true)
@@ -0,0 +1,17 @@
+package sbomconvert
+
+import (
+	"text/template"
+)
+
+var warningsTemplate *template.Template = template.Must(
+	template.New("sbomConvertWarnings").Parse(
+		`{{ if .Warnings -}}
+{{ .Title }}
+
+{{ range .Warnings -}}
+WARNING: {{ .Msg -}}
+{{ if .BOMRef }} ({{ .BOMRef }}){{ end }}
+{{ end }}
+{{ end -}}
+`))
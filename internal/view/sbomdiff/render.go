@@ -0,0 +1,69 @@
+package sbomdiff
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+func NewRenderer(w io.Writer) *Renderer {
+	return &Renderer{
+		w: w,
+	}
+}
+
+type Renderer struct {
+	w io.Writer
+}
+
+// RenderDiff renders the components added, removed and changed between the
+// documents in the files from and to.
+func (r *Renderer) RenderDiff(from, to string, d *bom.Diff) error {
+	added := make([]string, 0, len(d.Added))
+	for _, c := range d.Added {
+		added = append(added, addedStyle.Render("+ "+componentName(c)))
+	}
+	removed := make([]string, 0, len(d.Removed))
+	for _, c := range d.Removed {
+		removed = append(removed, removedStyle.Render("- "+componentName(c)))
+	}
+	changed := make([]string, 0, len(d.Changed))
+	for _, c := range d.Changed {
+		changed = append(changed, changedStyle.Render(fmt.Sprintf("~ %s %s → %s", c.To.Name, c.From.Version, c.To.Version)))
+	}
+
+	err := diffTemplate.Execute(r.w, struct {
+		Title        string
+		Empty        bool
+		AddedTitle   string
+		Added        []string
+		RemovedTitle string
+		Removed      []string
+		ChangedTitle string
+		Changed      []string
+		Summary      string
+	}{
+		Title:        bold.Render(fmt.Sprintf("Comparing %s to %s", from, to)),
+		Empty:        d.Empty(),
+		AddedTitle:   bold.Render(fmt.Sprintf("Added components (%d):", len(added))),
+		Added:        added,
+		RemovedTitle: bold.Render(fmt.Sprintf("Removed components (%d):", len(removed))),
+		Removed:      removed,
+		ChangedTitle: bold.Render(fmt.Sprintf("Changed components (%d):", len(changed))),
+		Changed:      changed,
+		Summary:      fmt.Sprintf("%d added, %d removed, %d changed", len(added), len(removed), len(changed)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render diff: %w", err)
+	}
+
+	return nil
+}
+
+func componentName(c *bom.Component) string {
+	if c.Version == "" {
+		return c.Name
+	}
+	return c.Name + "@" + c.Version
+}
@@ -0,0 +1,50 @@
+package sbomdiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+)
+
+func TestRenderer_RenderDiff(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+
+	require.NoError(t, r.RenderDiff("old.json", "new.json", &bom.Diff{
+		Added: []*bom.Component{
+			{Name: "express", Version: "4.4.0"},
+			{Name: "ws", Version: "1.0.0"},
+		},
+		Removed: []*bom.Component{
+			{Name: "util"},
+		},
+		Changed: []*bom.ComponentChange{
+			{From: &bom.Component{Name: "lodash", Version: "4.17.20"}, To: &bom.Component{Name: "lodash", Version: "4.17.21"}},
+		},
+	}))
+
+	snapshotter.SnapshotT(t, buf.String())
+}
+
+func TestRenderer_RenderDiff_AddedOnly(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+
+	require.NoError(t, r.RenderDiff("old.json", "new.json", &bom.Diff{
+		Added: []*bom.Component{{Name: "express", Version: "4.4.0"}},
+	}))
+
+	snapshotter.SnapshotT(t, buf.String())
+}
+
+func TestRenderer_RenderDiff_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+
+	require.NoError(t, r.RenderDiff("old.json", "new.json", &bom.Diff{}))
+
+	snapshotter.SnapshotT(t, buf.String())
+}
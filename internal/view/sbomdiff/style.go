@@ -0,0 +1,10 @@
+package sbomdiff
+
+import "github.com/charmbracelet/lipgloss"
+
+var (
+	bold         = lipgloss.NewStyle().Bold(true)
+	addedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	removedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	changedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+)
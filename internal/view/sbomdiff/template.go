@@ -0,0 +1,31 @@
+package sbomdiff
+
+import (
+	"text/template"
+)
+
+var diffTemplate *template.Template = template.Must(
+	template.New("sbomDiff").Parse(
+		`{{ .Title }}
+
+{{ if .Empty -}}
+No components were added, removed or changed.
+{{- else -}}
+{{ if .Added -}}
+{{ .AddedTitle }}
+{{ range .Added }}  {{ . }}
+{{ end }}
+{{ end -}}
+{{ if .Removed -}}
+{{ .RemovedTitle }}
+{{ range .Removed }}  {{ . }}
+{{ end }}
+{{ end -}}
+{{ if .Changed -}}
+{{ .ChangedTitle }}
+{{ range .Changed }}  {{ . }}
+{{ end }}
+{{ end -}}
+{{ .Summary }}
+{{- end }}
+`))
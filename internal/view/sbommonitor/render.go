@@ -26,6 +26,48 @@ func (r *Renderer) RenderWarnings(warnings []*snykclient.ConversionWarning) erro
 	})
 }
 
+// RenderDocument renders a heading for the results of monitoring the named
+// SBOM document, for batches of documents.
+func (r *Renderer) RenderDocument(name string) error {
+	err := documentTemplate.Execute(r.w, struct {
+		Title         string
+		RenderDivider bool
+	}{
+		Title:         bold.Render(fmt.Sprintf("SBOM document '%s'", name)),
+		RenderDivider: r.renderDivier,
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to render document: %w", err)
+	}
+
+	r.renderDivier = false
+
+	return nil
+}
+
+// RenderConversionError renders the error converting an SBOM document to
+// dep-graphs, in place of its monitored projects.
+func (r *Renderer) RenderConversionError(cerr error) error {
+	err := conversionErrorTemplate.Execute(r.w, struct {
+		RenderDivider bool
+		Error         error
+		ErrorTitle    string
+	}{
+		RenderDivider: r.renderDivier,
+		Error:         cerr,
+		ErrorTitle:    bold.Render("Error"),
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to render conversion error: %w", err)
+	}
+
+	r.renderDivier = true
+
+	return nil
+}
+
 func (r *Renderer) RenderMonitor(m *snykclient.MonitorDependenciesResponse, merr error) error {
 	var title string
 	var uri string
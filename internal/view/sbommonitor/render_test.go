@@ -126,3 +126,38 @@ func TestRenderer_RenderMonitor_WithError(t *testing.T) {
 
 	snapshotter.SnapshotT(t, out)
 }
+
+func TestRenderer_RenderDocument(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+
+	require.NoError(t, r.RenderDocument("sboms/a.json"))
+	require.NoError(t, r.RenderMonitor(
+		&snykclient.MonitorDependenciesResponse{
+			ProjectName: "Test Project",
+			URI:         "https://example.com/test_project"}, nil))
+	require.NoError(t, r.RenderDocument("sboms/b.json"))
+	require.NoError(t, r.RenderMonitor(
+		&snykclient.MonitorDependenciesResponse{
+			ProjectName: "A Different Project",
+			URI:         "https://example.com/different_project"}, nil))
+
+	out := buf.String()
+
+	assert.Contains(t, out, "SBOM document 'sboms/a.json'")
+	assert.Contains(t, out, "SBOM document 'sboms/b.json'")
+	snapshotter.SnapshotT(t, out)
+}
+
+func TestRenderer_RenderConversionError(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+
+	require.NoError(t, r.RenderDocument("sboms/a.json"))
+	require.NoError(t, r.RenderConversionError(errors.New("no supported projects")))
+
+	out := buf.String()
+
+	assert.Contains(t, out, "no supported projects")
+	snapshotter.SnapshotT(t, out)
+}
@@ -42,3 +42,28 @@ Notifications about newly disclosed issues related to these dependencies will be
 
 {{- end }}
 `))
+
+var conversionErrorTemplate *template.Template = template.Must(
+	template.New("sbomMonitorConversionError").Parse(
+		`{{ if .RenderDivider }}
+─────────────────────────────────────────────────────
+
+{{ end -}}
+
+{{ .ErrorTitle }}
+
+An error occurred while attempting to convert the SBOM document.
+
+Details:
+	{{ .Error }}
+`))
+
+var documentTemplate *template.Template = template.Must(
+	template.New("sbomMonitorDocument").Parse(
+		`{{ if .RenderDivider }}
+═════════════════════════════════════════════════════
+
+{{ end -}}
+{{ .Title }}
+
+`))
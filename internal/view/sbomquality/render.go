@@ -0,0 +1,74 @@
+package sbomquality
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/sbom"
+)
+
+func NewRenderer(w io.Writer) *Renderer {
+	return &Renderer{
+		w: w,
+	}
+}
+
+type Renderer struct {
+	w io.Writer
+}
+
+// RenderReport renders the quality report of a document: the score of each
+// category, along with the counts it is based on, and the overall score.
+func (r *Renderer) RenderReport(report *sbom.QualityReport) error {
+	categories := make([]string, 0, len(report.Categories))
+	for _, c := range report.Categories {
+		line := fmt.Sprintf("%-32s %3d   %s", label(c.Name), c.Score, detail(c, report.Violations))
+		switch {
+		case c.Score < 50:
+			line = failStyle.Render(line)
+		case c.Score < 100:
+			line = warnStyle.Render(line)
+		}
+		categories = append(categories, line)
+	}
+
+	summary := bold.Render(fmt.Sprintf("Quality score: %d", report.Score))
+	if !slices.ContainsFunc(report.Categories, func(c *sbom.QualityCategory) bool { return c.Name == sbom.QualitySpecCompliance }) {
+		summary += "\nSpec compliance was not scored, as the document cannot be validated against a schema."
+	}
+
+	err := qualityTemplate.Execute(r.w, struct {
+		Title      string
+		Categories []string
+		Summary    string
+	}{
+		Title:      bold.Render(fmt.Sprintf("SBOM quality of %s (%s)", report.Name, report.Format)),
+		Categories: categories,
+		Summary:    summary,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render quality report: %w", err)
+	}
+
+	return nil
+}
+
+func label(category string) string {
+	if category == bom.QualityIdentifiers {
+		return "Identifier coverage (purl/CPE)"
+	}
+	return strings.ToUpper(category[:1]) + category[1:]
+}
+
+func detail(c *sbom.QualityCategory, violations int) string {
+	if c.Name != sbom.QualitySpecCompliance {
+		return fmt.Sprintf("%d/%d components", c.Present, c.Total)
+	}
+	if violations == 0 {
+		return "valid"
+	}
+	return fmt.Sprintf("%d schema violation(s), run `snyk sbom validate` to list them", violations)
+}
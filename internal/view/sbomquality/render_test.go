@@ -0,0 +1,50 @@
+package sbomquality
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-sbom/internal/bom"
+	"github.com/snyk/cli-extension-sbom/internal/sbom"
+)
+
+func TestRenderer_RenderReport(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+
+	require.NoError(t, r.RenderReport(&sbom.QualityReport{
+		Name:   "bom.json",
+		Format: sbom.Format{Standard: "CycloneDX", SpecVersion: "1.6"},
+		Score:  56,
+		Categories: []*sbom.QualityCategory{
+			{Name: bom.QualityIdentifiers, Score: 100, Present: 4, Total: 4},
+			{Name: bom.QualityLicenses, Score: 75, Present: 3, Total: 4},
+			{Name: bom.QualityHashes, Score: 0, Present: 0, Total: 4},
+			{Name: bom.QualityDependencies, Score: 100, Present: 4, Total: 4},
+			{Name: bom.QualityVersions, Score: 100, Present: 4, Total: 4},
+			{Name: sbom.QualitySpecCompliance, Score: 0, Present: 0, Total: 1},
+		},
+		Violations: 2,
+	}))
+
+	snapshotter.SnapshotT(t, buf.String())
+}
+
+func TestRenderer_RenderReport_NoSchema(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRenderer(&buf)
+
+	require.NoError(t, r.RenderReport(&sbom.QualityReport{
+		Name:   "bom.xml",
+		Format: sbom.Format{Standard: "CycloneDX", SpecVersion: "1.6"},
+		Score:  100,
+		Categories: []*sbom.QualityCategory{
+			{Name: bom.QualityIdentifiers, Score: 100, Present: 1, Total: 1},
+			{Name: bom.QualityVersions, Score: 100, Present: 1, Total: 1},
+		},
+	}))
+
+	snapshotter.SnapshotT(t, buf.String())
+}
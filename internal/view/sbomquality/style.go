@@ -0,0 +1,9 @@
+package sbomquality
+
+import "github.com/charmbracelet/lipgloss"
+
+var (
+	bold      = lipgloss.NewStyle().Bold(true)
+	warnStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	failStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
@@ -0,0 +1,14 @@
+package sbomquality
+
+import (
+	"text/template"
+)
+
+var qualityTemplate *template.Template = template.Must(
+	template.New("sbomQuality").Parse(
+		`{{ .Title }}
+
+{{ range .Categories }}  {{ . }}
+{{ end }}
+{{ .Summary }}
+`))
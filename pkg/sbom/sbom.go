@@ -3,9 +3,15 @@ package sbom
 import (
 	"github.com/snyk/go-application-framework/pkg/workflow"
 
+	"github.com/snyk/cli-extension-sbom/internal/commands/sbomcheck"
+	"github.com/snyk/cli-extension-sbom/internal/commands/sbomconvert"
 	"github.com/snyk/cli-extension-sbom/internal/commands/sbomcreate"
+	"github.com/snyk/cli-extension-sbom/internal/commands/sbomdiff"
+	"github.com/snyk/cli-extension-sbom/internal/commands/sbommerge"
 	"github.com/snyk/cli-extension-sbom/internal/commands/sbommonitor"
+	"github.com/snyk/cli-extension-sbom/internal/commands/sbomquality"
 	"github.com/snyk/cli-extension-sbom/internal/commands/sbomtest"
+	"github.com/snyk/cli-extension-sbom/internal/commands/sbomvalidate"
 )
 
 func Init(e workflow.Engine) error {
@@ -25,5 +31,35 @@ func Init(e workflow.Engine) error {
 		return err
 	}
 
+	// Register the "sbom validate" command
+	if err := sbomvalidate.RegisterWorkflows(e); err != nil {
+		return err
+	}
+
+	// Register the "sbom merge" command
+	if err := sbommerge.RegisterWorkflows(e); err != nil {
+		return err
+	}
+
+	// Register the "sbom diff" command
+	if err := sbomdiff.RegisterWorkflows(e); err != nil {
+		return err
+	}
+
+	// Register the "sbom convert" command
+	if err := sbomconvert.RegisterWorkflows(e); err != nil {
+		return err
+	}
+
+	// Register the "sbom check" command
+	if err := sbomcheck.RegisterWorkflows(e); err != nil {
+		return err
+	}
+
+	// Register the "sbom quality" command
+	if err := sbomquality.RegisterWorkflows(e); err != nil {
+		return err
+	}
+
 	return nil
 }
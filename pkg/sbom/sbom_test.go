@@ -8,8 +8,14 @@ import (
 	"github.com/snyk/go-application-framework/pkg/workflow"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/snyk/cli-extension-sbom/internal/commands/sbomcheck"
+	"github.com/snyk/cli-extension-sbom/internal/commands/sbomconvert"
 	"github.com/snyk/cli-extension-sbom/internal/commands/sbomcreate"
+	"github.com/snyk/cli-extension-sbom/internal/commands/sbomdiff"
+	"github.com/snyk/cli-extension-sbom/internal/commands/sbommerge"
+	"github.com/snyk/cli-extension-sbom/internal/commands/sbomquality"
 	"github.com/snyk/cli-extension-sbom/internal/commands/sbomtest"
+	"github.com/snyk/cli-extension-sbom/internal/commands/sbomvalidate"
 	"github.com/snyk/cli-extension-sbom/pkg/sbom"
 )
 
@@ -25,6 +31,12 @@ func TestInit(t *testing.T) {
 
 	assertWorkflowExists(t, e, sbomcreate.WorkflowID)
 	assertWorkflowExists(t, e, sbomtest.WorkflowID)
+	assertWorkflowExists(t, e, sbomvalidate.WorkflowID)
+	assertWorkflowExists(t, e, sbommerge.WorkflowID)
+	assertWorkflowExists(t, e, sbomdiff.WorkflowID)
+	assertWorkflowExists(t, e, sbomconvert.WorkflowID)
+	assertWorkflowExists(t, e, sbomcheck.WorkflowID)
+	assertWorkflowExists(t, e, sbomquality.WorkflowID)
 }
 
 func assertWorkflowExists(t *testing.T, e workflow.Engine, id *url.URL) {